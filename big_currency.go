@@ -0,0 +1,920 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                               zr/[big_currency.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # BigCurrency Type:
+//   BigCurrency struct
+//
+// # BigCurrency Factories:
+//   BigCurrencyE(value interface{}) (BigCurrency, error)
+//   BigCurrencyOf(value interface{}) BigCurrency
+//   BigCurrencyOfS(s string) BigCurrency
+//   BigCurrencyRaw(x *big.Int) BigCurrency
+//
+// # Conversion to Currency:
+//   (n BigCurrency) Currency() Currency
+//   (n BigCurrency) CurrencyE() (Currency, error)
+//
+// # String Output:
+//   (n BigCurrency) GoString() string
+//   (n BigCurrency) Fmt(decimalPlaces int) string
+//   (n BigCurrency) InWordsEN(fmt string, opts ...InWordsOptions) string
+//   (n BigCurrency) String() string
+//
+// # Rounding:
+//   (n BigCurrency) Round(decimalPlaces int, mode RoundingMode) BigCurrency
+//
+// # Allocation:
+//   (n BigCurrency) Allocate(ratios ...int) []BigCurrency
+//   (n BigCurrency) AllocateAt(decimalPlaces int, ratios ...int) []BigCurrency
+//   (n BigCurrency) Split(parts int) []BigCurrency
+//
+// # Division:
+//   (n BigCurrency) Div(nums ...BigCurrency) BigCurrency
+//   (n BigCurrency) DivE(nums ...BigCurrency) (BigCurrency, error)
+//   (n BigCurrency) DivFloat(nums ...float64) BigCurrency
+//   (n BigCurrency) DivFloatRound(
+//       mode RoundingMode, nums ...float64,
+//   ) BigCurrency
+//   (n BigCurrency) DivInt(nums ...int) BigCurrency
+//   (n BigCurrency) DivRound(
+//       mode RoundingMode, nums ...BigCurrency,
+//   ) BigCurrency
+//
+// # Multiplication:
+//   (n BigCurrency) Mul(nums ...BigCurrency) BigCurrency
+//   (n BigCurrency) MulFloat(nums ...float64) BigCurrency
+//   (n BigCurrency) MulFloatRound(
+//       mode RoundingMode, nums ...float64,
+//   ) BigCurrency
+//   (n BigCurrency) MulInt(nums ...int) BigCurrency
+//   (n BigCurrency) MulRound(
+//       mode RoundingMode, nums ...BigCurrency,
+//   ) BigCurrency
+//
+// # Addition:
+//   (n BigCurrency) Add(nums ...BigCurrency) BigCurrency
+//   (n BigCurrency) AddFloat(nums ...float64) BigCurrency
+//   (n BigCurrency) AddInt(nums ...int) BigCurrency
+//
+// # Subtraction:
+//   (n BigCurrency) Sub(nums ...BigCurrency) BigCurrency
+//   (n BigCurrency) SubFloat(nums ...float64) BigCurrency
+//   (n BigCurrency) SubInt(nums ...int) BigCurrency
+//
+// # Information:
+//   (n BigCurrency) Cmp(other BigCurrency) int
+//   (n BigCurrency) Float64() float64
+//   (n BigCurrency) Int() *big.Int
+//   (n BigCurrency) Int64() int64
+//   (n BigCurrency) IsEqual(value interface{}) bool
+//   (n BigCurrency) IsGreater(value interface{}) bool
+//   (n BigCurrency) IsGreaterOrEqual(value interface{}) bool
+//   (n BigCurrency) IsLesser(value interface{}) bool
+//   (n BigCurrency) IsLesserOrEqual(value interface{}) bool
+//   (n BigCurrency) IsNegative() bool
+//   (n BigCurrency) IsZero() bool
+//   (n BigCurrency) Raw() *big.Int
+//   (n BigCurrency) Sign() int
+//
+// # JSON:
+//   (n BigCurrency) MarshalJSON() ([]byte, error)
+//   (n *BigCurrency) UnmarshalJSON(data []byte) error
+//
+// # Text and Binary Encoding:
+//   (n BigCurrency) MarshalBinary() ([]byte, error)
+//   (n BigCurrency) MarshalText() ([]byte, error)
+//   (n *BigCurrency) UnmarshalBinary(data []byte) error
+//   (n *BigCurrency) UnmarshalText(text []byte) error
+//
+// # Helper Functions
+//   bigCurrencyDiv(a, b BigCurrency, mode RoundingMode) (BigCurrency, error)
+//   bigCurrencyOfFloat(num float64) (BigCurrency, error)
+//   bigCurrencyParse(s string) (BigCurrency, error)
+//   bigCurrencyPow10(exp int) *big.Int
+//   (n BigCurrency) raw() *big.Int
+
+import (
+	"encoding/json" // json.Unmarshal is used via mod.json.* (mockable)
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// bigCurrencyMaxDigits is the maximum number of digits before the
+// decimal point of a BigCurrency read from a string, so that untrusted
+// input like "1e1000000" can't create huge numbers.
+const bigCurrencyMaxDigits = 300
+
+// bigCurrencyMaxParsed is 10^(bigCurrencyMaxDigits+4), the smallest
+// internal value with more than bigCurrencyMaxDigits integer digits.
+var bigCurrencyMaxParsed = bigCurrencyPow10(bigCurrencyMaxDigits + 4)
+
+// -----------------------------------------------------------------------------
+// # BigCurrency Type:
+
+// BigCurrency represents a currency value with up to four decimal places,
+// like Currency, but without a size limit. Use it for totals that can
+// exceed CurrencyIntLimit, e.g. when adding up amounts in currencies
+// with a low unit value over long periods.
+//
+// It is stored internally as a big.Int scaled by 10^4. BigCurrency values
+// are immutable: methods always return new values, so they can be copied
+// and shared freely. The zero value is zero.
+//
+// Since it can't overflow, the only arithmetic errors are divisions
+// by zero and floating-point numbers that are NaN or infinite.
+// Numbers read from strings, JSON or text can have at most 300
+// digits before the decimal point: longer numbers give an error
+// starting with EOverflow.
+type BigCurrency struct {
+	i *big.Int // nil means zero
+} //                                                                 BigCurrency
+
+// -----------------------------------------------------------------------------
+// # BigCurrency Factories:
+
+// BigCurrencyE converts any compatible value to a BigCurrency.
+// This includes simple numeric types, strings, Currency and *big.Int.
+//
+// - Dereferences pointers to evaluate the pointed-to type.
+// - Converts nil to 0.
+// - Converts signed and unsigned integers, and floats to BigCurrency.
+// - Converts numeric strings and json.Number to BigCurrency.
+// - Converts boolean true to 1, false to 0.
+//
+// Strings and floating-point numbers are converted using their exact
+// decimal representation. Decimals beyond the 4th decimal place
+// are rounded as specified by SetCurrencyRounding().
+//
+// A Currency is converted without any loss. If it holds an
+// overflow value, returns zero and an error.
+//
+// If the value can not be converted to BigCurrency, returns
+// zero and an error. Does not log the error.
+func BigCurrencyE(value interface{}) (BigCurrency, error) {
+	switch v := value.(type) {
+	case int, int64, int32, int16, int8:
+		{
+			x := big.NewInt(reflect.ValueOf(value).Int())
+			return BigCurrency{x.Mul(x, big1E4)}, nil
+		}
+	case uint, uint64, uint32, uint16, uint8:
+		{
+			x := new(big.Int).SetUint64(reflect.ValueOf(value).Uint())
+			return BigCurrency{x.Mul(x, big1E4)}, nil
+		}
+	case float64, float32:
+		{
+			n := reflect.ValueOf(value).Float()
+			if math.IsNaN(n) || math.IsInf(n, 0) {
+				return BigCurrency{}, fmt.Errorf("%s: %v", EInvalidArg, n)
+			}
+			bitSize := 64
+			if _, ok := value.(float32); ok {
+				bitSize = 32
+			}
+			return bigCurrencyParse(strconv.FormatFloat(n, 'f', -1, bitSize))
+		}
+	case string, json.Number:
+		return bigCurrencyParse(reflect.ValueOf(v).String())
+	case Currency:
+		{
+			if v.IsOverflow() {
+				return BigCurrency{}, errCurrencyOverflowOperand
+			}
+			return BigCurrency{big.NewInt(v.i64)}, nil
+		}
+	case BigCurrency:
+		return v, nil
+	case *big.Int:
+		{
+			if v == nil {
+				return BigCurrency{}, nil
+			}
+			return BigCurrency{new(big.Int).Mul(v, big1E4)}, nil
+		}
+	case bool:
+		{
+			if v {
+				return BigCurrency{big.NewInt(1e4)}, nil
+			}
+			return BigCurrency{}, nil
+		}
+	case nil:
+		return BigCurrency{}, nil
+	}
+	// if not converted yet, try to dereference pointer, then convert
+	xv := reflect.ValueOf(value)
+	if xv.Kind() == reflect.Ptr {
+		if xv.IsNil() {
+			return BigCurrency{}, nil
+		}
+		return BigCurrencyE(xv.Elem().Interface())
+	}
+	return BigCurrency{}, fmt.Errorf("Can not convert %s to BigCurrency: %v",
+		reflect.TypeOf(value), value)
+} //                                                                BigCurrencyE
+
+// BigCurrencyOf converts any compatible value type to a BigCurrency.
+// See BigCurrencyE() for the types that can be converted.
+//
+// If the value can not be converted to BigCurrency, returns
+// zero and logs an error (when logging is active).
+func BigCurrencyOf(value interface{}) BigCurrency {
+	n, err := BigCurrencyE(value)
+	if err != nil {
+		mod.Error(err)
+	}
+	return n
+} //                                                               BigCurrencyOf
+
+// BigCurrencyOfS converts a numeric string to a BigCurrency.
+//
+// The string is parsed exactly, like CurrencyOfS(). Decimals beyond
+// the 4th decimal place are rounded as specified by SetCurrencyRounding().
+// If the string is not numeric, logs an error and returns zero.
+func BigCurrencyOfS(s string) BigCurrency {
+	ret, err := bigCurrencyParse(s)
+	if err != nil {
+		mod.Error("Non-numeric string:^", s, ":", err)
+	}
+	return ret
+} //                                                              BigCurrencyOfS
+
+// BigCurrencyRaw initializes a currency value from an integer scaled
+// by 10^4: the decimal point is moved left 4 decimal places.
+// For example, 15500 results in a currency value of 1.55
+// x is copied, so it can be changed later without affecting the result.
+func BigCurrencyRaw(x *big.Int) BigCurrency {
+	if x == nil {
+		return BigCurrency{}
+	}
+	return BigCurrency{new(big.Int).Set(x)}
+} //                                                              BigCurrencyRaw
+
+// -----------------------------------------------------------------------------
+// # Conversion to Currency:
+
+// Currency converts the value to Currency without any loss.
+//
+// If the value is too big to be stored in Currency, logs an error
+// and returns math.MinInt64 or math.MaxInt64 depending on if the
+// value is negative. See CurrencyE() to get an error instead.
+func (n BigCurrency) Currency() Currency {
+	ret, err := n.CurrencyE()
+	currencyLogError(err)
+	return ret
+} //                                                                    Currency
+
+// CurrencyE converts the value to Currency without any loss, or returns
+// an overflow value and an error if the value is too big to be stored
+// in Currency. Does not log errors.
+func (n BigCurrency) CurrencyE() (Currency, error) {
+	return currencyOfBigE(n.raw(), n)
+} //                                                                   CurrencyE
+
+// -----------------------------------------------------------------------------
+// # String Output:
+
+// GoString outputs the value as a Go language string,
+// It implements the fmt.GoStringer interface.
+func (n BigCurrency) GoString() string {
+	return "zr.BigCurrencyOf(" + strconv.Quote(n.String()) + ")"
+} //                                                                    GoString
+
+// Fmt returns the currency value as a a string delimited with commas
+// (grouped every three digits) and having the specified number of
+// decimal places, like Currency.Fmt(). When decimalPlaces is
+// negative, the resulting number's decimals will vary.
+func (n BigCurrency) Fmt(decimalPlaces int) string {
+	var (
+		x      = n.raw()
+		digits = new(big.Int).Abs(x).String()
+		sb     strings.Builder
+	)
+	if len(digits) < 5 {
+		digits = strings.Repeat("0", 5-len(digits)) + digits
+	}
+	intPart, decPart := digits[:len(digits)-4], digits[len(digits)-4:]
+	if x.Sign() < 0 {
+		sb.WriteString("-")
+	}
+	for i, ch := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteRune(ch)
+	}
+	switch {
+	case decimalPlaces < 0:
+		if decPart = strings.TrimRight(decPart, "0"); decPart != "" {
+			sb.WriteString("." + decPart)
+		}
+	case decimalPlaces > 4:
+		sb.WriteString("." + decPart + strings.Repeat("0", decimalPlaces-4))
+	case decimalPlaces > 0:
+		sb.WriteString("." + decPart[:decimalPlaces])
+	}
+	return sb.String()
+} //                                                                         Fmt
+
+// InWordsEN returns the currency value as an English description in
+// words. See Currency.InWordsEN() for a description of the arguments.
+func (n BigCurrency) InWordsEN(fmt string, opts ...InWordsOptions) string {
+	var (
+		bigUnits = new(big.Int)
+		smlUnits = new(big.Int)
+	)
+	bigUnits.QuoRem(new(big.Int).Abs(n.raw()), big1E4, smlUnits)
+	return amountInWordsEN(bigUnits, smlUnits.Int64()/100, fmt, opts)
+} //                                                                   InWordsEN
+
+// String returns a string representing the currency value
+// and implements the fmt.Stringer interface.
+func (n BigCurrency) String() string {
+	return strings.ReplaceAll(n.Fmt(-1), ",", "")
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Rounding:
+
+// Round rounds the currency value to the specified number of decimal
+// places using the given rounding mode, and returns the result.
+// The object's value isn't changed.
+//
+// decimalPlaces can be 4 (no change) or less. Negative values
+// round to tens (-1), hundreds (-2), thousands (-3) and so on.
+func (n BigCurrency) Round(decimalPlaces int, mode RoundingMode) BigCurrency {
+	if decimalPlaces >= 4 {
+		return n
+	}
+	unit := bigCurrencyPow10(4 - decimalPlaces)
+	x := roundQuo(n.raw(), unit, mode)
+	return BigCurrency{x.Mul(x, unit)}
+} //                                                                       Round
+
+// -----------------------------------------------------------------------------
+// # Allocation:
+
+// Allocate distributes the currency value into parts proportional to
+// the given ratios, without losing any fractions, like Currency.Allocate().
+// The returned parts always add up exactly to the original value.
+func (n BigCurrency) Allocate(ratios ...int) []BigCurrency {
+	return n.AllocateAt(4, ratios...)
+} //                                                                    Allocate
+
+// AllocateAt distributes the currency value into parts proportional to
+// the given ratios, like Currency.AllocateAt(), but each part is a
+// multiple of the unit specified by decimalPlaces. The returned
+// parts always add up exactly to the original value.
+//
+// Ratios must not be negative and at least one must be greater
+// than zero, otherwise logs an error and returns nil.
+func (n BigCurrency) AllocateAt(
+	decimalPlaces int, ratios ...int,
+) []BigCurrency {
+	if decimalPlaces > 4 {
+		decimalPlaces = 4
+	}
+	total := int64(0)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			mod.Error(EInvalidArg, "negative ^ratio", ":", ratio)
+			return nil
+		}
+		total += int64(ratio)
+	}
+	if total == 0 {
+		mod.Error(EInvalidArg, "^ratios", ":", ratios)
+		return nil
+	}
+	var (
+		unit  = bigCurrencyPow10(4 - decimalPlaces)
+		units = new(big.Int) // whole units to allocate
+		rest  = new(big.Int) // excess fraction of a unit
+		left  = new(big.Int)
+		parts = make([]*big.Int, len(ratios))
+		bigT  = big.NewInt(total)
+	)
+	units.QuoRem(n.raw(), unit, rest)
+	left.Set(units)
+	for i, ratio := range ratios {
+		parts[i] = new(big.Int).Mul(units, big.NewInt(int64(ratio)))
+		parts[i].Quo(parts[i], bigT)
+		left.Sub(left, parts[i])
+	}
+	// distribute the remaining units (fewer than the number
+	// of ratios) one at a time
+	step := big.NewInt(int64(left.Sign()))
+	for i := 0; left.Sign() != 0; i = (i + 1) % len(ratios) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Add(parts[i], step)
+		left.Sub(left, step)
+	}
+	ret := make([]BigCurrency, len(ratios))
+	for i, ratio := range ratios {
+		parts[i].Mul(parts[i], unit)
+		if rest.Sign() != 0 && ratio != 0 {
+			parts[i].Add(parts[i], rest)
+			rest.SetInt64(0)
+		}
+		ret[i] = BigCurrency{parts[i]}
+	}
+	return ret
+} //                                                                  AllocateAt
+
+// Split divides the currency value into the specified number of equal
+// parts, without losing any fractions, like Currency.Split().
+// If parts is less than 1, logs an error and returns nil.
+func (n BigCurrency) Split(parts int) []BigCurrency {
+	if parts < 1 {
+		mod.Error(EInvalidArg, "^parts", ":", parts)
+		return nil
+	}
+	ratios := make([]int, parts)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return n.AllocateAt(4, ratios...)
+} //                                                                       Split
+
+// -----------------------------------------------------------------------------
+// # Division:
+
+// Div divides a currency object by one or more currency values
+// and returns the result. The object's value isn't changed.
+// The result is truncated to 4 decimal places.
+//
+// If a divisor is zero, logs an error and returns zero.
+// See DivE() for a version that returns an error instead of logging it.
+func (n BigCurrency) Div(nums ...BigCurrency) BigCurrency {
+	ret, err := n.DivE(nums...)
+	currencyLogError(err)
+	return ret
+} //                                                                         Div
+
+// DivE divides a currency object by one or more currency values and
+// returns the result truncated to 4 decimal places, or zero and an
+// error if a divisor is zero. Does not log errors.
+// The object's value isn't changed.
+func (n BigCurrency) DivE(nums ...BigCurrency) (BigCurrency, error) {
+	var err error
+	for _, num := range nums {
+		n, err = bigCurrencyDiv(n, num, RoundDown)
+		if err != nil {
+			break
+		}
+	}
+	return n, err
+} //                                                                        DivE
+
+// DivFloat divides a currency object by one or more floating-point
+// numbers and returns the result. The object's value isn't changed.
+// Each number is first truncated to 4 decimal places.
+//
+// If a number is zero, NaN or infinite, logs an error and returns zero.
+func (n BigCurrency) DivFloat(nums ...float64) BigCurrency {
+	for _, num := range nums {
+		b, err := bigCurrencyOfFloat(num)
+		if err == nil {
+			n, err = bigCurrencyDiv(n, b, RoundDown)
+		}
+		if err != nil {
+			currencyLogError(err)
+			return BigCurrency{}
+		}
+	}
+	return n
+} //                                                                    DivFloat
+
+// DivFloatRound divides a currency object by one or more floating-point
+// numbers and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+//
+// Each float is used with its shortest decimal representation,
+// so dividing by 1.1 divides by exactly 11/10.
+// If a number is zero, NaN or infinite, logs an error and returns zero.
+func (n BigCurrency) DivFloatRound(
+	mode RoundingMode, nums ...float64,
+) BigCurrency {
+	for _, num := range nums {
+		r := currencyFloatRat(num)
+		if r == nil || r.Sign() == 0 {
+			mod.Error(fmt.Sprintf("%s: %v / %v", EDivByZero, n, num))
+			return BigCurrency{}
+		}
+		x := new(big.Int).Mul(n.raw(), r.Denom())
+		n = BigCurrency{roundQuo(x, r.Num(), mode)}
+	}
+	return n
+} //                                                               DivFloatRound
+
+// DivInt divides a currency object by one or more integer values
+// and returns the result truncated to 4 decimal places.
+// The object's value isn't changed.
+//
+// If a number is zero, logs an error and returns zero.
+func (n BigCurrency) DivInt(nums ...int) BigCurrency {
+	for _, num := range nums {
+		if num == 0 {
+			mod.Error(fmt.Sprintf("%s: %v / 0", EDivByZero, n))
+			return BigCurrency{}
+		}
+		n = BigCurrency{new(big.Int).Quo(n.raw(), big.NewInt(int64(num)))}
+	}
+	return n
+} //                                                                      DivInt
+
+// DivRound divides a currency object by one or more currency values
+// and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+//
+// If a divisor is zero, logs an error and returns zero.
+func (n BigCurrency) DivRound(
+	mode RoundingMode, nums ...BigCurrency,
+) BigCurrency {
+	var err error
+	for _, num := range nums {
+		n, err = bigCurrencyDiv(n, num, mode)
+		if err != nil {
+			currencyLogError(err)
+			break
+		}
+	}
+	return n
+} //                                                                    DivRound
+
+// -----------------------------------------------------------------------------
+// # Multiplication:
+
+// Mul multiplies a currency object by one or more currency values
+// and returns the result. The object's value isn't changed.
+// The result is truncated to 4 decimal places.
+func (n BigCurrency) Mul(nums ...BigCurrency) BigCurrency {
+	return n.MulRound(RoundDown, nums...)
+} //                                                                         Mul
+
+// MulFloat multiplies a currency object by one or more floating-point
+// numbers and returns the result truncated to 4 decimal places.
+// The object's value isn't changed.
+//
+// Each float is used with its shortest decimal representation,
+// so multiplying by 1.1 multiplies by exactly 11/10.
+// If a number is NaN or infinite, logs an error and returns zero.
+func (n BigCurrency) MulFloat(nums ...float64) BigCurrency {
+	return n.MulFloatRound(RoundDown, nums...)
+} //                                                                    MulFloat
+
+// MulFloatRound multiplies a currency object by one or more floating-point
+// numbers and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+//
+// Each float is used with its shortest decimal representation.
+// If a number is NaN or infinite, logs an error and returns zero.
+func (n BigCurrency) MulFloatRound(
+	mode RoundingMode, nums ...float64,
+) BigCurrency {
+	for _, num := range nums {
+		r := currencyFloatRat(num)
+		if r == nil {
+			mod.Error(EInvalidArg, ":", n, " * ", num)
+			return BigCurrency{}
+		}
+		x := new(big.Int).Mul(n.raw(), r.Num())
+		n = BigCurrency{roundQuo(x, r.Denom(), mode)}
+	}
+	return n
+} //                                                               MulFloatRound
+
+// MulInt multiplies a currency object by one or more integer values
+// and returns the result. The object's value isn't changed.
+func (n BigCurrency) MulInt(nums ...int) BigCurrency {
+	for _, num := range nums {
+		n = BigCurrency{new(big.Int).Mul(n.raw(), big.NewInt(int64(num)))}
+	}
+	return n
+} //                                                                      MulInt
+
+// MulRound multiplies a currency object by one or more currency values
+// and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+func (n BigCurrency) MulRound(
+	mode RoundingMode, nums ...BigCurrency,
+) BigCurrency {
+	for _, num := range nums {
+		x := new(big.Int).Mul(n.raw(), num.raw())
+		n = BigCurrency{roundQuo(x, big1E4, mode)}
+	}
+	return n
+} //                                                                    MulRound
+
+// -----------------------------------------------------------------------------
+// # Addition:
+
+// Add adds one or more currency values and returns the result.
+// The object's value isn't changed.
+func (n BigCurrency) Add(nums ...BigCurrency) BigCurrency {
+	x := new(big.Int).Set(n.raw())
+	for _, num := range nums {
+		x.Add(x, num.raw())
+	}
+	return BigCurrency{x}
+} //                                                                         Add
+
+// AddFloat adds one or more floating-point numbers to a currency object
+// and returns the result. The object's value isn't changed.
+// Each number is first truncated to 4 decimal places.
+//
+// If a number is NaN or infinite, logs an error and skips it.
+func (n BigCurrency) AddFloat(nums ...float64) BigCurrency {
+	for _, num := range nums {
+		b, err := bigCurrencyOfFloat(num)
+		if err != nil {
+			currencyLogError(err)
+			continue
+		}
+		n = n.Add(b)
+	}
+	return n
+} //                                                                    AddFloat
+
+// AddInt adds one or more integer values to a currency object
+// and returns the result. The object's value isn't changed.
+func (n BigCurrency) AddInt(nums ...int) BigCurrency {
+	for _, num := range nums {
+		n = n.Add(BigCurrencyOf(num))
+	}
+	return n
+} //                                                                      AddInt
+
+// -----------------------------------------------------------------------------
+// # Subtraction:
+
+// Sub subtracts one or more currency values from a currency object
+// and returns the result. The object's value isn't changed.
+func (n BigCurrency) Sub(nums ...BigCurrency) BigCurrency {
+	x := new(big.Int).Set(n.raw())
+	for _, num := range nums {
+		x.Sub(x, num.raw())
+	}
+	return BigCurrency{x}
+} //                                                                         Sub
+
+// SubFloat subtracts one or more floating-point numbers from a currency
+// object and returns the result. The object's value isn't changed.
+// Each number is first truncated to 4 decimal places.
+//
+// If a number is NaN or infinite, logs an error and skips it.
+func (n BigCurrency) SubFloat(nums ...float64) BigCurrency {
+	for _, num := range nums {
+		b, err := bigCurrencyOfFloat(num)
+		if err != nil {
+			currencyLogError(err)
+			continue
+		}
+		n = n.Sub(b)
+	}
+	return n
+} //                                                                    SubFloat
+
+// SubInt subtracts one or more integer values from a currency object
+// and returns the result. The object's value isn't changed.
+func (n BigCurrency) SubInt(nums ...int) BigCurrency {
+	for _, num := range nums {
+		n = n.Sub(BigCurrencyOf(num))
+	}
+	return n
+} //                                                                      SubInt
+
+// -----------------------------------------------------------------------------
+// # Information:
+
+// Cmp compares the currency value with another one
+// and returns -1, 0 or 1 if it is lesser, equal
+// or greater than the other value.
+func (n BigCurrency) Cmp(other BigCurrency) int {
+	return n.raw().Cmp(other.raw())
+} //                                                                         Cmp
+
+// Float64 returns the currency value as the nearest float64 value.
+func (n BigCurrency) Float64() float64 {
+	ret, _ := new(big.Rat).SetFrac(n.raw(), big1E4).Float64()
+	return ret
+} //                                                                     Float64
+
+// Int returns the integer part of the currency value.
+func (n BigCurrency) Int() *big.Int {
+	return new(big.Int).Quo(n.raw(), big1E4)
+} //                                                                         Int
+
+// Int64 returns the integer part of the currency value as an int64 value.
+// The result is undefined if it doesn't fit in an int64.
+func (n BigCurrency) Int64() int64 {
+	return n.Int().Int64()
+} //                                                                       Int64
+
+// IsEqual returns true if the object is equal to value.
+func (n BigCurrency) IsEqual(value interface{}) bool {
+	return n.Cmp(BigCurrencyOf(value)) == 0
+} //                                                                     IsEqual
+
+// IsGreater returns true if the object is greater than value.
+func (n BigCurrency) IsGreater(value interface{}) bool {
+	return n.Cmp(BigCurrencyOf(value)) > 0
+} //                                                                   IsGreater
+
+// IsGreaterOrEqual returns true if the object is greater or equal to value.
+func (n BigCurrency) IsGreaterOrEqual(value interface{}) bool {
+	return n.Cmp(BigCurrencyOf(value)) >= 0
+} //                                                            IsGreaterOrEqual
+
+// IsLesser returns true if the object is lesser than value.
+func (n BigCurrency) IsLesser(value interface{}) bool {
+	return n.Cmp(BigCurrencyOf(value)) < 0
+} //                                                                    IsLesser
+
+// IsLesserOrEqual returns true if the object is lesser or equal to value.
+func (n BigCurrency) IsLesserOrEqual(value interface{}) bool {
+	return n.Cmp(BigCurrencyOf(value)) <= 0
+} //                                                             IsLesserOrEqual
+
+// IsNegative returns true if the value of the currency object is negative.
+func (n BigCurrency) IsNegative() bool {
+	return n.raw().Sign() < 0
+} //                                                                  IsNegative
+
+// IsZero returns true if the value of the currency object is zero.
+func (n BigCurrency) IsZero() bool {
+	return n.raw().Sign() == 0
+} //                                                                      IsZero
+
+// Raw returns a copy of the internal integer used to store
+// the currency value, which is the value scaled by 10^4.
+func (n BigCurrency) Raw() *big.Int {
+	return new(big.Int).Set(n.raw())
+} //                                                                         Raw
+
+// Sign returns -1, 0 or 1 if the currency
+// value is negative, zero or positive.
+func (n BigCurrency) Sign() int {
+	return n.raw().Sign()
+} //                                                                        Sign
+
+// -----------------------------------------------------------------------------
+// # JSON:
+
+// MarshalJSON returns the JSON encoding of zr.BigCurrency,
+// which is a JSON number, e.g. 12345678901234567890.1234
+func (n BigCurrency) MarshalJSON() ([]byte, error) {
+	return []byte(n.String()), nil
+} //                                                                 MarshalJSON
+
+// UnmarshalJSON unmarshals a JSON description of zr.BigCurrency.
+// This method alters the object's value.
+//
+// Accepts JSON numbers and strings containing numbers, e.g. 12.5 or "12.5".
+// The decimal text is parsed exactly, without converting it to float64.
+// Decimals beyond the 4th decimal place are rounded as specified by
+// SetCurrencyRounding(). A JSON null leaves the value unchanged.
+func (n *BigCurrency) UnmarshalJSON(data []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	var num json.Number
+	err := mod.json.Unmarshal(data, &num)
+	if err != nil {
+		return err
+	}
+	if num == "" { // null
+		return nil
+	}
+	ret, err := bigCurrencyParse(string(num))
+	if err != nil {
+		return err
+	}
+	*n = ret
+	return nil
+} //                                                               UnmarshalJSON
+
+// -----------------------------------------------------------------------------
+// # Text and Binary Encoding:
+
+// MarshalBinary encodes the currency's internal value as bytes
+// and implements the encoding.BinaryMarshaler interface.
+// This is also used by encoding/gob.
+func (n BigCurrency) MarshalBinary() ([]byte, error) {
+	return n.raw().GobEncode()
+} //                                                               MarshalBinary
+
+// MarshalText returns the currency value as text, the same as
+// String(), and implements the encoding.TextMarshaler interface.
+// This is also used by encoding/xml.
+func (n BigCurrency) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+} //                                                                 MarshalText
+
+// UnmarshalBinary decodes a value encoded by MarshalBinary() and
+// implements the encoding.BinaryUnmarshaler interface.
+// This method alters the object's value.
+func (n *BigCurrency) UnmarshalBinary(data []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	x := new(big.Int)
+	if err := x.GobDecode(data); err != nil {
+		return fmt.Errorf("%s binary BigCurrency: %v", EInvalid, err)
+	}
+	n.i = x
+	return nil
+} //                                                             UnmarshalBinary
+
+// UnmarshalText parses a numeric string into the currency value and
+// implements the encoding.TextUnmarshaler interface. Decimals beyond
+// the 4th decimal place are rounded as specified by SetCurrencyRounding().
+// This method alters the object's value.
+func (n *BigCurrency) UnmarshalText(text []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	ret, err := bigCurrencyParse(string(text))
+	if err != nil {
+		return err
+	}
+	*n = ret
+	return nil
+} //                                                               UnmarshalText
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// bigCurrencyDiv returns a / b rounded to 4 decimal places using the
+// specified rounding mode, or zero and an error if b is zero.
+// Does not log errors.
+func bigCurrencyDiv(
+	a, b BigCurrency, mode RoundingMode,
+) (BigCurrency, error) {
+	if b.IsZero() {
+		return BigCurrency{}, fmt.Errorf("%s: %v / 0", EDivByZero, a)
+	}
+	x := new(big.Int).Mul(a.raw(), big1E4)
+	return BigCurrency{roundQuo(x, b.raw(), mode)}, nil
+} //                                                              bigCurrencyDiv
+
+// bigCurrencyOfFloat converts a float to BigCurrency, truncating it to
+// 4 decimal places. Returns zero and an error if the number is NaN or
+// infinite. Does not log errors.
+func bigCurrencyOfFloat(num float64) (BigCurrency, error) {
+	r := currencyFloatRat(num)
+	if r == nil {
+		return BigCurrency{}, fmt.Errorf("%s: %v", EInvalidArg, num)
+	}
+	x := new(big.Int).Mul(r.Num(), big1E4)
+	return BigCurrency{x.Quo(x, r.Denom())}, nil
+} //                                                          bigCurrencyOfFloat
+
+// bigCurrencyParse converts a decimal string to BigCurrency exactly,
+// like currencyParse(), rounding decimals beyond the 4th decimal
+// place as specified by SetCurrencyRounding(). Returns an error
+// starting with EOverflow if the number has more than
+// bigCurrencyMaxDigits integer digits. Does not log errors.
+func bigCurrencyParse(s string) (BigCurrency, error) {
+	// a larger shift gives too many digits in any case
+	x, err := currencyParseBig(s, CurrencyRounding(), bigCurrencyMaxDigits+5)
+	if err != nil {
+		return BigCurrency{}, err
+	}
+	if x.CmpAbs(bigCurrencyMaxParsed) >= 0 {
+		return BigCurrency{}, fmt.Errorf("%s: more than %d digits in %.40q",
+			EOverflow, bigCurrencyMaxDigits, s)
+	}
+	return BigCurrency{x}, nil
+} //                                                            bigCurrencyParse
+
+// bigCurrencyPow10 returns 10 raised to the power of exp.
+func bigCurrencyPow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+} //                                                            bigCurrencyPow10
+
+// raw returns the internal value of the currency, which must not be
+// changed. Returns a new zero value for the zero BigCurrency.
+func (n BigCurrency) raw() *big.Int {
+	if n.i == nil {
+		return new(big.Int)
+	}
+	return n.i
+} //                                                                         raw
+
+// end
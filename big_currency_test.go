@@ -0,0 +1,437 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                          zr/[big_currency_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # BigCurrency Factories
+//   Test_bcur_BigCurrencyE_
+//
+// # Conversion to Currency
+//   Test_bcur_BigCurrency_CurrencyE_
+//
+// # String Output
+//   Test_bcur_BigCurrency_Fmt_
+//   Test_bcur_BigCurrency_InWordsEN_
+//
+// # Rounding and Allocation
+//   Test_bcur_BigCurrency_AllocateAt_
+//   Test_bcur_BigCurrency_Round_
+//
+// # Arithmetic
+//   Test_bcur_BigCurrency_Add_
+//   Test_bcur_BigCurrency_DivE_
+//   Test_bcur_BigCurrency_Mul_
+//
+// # Encoding
+//   Test_bcur_BigCurrency_MarshalJSON_
+//   Test_bcur_BigCurrency_MarshalBinary_
+
+//  to test all items in big_currency.go use:
+//      go test --run Test_bcur_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// bcur is a shorthand for BigCurrencyOf()
+func bcur(value interface{}) BigCurrency {
+	return BigCurrencyOf(value)
+}
+
+// -----------------------------------------------------------------------------
+// # BigCurrency Factories
+
+// go test --run Test_bcur_BigCurrencyE_
+func Test_bcur_BigCurrencyE_(t *testing.T) {
+	TBegin(t)
+	//
+	// BigCurrencyE(value interface{}) (BigCurrency, error)
+	//
+	test := func(value interface{}, expect string) {
+		got, err := BigCurrencyE(value)
+		if err != nil || got.String() != expect {
+			TFailf(t, `BigCurrencyE(%#v) returned %q, %v instead of %q`,
+				value, got.String(), err, expect)
+		}
+	}
+	test(nil, "0")
+	test(true, "1")
+	test(-12, "-12")
+	test(uint64(math.MaxUint64), "18446744073709551615")
+	test(0.29, "0.29")
+	test(float32(1.5), "1.5")
+	test("-12,345,678,901,234,567,890.12345", "-12345678901234567890.1234")
+	test("1e30", "1000000000000000000000000000000")
+	test(json.Number("0.0001"), "0.0001")
+	test(CurrencyOf("-922337203685476.9999"), "-922337203685476.9999")
+	test(big.NewInt(1e18), "1000000000000000000")
+	test(bcur(5), "5")
+	{
+		n := 7
+		test(&n, "7")
+	}
+	// errors
+	for _, value := range []interface{}{
+		"abc", math.NaN(), math.Inf(1), Currency{math.MaxInt64}, []int{1},
+	} {
+		got, err := BigCurrencyE(value)
+		TTrue(t, err != nil)
+		TTrue(t, got.IsZero())
+	}
+	// strings can have up to 300 integer digits
+	test("1e299", "1"+strings.Repeat("0", 299))
+	test("-"+strings.Repeat("9", 300)+".99999", "-"+strings.Repeat("9", 300)+
+		".9999")
+	test("1e-1000000", "0")
+	for _, s := range []string{
+		"1e300", "-1e300", "1e1000000", strings.Repeat("9", 301),
+		"0.001e304",
+	} {
+		got, err := BigCurrencyE(s)
+		if err == nil || !strings.HasPrefix(err.Error(), EOverflow) ||
+			!got.IsZero() {
+			TFailf(t, `BigCurrencyE(%.20q) returned %v, %v`, s, got, err)
+		}
+	}
+} //                                                     Test_bcur_BigCurrencyE_
+
+// -----------------------------------------------------------------------------
+// # Conversion to Currency
+
+// go test --run Test_bcur_BigCurrency_CurrencyE_
+func Test_bcur_BigCurrency_CurrencyE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) CurrencyE() (Currency, error)
+	//
+	// conversion in both directions is lossless
+	for _, n := range []Currency{
+		{0}, {1}, {-1}, {MaxCurrencyI64}, {MinCurrencyI64},
+	} {
+		got, err := BigCurrencyOf(n).CurrencyE()
+		TEqual(t, got, n)
+		TEqual(t, err, nil)
+	}
+	// values that don't fit give an overflow value
+	{
+		huge := bcur(CurrencyIntLimit).AddInt(1)
+		got, err := huge.CurrencyE()
+		TTrue(t, got.IsOverflow())
+		TEqual(t, got.Overflow(), 1)
+		TTrue(t, err != nil)
+		//
+		got, err = huge.MulInt(-1).CurrencyE()
+		TEqual(t, got.Overflow(), -1)
+		TTrue(t, err != nil)
+		//
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got = huge.Currency()
+		got2 := CurrencyOf(huge)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TTrue(t, got.IsOverflow())
+		TTrue(t, got2.IsOverflow())
+		TEqual(t, ec2-ec1, 2)
+	}
+	TEqual(t, CurrencyOf(bcur("12.5")), CurrencyOf(12.5))
+} //                                            Test_bcur_BigCurrency_CurrencyE_
+
+// -----------------------------------------------------------------------------
+// # String Output
+
+// go test --run Test_bcur_BigCurrency_Fmt_
+func Test_bcur_BigCurrency_Fmt_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) Fmt(decimalPlaces int) string
+	//
+	// the same results as Currency.Fmt()
+	for _, n := range []Currency{
+		{0}, {1}, {-5000}, {12345678}, {-1000000000}, {MaxCurrencyI64},
+	} {
+		for _, places := range []int{-1, 0, 2, 4, 6} {
+			TEqual(t, BigCurrencyOf(n).Fmt(places), n.Fmt(places))
+		}
+		TEqual(t, BigCurrencyOf(n).String(), n.String())
+	}
+	n := bcur("-12345678901234567890.125")
+	TEqual(t, n.Fmt(2), "-12,345,678,901,234,567,890.12")
+	TEqual(t, n.Fmt(-1), "-12,345,678,901,234,567,890.125")
+	TEqual(t, n.String(), "-12345678901234567890.125")
+	TEqual(t, n.GoString(), `zr.BigCurrencyOf("-12345678901234567890.125")`)
+	TEqual(t, BigCurrency{}.String(), "0")
+} //                                                  Test_bcur_BigCurrency_Fmt_
+
+// go test --run Test_bcur_BigCurrency_InWordsEN_
+func Test_bcur_BigCurrency_InWordsEN_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) InWordsEN(fmt string, opts ...InWordsOptions) string
+	//
+	TEqual(t, bcur(11.02).InWordsEN("Dollar;;Cent"),
+		CurrencyOf(11.02).InWordsEN("Dollar;;Cent"))
+	TEqual(t, bcur("1000000000000000000000.01").InWordsEN("Dollar;;Cent"),
+		"One Sextillion Dollars and One Cent")
+	TEqual(t, bcur("-2e15").InWordsEN("Rupee;;Paisa;Paise",
+		InWordsOptions{Scale: ScaleIndian}), "Twenty Crore Crore Rupees")
+} //                                            Test_bcur_BigCurrency_InWordsEN_
+
+// -----------------------------------------------------------------------------
+// # Rounding and Allocation
+
+// go test --run Test_bcur_BigCurrency_AllocateAt_
+func Test_bcur_BigCurrency_AllocateAt_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) AllocateAt(decimalPlaces int, ratios ...int) []BigCurrency
+	//
+	test := func(n BigCurrency, decimalPlaces int, ratios []int,
+		expect ...string) {
+		got := n.AllocateAt(decimalPlaces, ratios...)
+		sum := BigCurrency{}
+		var ar []string
+		for _, part := range got {
+			ar = append(ar, part.String())
+			sum = sum.Add(part)
+		}
+		TEqual(t, ar, expect)
+		TEqual(t, sum.String(), n.String())
+	}
+	test(bcur(100), 4, []int{1, 1, 1}, "33.3334", "33.3333", "33.3333")
+	test(bcur(100), 2, []int{1, 1, 1}, "33.34", "33.33", "33.33")
+	test(bcur(-100), 2, []int{1, 1, 1}, "-33.34", "-33.33", "-33.33")
+	test(bcur(10.005), 2, []int{0, 1, 1}, "0", "5.005", "5")
+	test(bcur("100000000000000000000"), 0, []int{1, 2},
+		"33333333333333333334", "66666666666666666666")
+	//
+	// the same results as Currency.AllocateAt()
+	for _, c := range []Currency{CurrencyOf(100), CurrencyOf(-7.77)} {
+		got := BigCurrencyOf(c).AllocateAt(2, 3, 1, 2)
+		for i, part := range c.AllocateAt(2, 3, 1, 2) {
+			TEqual(t, got[i].String(), part.String())
+		}
+	}
+	TEqual(t, len(bcur(1).Split(3)), 3)
+	//
+	// invalid ratios are logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, bcur(1).AllocateAt(2, 1, -1) == nil)
+	TTrue(t, bcur(1).AllocateAt(2, 0, 0) == nil)
+	TTrue(t, bcur(1).Split(0) == nil)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 3)
+} //                                           Test_bcur_BigCurrency_AllocateAt_
+
+// go test --run Test_bcur_BigCurrency_Round_
+func Test_bcur_BigCurrency_Round_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) Round(decimalPlaces int, mode RoundingMode) BigCurrency
+	//
+	test := func(s string, decimalPlaces int, mode RoundingMode,
+		expect string) {
+		got := bcur(s).Round(decimalPlaces, mode).String()
+		if got != expect {
+			TFailf(t, `%s.Round(%d, %v) returned %s instead of %s`,
+				s, decimalPlaces, mode, got, expect)
+		}
+	}
+	test("2.345", 2, RoundHalfEven, "2.34")
+	test("2.345", 2, RoundHalfUp, "2.35")
+	test("-2.345", 2, RoundHalfUp, "-2.35")
+	test("2.345", 4, RoundUp, "2.345")
+	test("12345678901234567890.5", 0, RoundHalfEven, "12345678901234567890")
+	test("15", -1, RoundHalfUp, "20")
+	test("1e25", -20, RoundHalfUp, "10000000000000000000000000")
+	test("5e19", -20, RoundHalfEven, "0")
+} //                                                Test_bcur_BigCurrency_Round_
+
+// -----------------------------------------------------------------------------
+// # Arithmetic
+
+// go test --run Test_bcur_BigCurrency_Add_
+func Test_bcur_BigCurrency_Add_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) Add(nums ...BigCurrency) BigCurrency
+	// (n BigCurrency) AddFloat(nums ...float64) BigCurrency
+	// (n BigCurrency) AddInt(nums ...int) BigCurrency
+	// (n BigCurrency) Sub(nums ...BigCurrency) BigCurrency
+	// (n BigCurrency) SubFloat(nums ...float64) BigCurrency
+	// (n BigCurrency) SubInt(nums ...int) BigCurrency
+	//
+	limit := bcur(CurrencyIntLimit)
+	TEqual(t, limit.Add(limit, limit).String(), "2767011611056428")
+	TEqual(t, limit.Sub(limit, limit).String(), "-922337203685476")
+	TEqual(t, bcur(1).AddFloat(0.1, 0.12345).String(), "1.2234")
+	TEqual(t, bcur(1).SubFloat(0.1, 0.12345).String(), "0.7766")
+	TEqual(t, bcur(1).AddInt(2, 3).String(), "6")
+	TEqual(t, bcur(1).SubInt(2, 3).String(), "-4")
+	//
+	// the object's value isn't changed
+	n := bcur(5)
+	n.Add(bcur(1))
+	n.Sub(bcur(1))
+	TEqual(t, n.String(), "5")
+	//
+	// NaN is logged and skipped
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got := bcur(1).AddFloat(math.NaN(), 1)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, got.String(), "2")
+	TEqual(t, ec2-ec1, 1)
+} //                                                  Test_bcur_BigCurrency_Add_
+
+// go test --run Test_bcur_BigCurrency_DivE_
+func Test_bcur_BigCurrency_DivE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) DivE(nums ...BigCurrency) (BigCurrency, error)
+	//
+	got, err := bcur(10).DivE(bcur(3))
+	TEqual(t, got.String(), "3.3333")
+	TEqual(t, err, nil)
+	//
+	got, err = bcur("1e30").DivE(bcur(4), bcur("0.5"))
+	TEqual(t, got.String(), "500000000000000000000000000000")
+	TEqual(t, err, nil)
+	//
+	got, err = bcur(10).DivE(bcur(0))
+	TTrue(t, got.IsZero())
+	TTrue(t, err != nil)
+	//
+	TEqual(t, bcur(10).DivRound(RoundHalfUp, bcur(3)).String(), "3.3333")
+	TEqual(t, bcur(20).DivRound(RoundHalfUp, bcur(3)).String(), "6.6667")
+	TEqual(t, bcur(10).DivInt(4).String(), "2.5")
+	TEqual(t, bcur(10).DivFloat(3).String(), "3.3333")
+	TEqual(t, bcur(20).DivFloatRound(RoundHalfUp, 3).String(), "6.6667")
+	//
+	// divisions by zero are logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, bcur(1).Div(bcur(0)).IsZero())
+	TTrue(t, bcur(1).DivInt(0).IsZero())
+	TTrue(t, bcur(1).DivFloat(0).IsZero())
+	TTrue(t, bcur(1).DivFloatRound(RoundHalfUp, 0).IsZero())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 4)
+} //                                                 Test_bcur_BigCurrency_DivE_
+
+// go test --run Test_bcur_BigCurrency_Mul_
+func Test_bcur_BigCurrency_Mul_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) Mul(nums ...BigCurrency) BigCurrency
+	// (n BigCurrency) MulFloat(nums ...float64) BigCurrency
+	// (n BigCurrency) MulFloatRound(
+	//     mode RoundingMode, nums ...float64,
+	// ) BigCurrency
+	// (n BigCurrency) MulInt(nums ...int) BigCurrency
+	// (n BigCurrency) MulRound(mode RoundingMode, nums ...BigCurrency) BigCurrency
+	//
+	limit := bcur(CurrencyIntLimit)
+	TEqual(t, limit.Mul(limit).String(), "850705917302343242597133346576")
+	TEqual(t, bcur("1.2345").Mul(bcur("1.2345")).String(), "1.5239")
+	TEqual(t, bcur("1.2345").MulRound(RoundHalfUp, bcur("1.2345")).String(),
+		"1.524")
+	TEqual(t, bcur(10).MulFloat(1.1).String(), "11")
+	TEqual(t, bcur("0.0001").MulFloatRound(RoundHalfUp, 0.5).String(),
+		"0.0001")
+	TEqual(t, limit.MulInt(10, 10).String(), "92233720368547600")
+	//
+	// the same results as Currency within its range
+	for _, s := range []string{"0", "1.5", "-12.3456", "999999.9999"} {
+		for _, s2 := range []string{"0.0001", "-3", "7.77"} {
+			c := CurrencyOf(s).Mul(CurrencyOf(s2))
+			TEqual(t, bcur(s).Mul(bcur(s2)).String(), c.String())
+		}
+	}
+	// NaN is logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, bcur(1).MulFloat(math.Inf(-1)).IsZero())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 1)
+} //                                                  Test_bcur_BigCurrency_Mul_
+
+// -----------------------------------------------------------------------------
+// # Encoding
+
+// go test --run Test_bcur_BigCurrency_MarshalJSON_
+func Test_bcur_BigCurrency_MarshalJSON_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) MarshalJSON() ([]byte, error)
+	// (n *BigCurrency) UnmarshalJSON(data []byte) error
+	//
+	type rec struct {
+		Total BigCurrency
+	}
+	data, err := json.Marshal(rec{bcur("-98765432109876543210.5")})
+	TEqual(t, string(data), `{"Total":-98765432109876543210.5}`)
+	TEqual(t, err, nil)
+	//
+	var r rec
+	err = json.Unmarshal([]byte(`{"Total":"12345678901234567890.12345"}`),
+		&r)
+	TEqual(t, r.Total.String(), "12345678901234567890.1234")
+	TEqual(t, err, nil)
+	//
+	err = json.Unmarshal([]byte(`{"Total":null}`), &r)
+	TEqual(t, r.Total.String(), "12345678901234567890.1234")
+	TEqual(t, err, nil)
+	//
+	err = json.Unmarshal([]byte(`{"Total":"1e1000000"}`), &r)
+	TTrue(t, err != nil)
+	err = json.Unmarshal([]byte(`{"Total":"x"}`), &r)
+	TTrue(t, err != nil)
+	//
+	var nilPtr *BigCurrency
+	TTrue(t, nilPtr.UnmarshalJSON([]byte("1")) != nil)
+} //                                          Test_bcur_BigCurrency_MarshalJSON_
+
+// go test --run Test_bcur_BigCurrency_MarshalBinary_
+func Test_bcur_BigCurrency_MarshalBinary_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n BigCurrency) MarshalBinary() ([]byte, error)
+	// (n *BigCurrency) UnmarshalBinary(data []byte) error
+	// (n BigCurrency) MarshalText() ([]byte, error)
+	// (n *BigCurrency) UnmarshalText(text []byte) error
+	//
+	for _, s := range []string{"0", "-1.5", "123456789012345678901234.5678"} {
+		var (
+			n       = bcur(s)
+			got     BigCurrency
+			data, _ = n.MarshalBinary()
+		)
+		TEqual(t, got.UnmarshalBinary(data), nil)
+		TEqual(t, got.String(), s)
+		//
+		text, _ := n.MarshalText()
+		TEqual(t, string(text), s)
+		TEqual(t, got.UnmarshalText(text), nil)
+		TEqual(t, got.String(), s)
+	}
+	var got BigCurrency
+	TTrue(t, got.UnmarshalBinary([]byte{255}) != nil)
+} //                                        Test_bcur_BigCurrency_MarshalBinary_
+
+// end
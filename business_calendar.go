@@ -0,0 +1,815 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                          zr/[business_calendar.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Holiday Types
+//   HolidayRule int
+//   HolidayObserved int
+//   Holiday struct
+//   HolidayDate struct
+//   holidayRow struct
+//
+// # Holiday Functions
+//   EasterSunday(year int) time.Time
+//   HolidayOf(rule, name string) (Holiday, error)
+//
+// # BusinessCalendar Type
+//   BusinessCalendar struct
+//
+// # Methods (ob *BusinessCalendar)
+//   ) AddBusinessDays(date time.Time, n int) time.Time
+//   ) AddHoliday(holidays ...Holiday) error
+//   ) BusinessDaysBetween(from, to time.Time) int
+//   ) Holidays(year int) []HolidayDate
+//   ) IsBusinessDay(date time.Time) bool
+//   ) IsHoliday(date time.Time) bool
+//   ) IsWeekend(date time.Time) bool
+//   ) LoadJSON(r io.Reader) error
+//   ) LoadText(r io.Reader) error
+//   ) NextBusinessDay(date time.Time) time.Time
+//
+// # Private Methods/Functions
+//   (ob *BusinessCalendar) holidaysIn(year int) []HolidayDate
+//   (ob *BusinessCalendar) load(weekend []string, rows []holidayRow) error
+//   (ob *BusinessCalendar) observe(
+//       date time.Time, mode HolidayObserved,
+//       weekend []time.Weekday, taken map[time.Time]bool,
+//   ) (time.Time, bool)
+//   (ob *BusinessCalendar) step(date time.Time, dir int) (time.Time, bool)
+//   (h Holiday) dateIn(year int) (time.Time, bool)
+//   (h Holiday) validate() error
+//   (row holidayRow) parse() (Holiday, error)
+//   businessDay(date time.Time) time.Time
+//   businessIsWeekend(weekend []time.Weekday, day time.Weekday) bool
+//   businessSameDays(a, b []time.Weekday) bool
+//   businessWeekdayOf(s string) (time.Weekday, bool)
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// holidayDateRx matches fixed holiday rules like "12-25" or "2024-12-27".
+var holidayDateRx = regexp.MustCompile(`^(?:\d{4}-)?\d{2}-\d{2}$`)
+
+// holidayEasterRx matches Easter-relative holiday rules
+// like "easter", "easter+1" or "easter - 2".
+var holidayEasterRx = regexp.MustCompile(`^EASTER(?:\s*([+-])\s*(\d+))?$`)
+
+// holidayNthWords maps the words used in nth weekday
+// holiday rules (e.g. "last Mon May") to Holiday.Nth.
+var holidayNthWords = map[string]int{
+	"FIRST": 1, "SECOND": 2, "THIRD": 3, "FOURTH": 4, "FIFTH": 5,
+	"1ST": 1, "2ND": 2, "3RD": 3, "4TH": 4, "5TH": 5, "LAST": -1,
+}
+
+// -----------------------------------------------------------------------------
+// # Holiday Types
+
+// HolidayRule specifies how the date of a holiday is determined.
+type HolidayRule int
+
+const (
+	// HolidayFixed occurs on the same day and month every
+	// year (e.g. December 25), or on a single date if
+	// Holiday.Year is specified.
+	HolidayFixed HolidayRule = iota
+
+	// HolidayNthWeekday occurs on the nth weekday of
+	// a month, e.g. the last Monday in May.
+	HolidayNthWeekday
+
+	// HolidayEaster occurs a number of days before or after
+	// Easter Sunday, e.g. Good Friday is 2 days before it.
+	HolidayEaster
+)
+
+// HolidayObserved specifies if and when a holiday that
+// falls on a weekend is observed on a business day.
+type HolidayObserved int
+
+const (
+	// ObserveNone doesn't move holidays that fall on a weekend.
+	ObserveNone HolidayObserved = iota
+
+	// ObserveFollowing moves a holiday that falls on a weekend to the
+	// following business day, usually Monday. If that day is already
+	// a holiday, the next one is used, so that when Christmas and
+	// Boxing Day fall on a weekend, they are observed on Monday and
+	// Tuesday.
+	ObserveFollowing
+
+	// ObserveNearest moves a holiday that falls on a weekend to
+	// the nearest business day, e.g. Saturday to Friday and
+	// Sunday to Monday.
+	ObserveNearest
+)
+
+// Holiday describes a public holiday that recurs every year.
+type Holiday struct {
+	// Name is the name of the holiday, e.g. "Christmas Day".
+	Name string
+
+	// Rule specifies which of the following fields give the date.
+	Rule HolidayRule
+
+	// Month is the month of HolidayFixed and HolidayNthWeekday holidays.
+	Month time.Month
+
+	// Day is the day of the month of HolidayFixed holidays.
+	// Holidays on February 29 only occur in leap years.
+	Day int
+
+	// Weekday is the day of the week of HolidayNthWeekday holidays.
+	Weekday time.Weekday
+
+	// Nth is 1 to 5 for the first to fifth Weekday of the month, or -1
+	// to -5 for the last to the fifth-last. If the month has no such
+	// weekday, there is no holiday that year.
+	Nth int
+
+	// Offset is the number of days after Easter Sunday of HolidayEaster
+	// holidays, or before it if negative. E.g. -2 for Good Friday.
+	Offset int
+
+	// Year limits the holiday to a single year, if not zero.
+	Year int
+
+	// Observed specifies if and when the holiday is
+	// observed when it falls on a weekend.
+	Observed HolidayObserved
+} //                                                                     Holiday
+
+// HolidayDate is a holiday on a specific date, returned by Holidays().
+type HolidayDate struct {
+	Date     time.Time // the date at midnight UTC
+	Name     string
+	Observed bool // true if the holiday fell on a weekend and was moved
+} //                                                                 HolidayDate
+
+// holidayRow is a holiday read by LoadText() or LoadJSON().
+type holidayRow struct {
+	Rule     string `json:"rule"`
+	Name     string `json:"name"`
+	Observed string `json:"observed"`
+	no       int    // number of the line or JSON array item
+} //                                                                  holidayRow
+
+// -----------------------------------------------------------------------------
+// # Holiday Functions
+
+// EasterSunday returns the date of Easter Sunday in the
+// Gregorian calendar in the specified year, at midnight UTC.
+func EasterSunday(year int) time.Time {
+	// anonymous Gregorian algorithm (Meeus/Jones/Butcher)
+	var (
+		a     = year % 19
+		b     = year / 100
+		c     = year % 100
+		d     = b / 4
+		e     = b % 4
+		f     = (b + 8) / 25
+		g     = (b - f + 1) / 3
+		h     = (19*a + b - d - g + 15) % 30
+		i     = c / 4
+		k     = c % 4
+		l     = (32 + 2*e + 2*i - h - k) % 7
+		m     = (a + 11*h + 22*l) / 451
+		month = (h + l - 7*m + 114) / 31
+		day   = (h+l-7*m+114)%31 + 1
+	)
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+} //                                                                EasterSunday
+
+// HolidayOf creates a holiday from a rule written as text, which is
+// one of the following (names of days and months can be abbreviated
+// and are not case-sensitive):
+//
+//	"12-25"         every year on December 25
+//	"2024-12-27"    only on December 27, 2024
+//	"last Mon May"  the last Monday in May: first, second, third,
+//	                fourth, fifth, 1st to 5th or last weekday of
+//	                a month. "of" and "in" are ignored, e.g.
+//	                "first Monday of September"
+//	"easter-2"      2 days before Easter Sunday (Good Friday)
+//	"easter+1"      the day after Easter Sunday (Easter Monday)
+//
+// The returned holiday is not observed on other days when it falls
+// on a weekend. Set its Observed field to change this.
+//
+// Returns an error if the rule is not valid. Does not log errors.
+func HolidayOf(rule, name string) (Holiday, error) {
+	s := strings.ToUpper(strings.TrimSpace(rule))
+	ret := Holiday{Name: name}
+	invalid := func() (Holiday, error) {
+		return Holiday{}, fmt.Errorf("%s holiday rule %q", EInvalid, rule)
+	}
+	if m := holidayEasterRx.FindStringSubmatch(s); m != nil {
+		ret.Rule = HolidayEaster
+		if m[2] != "" {
+			n, err := strconv.Atoi(m[2])
+			if err != nil || n > 366 {
+				return invalid()
+			}
+			ret.Offset = n
+			if m[1] == "-" {
+				ret.Offset = -n
+			}
+		}
+		return ret, nil
+	}
+	if holidayDateRx.MatchString(s) {
+		layout := "01-02"
+		if len(s) == 10 {
+			layout = "2006-01-02"
+		}
+		date, err := time.Parse(layout, s)
+		if err != nil {
+			return invalid()
+		}
+		ret.Rule, ret.Month, ret.Day = HolidayFixed, date.Month(), date.Day()
+		if len(s) == 10 {
+			ret.Year = date.Year()
+		}
+		return ret, nil
+	}
+	var words []string
+	for _, word := range strings.Fields(s) {
+		if word != "OF" && word != "IN" {
+			words = append(words, word)
+		}
+	}
+	if len(words) != 3 {
+		return invalid()
+	}
+	nth, found := holidayNthWords[words[0]]
+	if !found {
+		return invalid()
+	}
+	weekday, found := businessWeekdayOf(words[1])
+	if !found {
+		return invalid()
+	}
+	month := MonthNumberEN(words[2])
+	if month == 0 {
+		return invalid()
+	}
+	ret.Rule, ret.Nth = HolidayNthWeekday, nth
+	ret.Weekday, ret.Month = weekday, time.Month(month)
+	return ret, nil
+} //                                                                   HolidayOf
+
+// -----------------------------------------------------------------------------
+// # BusinessCalendar Type
+
+// BusinessCalendar determines which days are business days, given the
+// days of the weekend and a list of public holidays, and calculates
+// due dates and the number of business days between dates.
+//
+// Only the calendar date of the times passed to its methods is used.
+// Returned dates keep the time of day and time zone of the given date.
+//
+// The zero value is a calendar with Saturday and Sunday weekends
+// and no holidays, ready to use. Set Weekend and add holidays
+// before using the calendar. Adding or loading holidays and using
+// the calendar is safe for concurrent use, but setting Weekend
+// directly is not: set it before sharing the calendar, or use
+// LoadText() or LoadJSON() to change it later.
+type BusinessCalendar struct {
+	// Weekend lists the days of the week that are not business days.
+	// If nil, Saturday and Sunday are used. Use an empty (non-nil)
+	// slice if there are no weekend days.
+	Weekend []time.Weekday
+
+	mu       sync.RWMutex
+	holidays []Holiday
+	years    map[int][]HolidayDate // holiday dates by year, sorted
+	yearsOf  []time.Weekday        // copy of Weekend used for 'years'
+} //                                                            BusinessCalendar
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *BusinessCalendar)
+
+// AddBusinessDays returns the date that is 'n' business days after
+// 'date', or before it if 'n' is negative. For example, adding 1 to a
+// Friday usually returns the following Monday. If 'n' is zero, returns
+// 'date' if it is a business day, otherwise the next business day.
+//
+// If the calendar has no business days, logs an error and returns 'date'.
+func (ob *BusinessCalendar) AddBusinessDays(date time.Time, n int) time.Time {
+	if n == 0 {
+		if ob.IsBusinessDay(date) {
+			return date
+		}
+		n = 1
+	}
+	dir := 1
+	if n < 0 {
+		dir, n = -1, -n
+	}
+	ret := date
+	for i := 0; i < n; i++ {
+		next, ok := ob.step(ret, dir)
+		if !ok {
+			return date
+		}
+		ret = next
+	}
+	return ret
+} //                                                             AddBusinessDays
+
+// AddHoliday adds one or more holidays to the calendar.
+//
+// Returns an error if any holiday is not valid,
+// without adding any holidays. Does not log errors.
+func (ob *BusinessCalendar) AddHoliday(holidays ...Holiday) error {
+	if ob == nil {
+		return errors.New(ENilReceiver)
+	}
+	for _, h := range holidays {
+		if err := h.validate(); err != nil {
+			return err
+		}
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.holidays = append(ob.holidays, holidays...)
+	ob.years = nil
+	return nil
+} //                                                                  AddHoliday
+
+// BusinessDaysBetween returns the number of business days after 'from'
+// up to and including 'to', so that for a business day 'to':
+// BusinessDaysBetween(from, AddBusinessDays(from, n)) == n.
+// Returns a negative number if 'to' is before 'from'.
+func (ob *BusinessCalendar) BusinessDaysBetween(from, to time.Time) int {
+	var (
+		start = businessDay(from)
+		end   = businessDay(to)
+		sign  = 1
+		ret   = 0
+	)
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	for day := start.AddDate(0, 0, 1); !day.After(end); {
+		if ob.IsBusinessDay(day) {
+			ret++
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return ret * sign
+} //                                                         BusinessDaysBetween
+
+// Holidays returns the holidays in the specified year, in chronological
+// order. The list includes holidays that fall on a weekend and, if they
+// are observed, the business days on which they are observed.
+func (ob *BusinessCalendar) Holidays(year int) []HolidayDate {
+	days := ob.holidaysIn(year)
+	if len(days) == 0 {
+		return nil
+	}
+	return append([]HolidayDate(nil), days...)
+} //                                                                    Holidays
+
+// IsBusinessDay returns true if 'date' is neither
+// a weekend day nor a holiday (observed or not).
+func (ob *BusinessCalendar) IsBusinessDay(date time.Time) bool {
+	return !ob.IsWeekend(date) && !ob.IsHoliday(date)
+} //                                                               IsBusinessDay
+
+// IsHoliday returns true if 'date' is a holiday, or a
+// business day on which a holiday is observed.
+func (ob *BusinessCalendar) IsHoliday(date time.Time) bool {
+	day := businessDay(date)
+	for _, it := range ob.holidaysIn(day.Year()) {
+		if it.Date.Equal(day) {
+			return true
+		}
+	}
+	return false
+} //                                                                   IsHoliday
+
+// IsWeekend returns true if 'date' falls on a weekend day.
+func (ob *BusinessCalendar) IsWeekend(date time.Time) bool {
+	if ob == nil {
+		return businessIsWeekend(nil, date.Weekday())
+	}
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	return businessIsWeekend(ob.Weekend, date.Weekday())
+} //                                                                   IsWeekend
+
+// LoadJSON reads the weekend days and holidays from JSON data and adds
+// the holidays to the calendar. The data is an object like:
+//
+//	{
+//	    "weekend": ["Sat", "Sun"],
+//	    "holidays": [
+//	        {"rule": "12-25", "name": "Christmas Day",
+//	         "observed": "following"},
+//	        {"rule": "easter-2", "name": "Good Friday"}
+//	    ]
+//	}
+//
+// "weekend" is optional and replaces Weekend if specified. The data
+// can also be just the array of holidays. See LoadText for the
+// rules and "observed" values.
+//
+// If any holiday is invalid, returns an error that specifies the
+// holiday and doesn't change the calendar. Does not log errors.
+func (ob *BusinessCalendar) LoadJSON(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s holidays JSON: %v", EFailedReading, err)
+	}
+	var file struct {
+		Weekend  []string     `json:"weekend"`
+		Holidays []holidayRow `json:"holidays"`
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = mod.json.Unmarshal(data, &file.Holidays)
+	} else {
+		err = mod.json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return fmt.Errorf("%s holidays JSON: %v", EFailedParsing, err)
+	}
+	for i := range file.Holidays {
+		file.Holidays[i].no = i + 1
+	}
+	return ob.load(file.Weekend, file.Holidays)
+} //                                                                    LoadJSON
+
+// LoadText reads the weekend days and holidays from text and adds the
+// holidays to the calendar. Each line has a rule (see HolidayOf),
+// the holiday's name and optionally when it is observed if it falls
+// on a weekend: "none", "following" (or "monday") or "nearest"
+// (see HolidayObserved), separated by colons. For example:
+//
+//	# United Kingdom (England and Wales)
+//	weekend: Sat Sun
+//	01-01: New Year's Day: following
+//	easter-2: Good Friday
+//	easter+1: Easter Monday
+//	first Mon May: Early May bank holiday
+//	last Mon May: Spring bank holiday
+//	last Mon Aug: Summer bank holiday
+//	12-25: Christmas Day: following
+//	12-26: Boxing Day: following
+//
+// The optional "weekend" line replaces Weekend. Blank lines and
+// lines starting with '#' are ignored.
+//
+// If any line is invalid, returns an error that specifies the
+// line and doesn't change the calendar. Does not log errors.
+func (ob *BusinessCalendar) LoadText(r io.Reader) error {
+	var (
+		weekend []string
+		rows    []holidayRow
+		sc      = bufio.NewScanner(r)
+		no      = 0
+	)
+	for sc.Scan() {
+		no++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		for i, field := range fields {
+			fields[i] = strings.TrimSpace(field)
+		}
+		if strings.EqualFold(fields[0], "weekend") && len(fields) == 2 {
+			weekend = append([]string{}, strings.Fields(fields[1])...)
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("holiday line %d: %s %q", no, EInvalid, line)
+		}
+		row := holidayRow{Rule: fields[0], Name: fields[1], no: no}
+		if len(fields) == 3 {
+			row.Observed = fields[2]
+		}
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("%s holidays: %v", EFailedReading, err)
+	}
+	return ob.load(weekend, rows)
+} //                                                                    LoadText
+
+// NextBusinessDay returns the first business day after 'date'.
+//
+// If the calendar has no business days, logs an error and returns 'date'.
+func (ob *BusinessCalendar) NextBusinessDay(date time.Time) time.Time {
+	ret, ok := ob.step(date, 1)
+	if !ok {
+		return date
+	}
+	return ret
+} //                                                             NextBusinessDay
+
+// -----------------------------------------------------------------------------
+// # Private Methods/Functions
+
+// holidaysIn returns the holiday dates in the specified year,
+// calculating and caching them if necessary. The cache is cleared
+// if Weekend was changed since it was filled. The returned slice
+// must not be changed.
+func (ob *BusinessCalendar) holidaysIn(year int) []HolidayDate {
+	if ob == nil {
+		return nil
+	}
+	ob.mu.RLock()
+	ret, found := ob.years[year]
+	found = found && businessSameDays(ob.yearsOf, ob.Weekend)
+	ob.mu.RUnlock()
+	if found {
+		return ret
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	if !businessSameDays(ob.yearsOf, ob.Weekend) {
+		ob.years = nil
+		ob.yearsOf = nil
+		if ob.Weekend != nil {
+			ob.yearsOf = append([]time.Weekday{}, ob.Weekend...)
+		}
+	}
+	if ret, found := ob.years[year]; found {
+		return ret // added by another goroutine
+	}
+	weekend := ob.yearsOf
+	//
+	// get the holidays in the previous, current and next years
+	// since observed holidays can move across the new year
+	type dated struct {
+		date time.Time
+		h    Holiday
+	}
+	var all []dated
+	for y := year - 1; y <= year+1; y++ {
+		for _, h := range ob.holidays {
+			if date, ok := h.dateIn(y); ok {
+				all = append(all, dated{date, h})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].date.Before(all[j].date)
+	})
+	taken := make(map[time.Time]bool, len(all))
+	for _, it := range all {
+		taken[it.date] = true
+	}
+	ret = []HolidayDate{}
+	for _, it := range all {
+		if it.date.Year() == year {
+			ret = append(ret, HolidayDate{Date: it.date, Name: it.h.Name})
+		}
+		if it.h.Observed == ObserveNone ||
+			!businessIsWeekend(weekend, it.date.Weekday()) {
+			continue
+		}
+		date, ok := ob.observe(it.date, it.h.Observed, weekend, taken)
+		if !ok {
+			continue
+		}
+		taken[date] = true
+		if date.Year() == year {
+			ret = append(ret, HolidayDate{
+				Date: date, Name: it.h.Name, Observed: true,
+			})
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Date.Before(ret[j].Date)
+	})
+	if ob.years == nil {
+		ob.years = make(map[int][]HolidayDate)
+	}
+	ob.years[year] = ret
+	return ret
+} //                                                                  holidaysIn
+
+// load validates the weekend days and holidays read by LoadText() or
+// LoadJSON() and adds them to the calendar. If any of them is invalid,
+// returns an error and doesn't change the calendar.
+func (ob *BusinessCalendar) load(weekend []string, rows []holidayRow) error {
+	if ob == nil {
+		return errors.New(ENilReceiver)
+	}
+	var days []time.Weekday
+	if weekend != nil {
+		days = []time.Weekday{}
+		for _, s := range weekend {
+			day, found := businessWeekdayOf(s)
+			if !found {
+				return fmt.Errorf("%s weekend day %q", EInvalid, s)
+			}
+			days = append(days, day)
+		}
+	}
+	holidays := make([]Holiday, len(rows))
+	for i, row := range rows {
+		h, err := row.parse()
+		if err != nil {
+			return fmt.Errorf("holiday %d: %v", row.no, err)
+		}
+		holidays[i] = h
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	if days != nil {
+		ob.Weekend = days
+	}
+	ob.holidays = append(ob.holidays, holidays...)
+	ob.years = nil
+	return nil
+} //                                                                        load
+
+// observe returns the business day on which a holiday that falls on
+// a weekend 'date' is observed, skipping the dates in 'taken'.
+// Returns false if there is no such day within a week.
+func (ob *BusinessCalendar) observe(
+	date time.Time, mode HolidayObserved,
+	weekend []time.Weekday, taken map[time.Time]bool,
+) (time.Time, bool) {
+	free := func(day time.Time) bool {
+		return !businessIsWeekend(weekend, day.Weekday()) && !taken[day]
+	}
+	for i := 1; i <= 7; i++ {
+		if mode == ObserveNearest {
+			if day := date.AddDate(0, 0, -i); free(day) {
+				return day, true
+			}
+		}
+		if day := date.AddDate(0, 0, i); free(day) {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+} //                                                                     observe
+
+// step returns the first business day after 'date' if 'dir' is 1,
+// or before it if 'dir' is -1. If there are no business days,
+// logs an error and returns false.
+func (ob *BusinessCalendar) step(date time.Time, dir int) (time.Time, bool) {
+	// a year is enough to skip any weekends and holidays,
+	// unless the weekends or holidays cover every day
+	for i := 1; i <= 366; i++ {
+		day := date.AddDate(0, 0, i*dir)
+		if ob.IsBusinessDay(day) {
+			return day, true
+		}
+	}
+	mod.Error("BusinessCalendar has no business days")
+	return date, false
+} //                                                                        step
+
+// dateIn returns the date of the holiday in the specified year, at
+// midnight UTC, or false if the holiday doesn't occur that year.
+// The date of Easter-relative holidays can fall in another year.
+func (h Holiday) dateIn(year int) (time.Time, bool) {
+	if h.Year != 0 && h.Year != year {
+		return time.Time{}, false
+	}
+	switch h.Rule {
+	case HolidayFixed:
+		if h.Day > DaysInMonth(year, h.Month) {
+			return time.Time{}, false
+		}
+		return time.Date(year, h.Month, h.Day, 0, 0, 0, 0, time.UTC), true
+	case HolidayNthWeekday:
+		var (
+			last = DaysInMonth(year, h.Month)
+			day  = 0
+		)
+		if h.Nth > 0 {
+			first := time.Date(year, h.Month, 1, 0, 0, 0, 0, time.UTC)
+			day = 1 + (int(h.Weekday)-int(first.Weekday())+7)%7 + (h.Nth-1)*7
+		} else {
+			end := time.Date(year, h.Month, last, 0, 0, 0, 0, time.UTC)
+			day = last - (int(end.Weekday())-int(h.Weekday)+7)%7 + (h.Nth+1)*7
+		}
+		if day < 1 || day > last {
+			return time.Time{}, false
+		}
+		return time.Date(year, h.Month, day, 0, 0, 0, 0, time.UTC), true
+	case HolidayEaster:
+		return EasterSunday(year).AddDate(0, 0, h.Offset), true
+	}
+	return time.Time{}, false
+} //                                                                      dateIn
+
+// validate returns an error if the holiday is not valid.
+func (h Holiday) validate() error {
+	invalid := func(what string, value interface{}) error {
+		return fmt.Errorf("%s holiday %q: %s %v", EInvalid, h.Name, what, value)
+	}
+	if h.Observed < ObserveNone || h.Observed > ObserveNearest {
+		return invalid("observed", int(h.Observed))
+	}
+	switch h.Rule {
+	case HolidayFixed:
+		if h.Month < time.January || h.Month > time.December {
+			return invalid("month", int(h.Month))
+		}
+		if h.Day < 1 || h.Day > DaysInMonth(2000, h.Month) {
+			return invalid("day", h.Day)
+		}
+	case HolidayNthWeekday:
+		if h.Month < time.January || h.Month > time.December {
+			return invalid("month", int(h.Month))
+		}
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return invalid("weekday", int(h.Weekday))
+		}
+		if h.Nth == 0 || h.Nth < -5 || h.Nth > 5 {
+			return invalid("nth", h.Nth)
+		}
+	case HolidayEaster:
+		if h.Offset < -366 || h.Offset > 366 {
+			return invalid("offset", h.Offset)
+		}
+	default:
+		return invalid("rule", int(h.Rule))
+	}
+	return nil
+} //                                                                    validate
+
+// parse validates a holiday read by LoadText() or LoadJSON().
+func (row holidayRow) parse() (Holiday, error) {
+	ret, err := HolidayOf(row.Rule, strings.TrimSpace(row.Name))
+	if err != nil {
+		return Holiday{}, err
+	}
+	switch strings.ToLower(strings.TrimSpace(row.Observed)) {
+	case "", "none":
+		ret.Observed = ObserveNone
+	case "following", "monday":
+		ret.Observed = ObserveFollowing
+	case "nearest":
+		ret.Observed = ObserveNearest
+	default:
+		return Holiday{}, fmt.Errorf("%s observed value %q",
+			EInvalid, row.Observed)
+	}
+	return ret, nil
+} //                                                                       parse
+
+// businessDay returns the calendar date of 'date' at midnight UTC.
+func businessDay(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+} //                                                                 businessDay
+
+// businessIsWeekend returns true if 'day' is one of the 'weekend' days.
+// If 'weekend' is nil, Saturday and Sunday are the weekend.
+func businessIsWeekend(weekend []time.Weekday, day time.Weekday) bool {
+	if weekend == nil {
+		return day == time.Saturday || day == time.Sunday
+	}
+	for _, it := range weekend {
+		if it == day {
+			return true
+		}
+	}
+	return false
+} //                                                           businessIsWeekend
+
+// businessSameDays returns true if 'a' and 'b' have the same days in
+// the same order and are both nil or both not nil.
+func businessSameDays(a, b []time.Weekday) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+} //                                                            businessSameDays
+
+// businessWeekdayOf returns the day of the week given its English
+// name or 3-letter abbreviation, e.g. "Saturday" or "sat".
+func businessWeekdayOf(s string) (time.Weekday, bool) {
+	return dateRelativeWeekday(strings.ToUpper(strings.TrimSpace(s)))
+} //                                                           businessWeekdayOf
+
+// end
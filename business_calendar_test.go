@@ -0,0 +1,521 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                     zr/[business_calendar_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Holiday Functions
+//   Test_bcal_EasterSunday_
+//   Test_bcal_HolidayOf_
+//
+// # Methods (ob *BusinessCalendar)
+//   Test_bcal_BusinessCalendar_AddBusinessDays_
+//   Test_bcal_BusinessCalendar_AddHoliday_
+//   Test_bcal_BusinessCalendar_BusinessDaysBetween_
+//   Test_bcal_BusinessCalendar_Holidays_
+//   Test_bcal_BusinessCalendar_IsBusinessDay_
+//   Test_bcal_BusinessCalendar_LoadJSON_
+//   Test_bcal_BusinessCalendar_LoadText_
+//   Test_bcal_BusinessCalendar_NextBusinessDay_
+
+//  to test all items in business_calendar.go use:
+//      go test --run Test_bcal_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// bcalFixtureUK contains the bank holidays of England and Wales.
+const bcalFixtureUK = `
+# England and Wales
+weekend: Sat Sun
+01-01: New Year's Day: following
+easter-2: Good Friday
+easter+1: Easter Monday
+first Mon May: Early May bank holiday
+last Mon May: Spring bank holiday
+last Mon Aug: Summer bank holiday
+12-25: Christmas Day: following
+12-26: Boxing Day: following
+`
+
+// bcalFixtureUS contains some federal holidays of the United States.
+const bcalFixtureUS = `{
+    "holidays": [
+        {"rule": "01-01", "name": "New Year's Day", "observed": "nearest"},
+        {"rule": "third Monday of January", "name": "Martin Luther King Day"},
+        {"rule": "last Monday of May", "name": "Memorial Day"},
+        {"rule": "07-04", "name": "Independence Day", "observed": "nearest"},
+        {"rule": "first Monday of September", "name": "Labor Day"},
+        {"rule": "fourth Thursday of November", "name": "Thanksgiving"},
+        {"rule": "12-25", "name": "Christmas Day", "observed": "nearest"}
+    ]
+}`
+
+// bcalCalendar returns a business calendar loaded from text or JSON.
+func bcalCalendar(t *testing.T, data string) *BusinessCalendar {
+	var ret BusinessCalendar
+	var err error
+	if strings.HasPrefix(data, "{") {
+		err = ret.LoadJSON(strings.NewReader(data))
+	} else {
+		err = ret.LoadText(strings.NewReader(data))
+	}
+	if err != nil {
+		t.Fatalf("failed loading holidays: %v", err)
+	}
+	return &ret
+} //                                                                bcalCalendar
+
+// bcalDates returns the dates and names of holidays as a string.
+func bcalDates(holidays []HolidayDate) string {
+	a := make([]string, len(holidays))
+	for i, it := range holidays {
+		a[i] = it.Date.Format("2006-01-02 Mon ") + it.Name
+		if it.Observed {
+			a[i] += " (observed)"
+		}
+	}
+	return strings.Join(a, "\n")
+} //                                                                   bcalDates
+
+// -----------------------------------------------------------------------------
+// # Holiday Functions
+
+// go test --run Test_bcal_EasterSunday_
+func Test_bcal_EasterSunday_(t *testing.T) {
+	TBegin(t)
+	//
+	// EasterSunday(year int) time.Time
+	//
+	for year, expect := range map[int]string{
+		1818: "1818-03-22", // earliest possible
+		1943: "1943-04-25", // latest possible
+		1961: "1961-04-02",
+		2000: "2000-04-23",
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	} {
+		got := EasterSunday(year)
+		TEqual(t, got.Format("2006-01-02"), expect)
+		TEqual(t, got.Weekday(), time.Sunday)
+	}
+} //                                                     Test_bcal_EasterSunday_
+
+// go test --run Test_bcal_HolidayOf_
+func Test_bcal_HolidayOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// HolidayOf(rule, name string) (Holiday, error)
+	//
+	test := func(rule string, expect Holiday) {
+		got, err := HolidayOf(rule, expect.Name)
+		if err != nil || got != expect {
+			TFailf(t, `HolidayOf(%q) returned %+v, %v`, rule, got, err)
+		}
+	}
+	test("12-25", Holiday{Name: "Christmas", Rule: HolidayFixed,
+		Month: 12, Day: 25})
+	test(" 02-29 ", Holiday{Rule: HolidayFixed, Month: 2, Day: 29})
+	test("2024-12-27", Holiday{Name: "Extra", Rule: HolidayFixed,
+		Month: 12, Day: 27, Year: 2024})
+	test("last Mon May", Holiday{Rule: HolidayNthWeekday,
+		Month: time.May, Weekday: time.Monday, Nth: -1})
+	test("first Monday of September", Holiday{Rule: HolidayNthWeekday,
+		Month: time.September, Weekday: time.Monday, Nth: 1})
+	test("4th thu in nov", Holiday{Rule: HolidayNthWeekday,
+		Month: time.November, Weekday: time.Thursday, Nth: 4})
+	test("Easter", Holiday{Rule: HolidayEaster})
+	test("easter-2", Holiday{Rule: HolidayEaster, Offset: -2})
+	test("EASTER + 50", Holiday{Rule: HolidayEaster, Offset: 50})
+	//
+	for _, rule := range []string{
+		"", "13-01", "02-30", "2023-02-29", "12/25", "1-1", "12-25-2024",
+		"last Mon", "sixth Mon May", "last Mon Foo", "last Fooday May",
+		"last Mon of", "easter*2", "easter+400", "christmas",
+	} {
+		got, err := HolidayOf(rule, "")
+		if err == nil || got != (Holiday{}) {
+			TFailf(t, `HolidayOf(%q) returned %+v, %v`, rule, got, err)
+		}
+	}
+} //                                                        Test_bcal_HolidayOf_
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *BusinessCalendar)
+
+// go test --run Test_bcal_BusinessCalendar_AddBusinessDays_
+func Test_bcal_BusinessCalendar_AddBusinessDays_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) AddBusinessDays(date time.Time, n int) time.Time
+	//
+	cal := bcalCalendar(t, bcalFixtureUK)
+	test := func(date string, n int, expect string) {
+		got := cal.AddBusinessDays(DateOf(date), n).Format("2006-01-02")
+		if got != expect {
+			TFailf(t, `AddBusinessDays(%q, %d) returned %q instead of %q`,
+				date, n, got, expect)
+		}
+	}
+	// weekends
+	test("2024-03-06", 1, "2024-03-07") // Wed -> Thu
+	test("2024-03-08", 1, "2024-03-11") // Fri -> Mon
+	test("2024-03-09", 1, "2024-03-11") // Sat -> Mon
+	test("2024-03-08", 5, "2024-03-15")
+	test("2024-03-11", -1, "2024-03-08")
+	test("2024-03-10", -1, "2024-03-08")
+	//
+	// Easter: Good Friday and Easter Monday
+	test("2024-03-28", 1, "2024-04-02")
+	test("2024-04-02", -1, "2024-03-28")
+	test("2024-03-27", 3, "2024-04-03")
+	//
+	// Christmas
+	test("2024-12-24", 1, "2024-12-27")
+	test("2021-12-24", 1, "2021-12-29") // observed on Mon 27 and Tue 28
+	//
+	// zero moves to the next business day, if necessary
+	test("2024-03-08", 0, "2024-03-08")
+	test("2024-03-30", 0, "2024-04-02")
+	//
+	// the time of day and time zone are kept
+	cet := time.FixedZone("CET", 60*60)
+	TEqual(t, cal.AddBusinessDays(time.Date(2024, 3, 8, 17, 30, 0, 0, cet), 1),
+		time.Date(2024, 3, 11, 17, 30, 0, 0, cet))
+	//
+	// a calendar without holidays has only weekends
+	var plain BusinessCalendar
+	TEqual(t, plain.AddBusinessDays(DateOf("2024-12-24"), 1),
+		DateOf("2024-12-25"))
+	TEqual(t, plain.AddBusinessDays(DateOf("2024-12-27"), 1),
+		DateOf("2024-12-30"))
+	//
+	// no business days at all
+	all := BusinessCalendar{Weekend: []time.Weekday{
+		time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+		time.Thursday, time.Friday, time.Saturday,
+	}}
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got := all.AddBusinessDays(DateOf("2024-03-08"), 1)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, got, DateOf("2024-03-08"))
+	TEqual(t, ec2-ec1, 1)
+} //                                 Test_bcal_BusinessCalendar_AddBusinessDays_
+
+// go test --run Test_bcal_BusinessCalendar_AddHoliday_
+func Test_bcal_BusinessCalendar_AddHoliday_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) AddHoliday(holidays ...Holiday) error
+	//
+	var cal BusinessCalendar
+	TFalse(t, cal.IsHoliday(DateOf("2024-05-01")))
+	err := cal.AddHoliday(
+		Holiday{Name: "Labour Day", Month: time.May, Day: 1},
+		Holiday{Name: "Whit Monday", Rule: HolidayEaster, Offset: 50},
+	)
+	TEqual(t, err, nil)
+	TTrue(t, cal.IsHoliday(DateOf("2024-05-01")))
+	TTrue(t, cal.IsHoliday(DateOf("2024-05-20")))
+	//
+	// invalid holidays are not added
+	for _, h := range []Holiday{
+		{Name: "a", Month: 0, Day: 1},
+		{Name: "b", Month: 2, Day: 30},
+		{Name: "c", Rule: HolidayNthWeekday, Month: 1, Nth: 0},
+		{Name: "d", Rule: HolidayNthWeekday, Month: 1, Nth: 6},
+		{Name: "e", Rule: HolidayNthWeekday, Month: 1, Nth: 1, Weekday: 7},
+		{Name: "f", Rule: HolidayEaster, Offset: 1000},
+		{Name: "g", Rule: HolidayRule(9)},
+		{Name: "h", Month: 1, Day: 1, Observed: HolidayObserved(9)},
+	} {
+		err := cal.AddHoliday(Holiday{Month: 1, Day: 2}, h)
+		if err == nil || !strings.Contains(err.Error(), `"`+h.Name+`"`) {
+			TFailf(t, `AddHoliday(%+v) returned %v`, h, err)
+		}
+	}
+	TFalse(t, cal.IsHoliday(DateOf("2024-01-02")))
+	//
+	var nilCal *BusinessCalendar
+	err = nilCal.AddHoliday()
+	TTrue(t, err != nil && err.Error() == ENilReceiver)
+} //                                      Test_bcal_BusinessCalendar_AddHoliday_
+
+// go test --run Test_bcal_BusinessCalendar_BusinessDaysBetween_
+func Test_bcal_BusinessCalendar_BusinessDaysBetween_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) BusinessDaysBetween(from, to time.Time) int
+	//
+	cal := bcalCalendar(t, bcalFixtureUK)
+	test := func(from, to string, expect int) {
+		got := cal.BusinessDaysBetween(DateOf(from), DateOf(to))
+		if got != expect {
+			TFailf(t, `BusinessDaysBetween(%q, %q) returned %d instead of %d`,
+				from, to, got, expect)
+		}
+	}
+	test("2024-03-08", "2024-03-08", 0)
+	test("2024-03-08", "2024-03-11", 1)
+	test("2024-03-08", "2024-03-10", 0)
+	test("2024-03-11", "2024-03-08", -1)
+	test("2024-03-28", "2024-04-02", 1)
+	test("2024-03-01", "2024-03-31", 19) // 21 weekdays less Good Friday
+	test("2024-01-01", "2024-12-31", 254)
+	test("2024-12-31", "2024-01-01", -254)
+	//
+	// agrees with AddBusinessDays
+	from := DateOf("2024-03-27")
+	for n := -30; n <= 30; n++ {
+		TEqual(t, cal.BusinessDaysBetween(from, cal.AddBusinessDays(from, n)), n)
+	}
+	// only the calendar dates are used
+	TEqual(t, cal.BusinessDaysBetween(
+		time.Date(2024, 3, 8, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 11, 1, 0, 0, 0, time.UTC)), 1)
+} //                             Test_bcal_BusinessCalendar_BusinessDaysBetween_
+
+// go test --run Test_bcal_BusinessCalendar_Holidays_
+func Test_bcal_BusinessCalendar_Holidays_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) Holidays(year int) []HolidayDate
+	//
+	uk := bcalCalendar(t, bcalFixtureUK)
+	TEqual(t, bcalDates(uk.Holidays(2021)), strings.TrimSpace(`
+2021-01-01 Fri New Year's Day
+2021-04-02 Fri Good Friday
+2021-04-05 Mon Easter Monday
+2021-05-03 Mon Early May bank holiday
+2021-05-31 Mon Spring bank holiday
+2021-08-30 Mon Summer bank holiday
+2021-12-25 Sat Christmas Day
+2021-12-26 Sun Boxing Day
+2021-12-27 Mon Christmas Day (observed)
+2021-12-28 Tue Boxing Day (observed)`))
+	//
+	// Christmas on Sunday is observed after Boxing Day
+	TEqual(t, bcalDates(uk.Holidays(2022)[7:]), strings.TrimSpace(`
+2022-12-25 Sun Christmas Day
+2022-12-26 Mon Boxing Day
+2022-12-27 Tue Christmas Day (observed)`))
+	//
+	// US holidays are observed on the nearest weekday,
+	// which can be in the previous year
+	us := bcalCalendar(t, bcalFixtureUS)
+	TEqual(t, bcalDates(us.Holidays(2021)), strings.TrimSpace(`
+2021-01-01 Fri New Year's Day
+2021-01-18 Mon Martin Luther King Day
+2021-05-31 Mon Memorial Day
+2021-07-04 Sun Independence Day
+2021-07-05 Mon Independence Day (observed)
+2021-09-06 Mon Labor Day
+2021-11-25 Thu Thanksgiving
+2021-12-24 Fri Christmas Day (observed)
+2021-12-25 Sat Christmas Day
+2021-12-31 Fri New Year's Day (observed)`))
+	TEqual(t, bcalDates(us.Holidays(2022)[:1]), "2022-01-01 Sat New Year's Day")
+	TTrue(t, us.IsHoliday(DateOf("2026-07-03")))
+	//
+	// the fifth weekday of a month doesn't occur every year
+	var cal BusinessCalendar
+	h, _ := HolidayOf("fifth Thu Feb", "Leap Thursday")
+	cal.AddHoliday(h)
+	TEqual(t, len(cal.Holidays(2024)), 1)
+	TEqual(t, len(cal.Holidays(2025)), 0)
+	h, _ = HolidayOf("02-29", "Leap Day")
+	cal.AddHoliday(h)
+	TEqual(t, len(cal.Holidays(2024)), 2)
+	TEqual(t, len(cal.Holidays(2023)), 0)
+	//
+	// the returned slice is a copy
+	days := uk.Holidays(2024)
+	days[0].Name = "changed"
+	TEqual(t, uk.Holidays(2024)[0].Name, "New Year's Day")
+	//
+	// changing Weekend recalculates the observed holidays
+	cal2 := bcalCalendar(t, bcalFixtureUK)
+	TEqual(t, len(cal2.Holidays(2021)), 10)
+	cal2.Weekend = []time.Weekday{}
+	TEqual(t, len(cal2.Holidays(2021)), 8)
+	cal2.Weekend = nil
+	TEqual(t, len(cal2.Holidays(2021)), 10)
+	//
+	// loading and using the calendar can be done concurrently
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 10; i++ {
+			cal2.LoadText(strings.NewReader("weekend: Fri Sat"))
+		}
+		done <- true
+	}()
+	for i := 0; i < 10; i++ {
+		cal2.IsWeekend(DateOf("2021-12-24"))
+	}
+	<-done
+	TEqual(t, cal2.Weekend, []time.Weekday{time.Friday, time.Saturday})
+} //                                        Test_bcal_BusinessCalendar_Holidays_
+
+// go test --run Test_bcal_BusinessCalendar_IsBusinessDay_
+func Test_bcal_BusinessCalendar_IsBusinessDay_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) IsBusinessDay(date time.Time) bool
+	// (ob *BusinessCalendar) IsHoliday(date time.Time) bool
+	// (ob *BusinessCalendar) IsWeekend(date time.Time) bool
+	//
+	uk := bcalCalendar(t, bcalFixtureUK)
+	test := func(cal *BusinessCalendar, date string, business, holiday,
+		weekend bool) {
+		day := DateOf(date)
+		if cal.IsBusinessDay(day) != business ||
+			cal.IsHoliday(day) != holiday || cal.IsWeekend(day) != weekend {
+			TFailf(t, `%s: IsBusinessDay %v, IsHoliday %v, IsWeekend %v`,
+				date, cal.IsBusinessDay(day), cal.IsHoliday(day),
+				cal.IsWeekend(day))
+		}
+	}
+	test(uk, "2024-03-08", true, false, false)
+	test(uk, "2024-03-09", false, false, true)
+	test(uk, "2024-03-29", false, true, false)
+	test(uk, "2021-12-25", false, true, true)
+	test(uk, "2021-12-27", false, true, false)
+	//
+	// weekend on Friday and Saturday
+	gulf := &BusinessCalendar{Weekend: []time.Weekday{
+		time.Friday, time.Saturday,
+	}}
+	test(gulf, "2024-03-08", false, false, true)
+	test(gulf, "2024-03-09", false, false, true)
+	test(gulf, "2024-03-10", true, false, false)
+	//
+	// no weekend
+	none := &BusinessCalendar{Weekend: []time.Weekday{}}
+	test(none, "2024-03-09", true, false, false)
+	//
+	// times in other time zones use their own calendar date
+	TTrue(t, uk.IsBusinessDay(
+		time.Date(2024, 3, 8, 23, 0, 0, 0, time.FixedZone("", -5*60*60))))
+	//
+	var nilCal *BusinessCalendar
+	TFalse(t, nilCal.IsHoliday(DateOf("2024-12-25")))
+	TTrue(t, nilCal.IsWeekend(DateOf("2024-03-09")))
+} //                                   Test_bcal_BusinessCalendar_IsBusinessDay_
+
+// go test --run Test_bcal_BusinessCalendar_LoadJSON_
+func Test_bcal_BusinessCalendar_LoadJSON_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) LoadJSON(r io.Reader) error
+	//
+	us := bcalCalendar(t, bcalFixtureUS)
+	TEqual(t, len(us.Holidays(2024)), 7)
+	TTrue(t, us.Weekend == nil)
+	//
+	// weekend and an array of holidays
+	var cal BusinessCalendar
+	err := cal.LoadJSON(strings.NewReader(
+		`{"weekend": ["fri", "Saturday"], "holidays": []}`))
+	TEqual(t, err, nil)
+	TEqual(t, cal.Weekend, []time.Weekday{time.Friday, time.Saturday})
+	err = cal.LoadJSON(strings.NewReader(
+		` [{"rule": "easter", "name": "Easter Sunday"}]`))
+	TEqual(t, err, nil)
+	TTrue(t, cal.IsHoliday(DateOf("2024-03-31")))
+	TTrue(t, cal.IsBusinessDay(DateOf("2024-04-07"))) // Sunday
+	//
+	// errors don't change the calendar
+	test := func(data, expectErr string) {
+		err := cal.LoadJSON(strings.NewReader(data))
+		if err == nil || !strings.Contains(err.Error(), expectErr) {
+			TFailf(t, `LoadJSON(%q) returned %v`, data, err)
+		}
+	}
+	test(`{"weekend": ["Sun"], "holidays": [{"rule": "12-25"},
+		{"rule": "13-25"}]}`, `holiday 2: invalid holiday rule "13-25"`)
+	test(`{"weekend": ["Sun", "Mo"]}`, `invalid weekend day "Mo"`)
+	test(`[{"rule": "12-25", "observed": "later"}]`,
+		`holiday 1: invalid observed value "later"`)
+	test(`{"holidays": {}}`, "failed parsing")
+	test(`[`, "failed parsing")
+	TEqual(t, cal.Weekend, []time.Weekday{time.Friday, time.Saturday})
+	TEqual(t, len(cal.Holidays(2024)), 1)
+} //                                        Test_bcal_BusinessCalendar_LoadJSON_
+
+// go test --run Test_bcal_BusinessCalendar_LoadText_
+func Test_bcal_BusinessCalendar_LoadText_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) LoadText(r io.Reader) error
+	//
+	uk := bcalCalendar(t, bcalFixtureUK)
+	TEqual(t, uk.Weekend, []time.Weekday{time.Saturday, time.Sunday})
+	TEqual(t, len(uk.Holidays(2024)), 8)
+	//
+	// more holidays can be loaded
+	err := uk.LoadText(strings.NewReader(
+		"2022-09-19: State funeral of Queen Elizabeth II\n"))
+	TEqual(t, err, nil)
+	TTrue(t, uk.IsHoliday(DateOf("2022-09-19")))
+	TFalse(t, uk.IsHoliday(DateOf("2023-09-19")))
+	//
+	// an empty weekend line means no weekend days
+	var cal BusinessCalendar
+	TEqual(t, cal.LoadText(strings.NewReader("weekend:\n")), nil)
+	TEqual(t, len(cal.Weekend), 0)
+	TTrue(t, cal.Weekend != nil)
+	TTrue(t, cal.IsBusinessDay(DateOf("2024-03-09")))
+	//
+	// errors specify the line and don't change the calendar
+	test := func(text, expectErr string) {
+		err := uk.LoadText(strings.NewReader(text))
+		if err == nil || !strings.Contains(err.Error(), expectErr) {
+			TFailf(t, `LoadText(%q) returned %v`, text, err)
+		}
+	}
+	test("weekend: Sun\n12-24: Christmas Eve\nfoo: bar",
+		`holiday 3: invalid holiday rule "foo"`)
+	test("# comment\n\n12-24", `holiday line 3: invalid "12-24"`)
+	test("12-24: a: b: c", `holiday line 1: invalid "12-24: a: b: c"`)
+	test("12-24: Christmas Eve: sometimes",
+		`holiday 1: invalid observed value "sometimes"`)
+	test("weekend: Sun Funday", `invalid weekend day "Funday"`)
+	TEqual(t, uk.Weekend, []time.Weekday{time.Saturday, time.Sunday})
+	TFalse(t, uk.IsHoliday(DateOf("2024-12-24")))
+} //                                        Test_bcal_BusinessCalendar_LoadText_
+
+// go test --run Test_bcal_BusinessCalendar_NextBusinessDay_
+func Test_bcal_BusinessCalendar_NextBusinessDay_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *BusinessCalendar) NextBusinessDay(date time.Time) time.Time
+	//
+	cal := bcalCalendar(t, bcalFixtureUK)
+	test := func(date, expect string) {
+		got := cal.NextBusinessDay(DateOf(date)).Format("2006-01-02")
+		if got != expect {
+			TFailf(t, `NextBusinessDay(%q) returned %q instead of %q`,
+				date, got, expect)
+		}
+	}
+	test("2024-03-07", "2024-03-08")
+	test("2024-03-08", "2024-03-11")
+	test("2024-03-09", "2024-03-11")
+	test("2024-03-28", "2024-04-02")
+	test("2021-12-24", "2021-12-29")
+	test("2021-12-31", "2022-01-04") // New Year's Day observed on Mon 3
+} //                                 Test_bcal_BusinessCalendar_NextBusinessDay_
+
+// end
@@ -9,17 +9,19 @@ package zr
 //   Calendar struct
 //   calendarDay struct
 //   calendarMonth struct
-//   calendarWeekdaysEN = []string
 //
 // # Methods (ob *Calendar)
 //   ) AddMonth(year int, month time.Month) error
 //   ) HasMonth(year int, month time.Month) bool
 //   ) Set(date, value interface{})
+//   ) SetRecurring(rule RRule, rng DateRange, value interface{})
 //   ) String() string
+//   ) StringLocale(loc DateLocale) string
 //
 // # Internal Methods/Functions
 //  (*Calendar) firstWeekday(year int, month time.Month) time.Weekday
 //  (ob *Calendar) getMonth(year int, month time.Month) *calendarMonth
+//  (mth *calendarMonth) layout(first time.Weekday) [6][7]calendarDay
 
 import (
 	"bytes"
@@ -60,17 +62,6 @@ type calendarMonth struct {
 	cells [6][7]calendarDay
 } //                                                               calendarMonth
 
-// calendarWeekdaysEN defines English weekday names
-var calendarWeekdaysEN = []string{
-	"Monday",
-	"Tuesday",
-	"Wednesday",
-	"Thursday",
-	"Friday",
-	"Saturday",
-	"Sunday",
-} //                                                          calendarWeekdaysEN
-
 // -----------------------------------------------------------------------------
 // # Methods (ob *Calendar)
 
@@ -157,6 +148,15 @@ func (ob *Calendar) Set(date, value interface{}) {
 	}
 } //                                                                         Set
 
+// SetRecurring assigns the specified value to every date of a recurring
+// series (see RRule) that falls within the date range, e.g. to plot
+// the dates on which monthly invoices are due.
+func (ob *Calendar) SetRecurring(rule RRule, rng DateRange, value interface{}) {
+	for _, date := range rule.Occurrences(rng) {
+		ob.Set(date, value)
+	}
+} //                                                                SetRecurring
+
 // SetWeekTotals disables or enables weekly subtotals.
 func (ob *Calendar) SetWeekTotals(v bool) {
 	ob.weekTotals = v
@@ -168,9 +168,16 @@ func (ob *Calendar) SetWeekTotals(v bool) {
 // The output may contain multiple months, in which
 // case the months are arranged in ascending order.
 //
-// See the sample output in the body of the function.
+// See the sample output in the body of StringLocale().
 //
 func (ob *Calendar) String() string {
+	return ob.StringLocale(dateLocaleEN)
+} //                                                                      String
+
+// StringLocale returns the calendar as a text string like String(),
+// but with the month and weekday names of the specified locale, and
+// with weeks starting on the locale's first day of the week.
+func (ob *Calendar) StringLocale(loc DateLocale) string {
 	// Sample output:
 	//
 	// 2018 FEBRUARY
@@ -254,7 +261,8 @@ func (ob *Calendar) String() string {
 	for _, mth := range ob.months {
 		//
 		// month heading
-		ws(strings.ToUpper(fmt.Sprintf("%d %v", mth.year, mth.month)), "\n")
+		ws(strings.ToUpper(fmt.Sprintf("%d %s",
+			mth.year, loc.monthName(mth.month, false))), "\n")
 		outerHLine()
 		//
 		// weekday names
@@ -264,13 +272,19 @@ func (ob *Calendar) String() string {
 				ws(fmt.Sprintf(weekdayFmt, "TOTAL"))
 				continue
 			}
-			ws(fmt.Sprintf(weekdayFmt, calendarWeekdaysEN[i][:3]))
+			day := time.Weekday((int(loc.FirstWeekday) + i) % 7)
+			name := []rune(loc.weekdayName(day, true))
+			if len(name) > CellWidth-2 {
+				name = name[:CellWidth-2]
+			}
+			ws(fmt.Sprintf(weekdayFmt, string(name)))
 		}
 		ws(VDiv, "\n")
 		var sum float64
 		var sumFH int // sum of full hours (daily hours rounded-down)
 		//
 		// draw the grid
+		cells := mth.layout(loc.FirstWeekday)
 		for row := 0; row < 6; row++ {
 			innerHLine()
 			//
@@ -280,7 +294,7 @@ func (ob *Calendar) String() string {
 				ws(VDiv)
 				if ob.weekTotals {
 					if col >= 0 && col <= 6 {
-						v := mth.cells[row][col].value
+						v := cells[row][col].value
 						if v, ok := v.(float64); ok {
 							weekSumFH += int(math.Floor(v))
 						}
@@ -296,7 +310,7 @@ func (ob *Calendar) String() string {
 				}
 				day := 0
 				if col >= 0 && col <= 6 {
-					day = mth.cells[row][col].day
+					day = cells[row][col].day
 				}
 				if day == 0 {
 					ws(blank)
@@ -319,7 +333,7 @@ func (ob *Calendar) String() string {
 					ws(s)
 					continue
 				}
-				v := mth.cells[row][col].value
+				v := cells[row][col].value
 				if v == nil {
 					ws(blank)
 					continue
@@ -342,7 +356,7 @@ func (ob *Calendar) String() string {
 		ws(numStr(sum), "\n\n")
 	} // mth
 	return retBuf.String()
-} //                                                                      String
+} //                                                                StringLocale
 
 // -----------------------------------------------------------------------------
 // # Internal Methods/Functions
@@ -365,4 +379,23 @@ func (ob *Calendar) getMonth(year int, month time.Month) *calendarMonth {
 	return nil
 } //                                                                    getMonth
 
+// layout returns the days of the month arranged in
+// a 6 x 7 grid, with weeks starting on 'first'
+func (mth *calendarMonth) layout(first time.Weekday) [6][7]calendarDay {
+	var (
+		ret   [6][7]calendarDay
+		start = time.Date(mth.year, mth.month, 1, 0, 0, 0, 0, time.UTC)
+		i     = (int(start.Weekday()) - int(first) + 7) % 7
+	)
+	for row := range mth.cells {
+		for _, cell := range mth.cells[row] {
+			if cell.day != 0 {
+				ret[i/7][i%7] = cell
+				i++
+			}
+		}
+	}
+	return ret
+} //                                                                      layout
+
 // end
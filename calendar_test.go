@@ -8,6 +8,7 @@ package zr
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 //  to test all items in calendar.go use:
@@ -224,4 +225,83 @@ func Test_Calendar_3(t *testing.T) {
 	TEqual(t, got, strings.TrimSpace(expect))
 } //                                                             Test_Calendar_3
 
+// go test --run Test_Calendar_4
+func Test_Calendar_4(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *Calendar) StringLocale(loc DateLocale) string
+	//
+	var ret Calendar
+	ret.Set("2024-03-01", 1.5)
+	ret.Set("2024-03-03", 2.0)
+	ret.Set("2024-03-31", 4.25)
+	//
+	// weeks start on Sunday in the US
+	us, _ := DateLocaleOf("en-US")
+	got := ret.StringLocale(us)
+	const expect = `
+2024 MARCH
+*--------------------------------------------------------------*
+|  Sun   |  Mon   |  Tue   |  Wed   |  Thu   |  Fri   |  Sat   |
+|--------|--------|--------|--------|--------|--------|--------|
+|        |        |        |        |        | 1      | 2      |
+|        |        |        |        |        |    1.5 |        |
+|--------|--------|--------|--------|--------|--------|--------|
+| 3      | 4      | 5      | 6      | 7      | 8      | 9      |
+|      2 |        |        |        |        |        |        |
+|--------|--------|--------|--------|--------|--------|--------|
+| 10     | 11     | 12     | 13     | 14     | 15     | 16     |
+|        |        |        |        |        |        |        |
+|--------|--------|--------|--------|--------|--------|--------|
+| 17     | 18     | 19     | 20     | 21     | 22     | 23     |
+|        |        |        |        |        |        |        |
+|--------|--------|--------|--------|--------|--------|--------|
+| 24     | 25     | 26     | 27     | 28     | 29     | 30     |
+|        |        |        |        |        |        |        |
+|--------|--------|--------|--------|--------|--------|--------|
+| 31     |        |        |        |        |        |        |
+|   4.25 |        |        |        |        |        |        |
+*--------------------------------------------------------------*
+(7)
+ 7.75`
+	TEqual(t, strings.TrimSpace(got), strings.TrimSpace(expect))
+	//
+	// German names, with weeks starting on Monday
+	de, _ := DateLocaleOf("de")
+	got = ret.StringLocale(de)
+	TTrue(t, strings.HasPrefix(strings.TrimSpace(got), "2024 MÄRZ\n"))
+	TTrue(t, strings.Contains(got,
+		"|  Mo    |  Di    |  Mi    |  Do    |  Fr    |  Sa    |  So    |"))
+	TTrue(t, strings.Contains(got,
+		"|        |        |        |        | 1      | 2      | 3      |"))
+	//
+	// String() is the same as the British English locale
+	gb, _ := DateLocaleOf("en-GB")
+	TEqual(t, ret.String(), ret.StringLocale(gb))
+} //                                                             Test_Calendar_4
+
+// go test --run Test_Calendar_5
+func Test_Calendar_5(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *Calendar) SetRecurring(rule RRule, rng DateRange, value interface{})
+	//
+	var ret Calendar
+	rule, _ := ParseRRule("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1")
+	ret.SetRecurring(rule, DateRangeOf("2024-02 to 2024-03"), 250.0)
+	got := ret.String()
+	TTrue(t, ret.HasMonth(2024, time.February))
+	TTrue(t, ret.HasMonth(2024, time.March))
+	TFalse(t, ret.HasMonth(2024, time.April))
+	//
+	// the last business days are Thu 29 February and Fri 29 March
+	TTrue(t, strings.Contains(got, `
+| 26     | 27     | 28     | 29     |        |        |        |
+|        |        |        |    250 |        |        |        |`))
+	TTrue(t, strings.Contains(got, `
+| 25     | 26     | 27     | 28     | 29     | 30     | 31     |
+|        |        |        |        |    250 |        |        |`))
+	TEqual(t, strings.Count(got, "|    250 |"), 2)
+} //                                                             Test_Calendar_5
+
 // end
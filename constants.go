@@ -12,6 +12,12 @@ package zr
 // # Error Message Constants
 
 const (
+	// ECurrencyMismatch indicates an operation on different currencies.
+	ECurrencyMismatch = "currency mismatch"
+
+	// EDivByZero indicates a division by zero.
+	EDivByZero = "division by zero"
+
 	// EFailedOperation _ _
 	EFailedOperation = "failed operation"
 
@@ -13,6 +13,10 @@ package zr
 // # Currency Type:
 //   Currency struct
 //
+// # Settings:
+//   CurrencyRounding() RoundingMode
+//   SetCurrencyRounding(mode RoundingMode)
+//
 // # Currency Factories:
 //   CurrencyE(value interface{}) (Currency, error)
 //   CurrencyOf(value interface{}) Currency
@@ -22,26 +26,42 @@ package zr
 // # String Output:
 //   (n Currency) GoString() string
 //   (n Currency) Fmt(decimalPlaces int) string
-//   (n Currency) InWordsEN(fmt string) string
+//   (n Currency) InWordsEN(fmt string, opts ...InWordsOptions) string
 //   (n Currency) String() string
 //
+// # Rounding:
+//   (n Currency) Round(decimalPlaces int, mode RoundingMode) Currency
+//
+// # Allocation:
+//   (n Currency) Allocate(ratios ...int) []Currency
+//   (n Currency) AllocateAt(decimalPlaces int, ratios ...int) []Currency
+//   (n Currency) Split(parts int) []Currency
+//
 // # Division:
 //   (n Currency) Div(nums... Currency) Currency
+//   (n Currency) DivE(nums ...Currency) (Currency, error)
 //   (n Currency) DivFloat(nums...float64) Currency
+//   (n Currency) DivFloatRound(mode RoundingMode, nums ...float64) Currency
 //   (n Currency) DivInt(nums...int) Currency
+//   (n Currency) DivRound(mode RoundingMode, nums ...Currency) Currency
 //
 // # Multiplication:
 //   (n Currency) Mul(nums... Currency) Currency
+//   (n Currency) MulE(nums ...Currency) (Currency, error)
 //   (n Currency) MulFloat(nums...float64) Currency
+//   (n Currency) MulFloatRound(mode RoundingMode, nums ...float64) Currency
 //   (n Currency) MulInt(nums...int) Currency
+//   (n Currency) MulRound(mode RoundingMode, nums ...Currency) Currency
 //
 // # Addition:
 //   (n Currency) Add(nums... Currency) Currency
+//   (n Currency) AddE(nums ...Currency) (Currency, error)
 //   (n Currency) AddFloat(nums...float64) Currency
 //   (n Currency) AddInt(nums...int) Currency
 //
 // # Subtraction:
 //   (n Currency) Sub(nums... Currency) Currency
+//   (n Currency) SubE(nums ...Currency) (Currency, error)
 //   (n Currency) SubFloat(nums...float64) Currency
 //   (n Currency) SubInt(nums...int) Currency
 //
@@ -55,6 +75,7 @@ package zr
 //   (n Currency) IsLesser(value interface{}) bool
 //   (n Currency) IsLesserOrEqual(value interface{}) bool
 //   (n Currency) IsNegative() bool
+//   (n Currency) IsOverflow() bool
 //   (n Currency) IsZero() bool
 //   (n Currency) Overflow() int
 //   (n Currency) Raw() int64
@@ -62,13 +83,45 @@ package zr
 // # JSON:
 //   (n Currency) MarshalJSON() ([]byte, error)
 //   (n *Currency) UnmarshalJSON(data []byte) error
+//
+// # Text, Binary and XML Encoding:
+//   (n Currency) MarshalBinary() ([]byte, error)
+//   (n Currency) MarshalText() ([]byte, error)
+//   (n Currency) MarshalXML(enc *xml.Encoder, start xml.StartElement) error
+//   (n *Currency) UnmarshalBinary(data []byte) error
+//   (n *Currency) UnmarshalText(text []byte) error
+//   (n *Currency) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error
 
-// # Helper Function
+// # Helper Functions
 //   currencyOverflow(isNegative bool, a ...interface{}) Currency
+//   currencyFloatRat(num float64) *big.Rat
+//   currencyOfBig(x *big.Int, a ...interface{}) Currency
+//   currencyAdd(a, b Currency) (Currency, error)
+//   currencyDiv(a, b Currency, mode RoundingMode) (Currency, error)
+//   currencyFloatOp(
+//       n Currency, nums []float64, op func(a, b Currency) (Currency, error),
+//   ) Currency
+//   currencyIntOp(
+//       n Currency, nums []int, op func(a, b Currency) (Currency, error),
+//   ) Currency
+//   currencyLogError(err error)
+//   currencyMul(a, b Currency, mode RoundingMode) (Currency, error)
+//   currencyOfBigE(x *big.Int, a ...interface{}) (Currency, error)
+//   currencyOfFloat(num float64) (Currency, error)
+//   currencyOfInt(num int64) (Currency, error)
+//   currencyOverflowE(isNegative bool, a ...interface{}) (Currency, error)
+//   currencyOverflowOf(a, b Currency) (Currency, error)
+//   currencySub(a, b Currency) (Currency, error)
+//   currencyParse(s string, mode RoundingMode) (Currency, error)
+//   currencyParseBig(
+//       s string, mode RoundingMode, maxShift int,
+//   ) (*big.Int, error)
 
 import (
-	// "encoding/json" // used via mod.json.* mockable
 	"bytes"
+	"encoding/binary"
+	"encoding/json" // json.Unmarshal is used via mod.json.* (mockable)
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"math"
@@ -76,6 +129,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // -----------------------------------------------------------------------------
@@ -103,6 +157,19 @@ const (
 // big1E4 scales the int64 to provide 4 decimal places.
 var big1E4 = big.NewInt(1e4)
 
+// currencyRounding specifies how to round numbers that have more
+// than 4 decimal places when they are converted to Currency. It holds
+// a RoundingMode and is accessed atomically, since conversions can
+// run in several goroutines. Use CurrencyRounding() to read it and
+// SetCurrencyRounding() to change it.
+var currencyRounding = int32(RoundDown)
+
+// errCurrencyOverflowOperand is returned by checked arithmetic when an
+// operand already holds an overflow value. The overflow is kept,
+// so it can't get lost in further calculations.
+var errCurrencyOverflowOperand = errors.New(
+	EOverflow + ": operand is an overflow value")
+
 // -----------------------------------------------------------------------------
 // # Currency Type:
 
@@ -113,6 +180,25 @@ type Currency struct {
 	i64 int64
 } //                                                                    Currency
 
+// -----------------------------------------------------------------------------
+// # Settings:
+
+// CurrencyRounding returns the rounding mode used when converting
+// strings and floating-point numbers with more than 4 decimal places
+// to Currency. The default is RoundDown, which truncates the decimals.
+func CurrencyRounding() RoundingMode {
+	return RoundingMode(atomic.LoadInt32(&currencyRounding))
+} //                                                            CurrencyRounding
+
+// SetCurrencyRounding changes the rounding mode used when converting
+// strings and floating-point numbers with more than 4 decimal places
+// to Currency. The setting applies to CurrencyE(), CurrencyOf(),
+// CurrencyOfS() and UnmarshalJSON(). It is safe to call while
+// other goroutines are converting values.
+func SetCurrencyRounding(mode RoundingMode) {
+	atomic.StoreInt32(&currencyRounding, int32(mode))
+} //                                                         SetCurrencyRounding
+
 // -----------------------------------------------------------------------------
 // # Currency Factories:
 
@@ -122,9 +208,14 @@ type Currency struct {
 // - Dereferences pointers to evaluate the pointed-to type.
 // - Converts nil to 0.
 // - Converts signed and unsigned integers, and floats to Currency.
-// - Converts numeric strings to Currency.
+// - Converts numeric strings and json.Number to Currency.
+// - Converts BigCurrency to Currency, or to an overflow value.
 // - Converts boolean true to 1, false to 0.
 //
+// Strings and floating-point numbers are converted using their exact
+// decimal representation. Decimals beyond the 4th decimal place
+// are rounded as specified by SetCurrencyRounding().
+//
 // Note: fmt.Stringer (or fmt.GoStringer) interfaces are not treated as
 // strings to avoid bugs from implicit conversion. Use the String method.
 //
@@ -144,15 +235,30 @@ func CurrencyE(value interface{}) (Currency, error) {
 	case float64, float32:
 		{
 			n := reflect.ValueOf(value).Float()
+			if math.IsNaN(n) {
+				return Currency{0}, errors.New(EInvalidArg + ": NaN")
+			}
 			if n < -float64(CurrencyIntLimit)-0.9999 ||
 				n > float64(CurrencyIntLimit)+0.9999 {
 				return currencyOverflow(n < 0, n), errors.New(EOverflow)
 			}
-			return Currency{int64(n * 1e4)}, nil
+			// use the shortest decimal representation of the float,
+			// to avoid errors like 0.29 * 1e4 = 2899.9999999999995
+			bitSize := 64
+			if _, ok := value.(float32); ok {
+				bitSize = 32
+			}
+			s := strconv.FormatFloat(n, 'f', -1, bitSize)
+			return currencyParse(s, CurrencyRounding())
 		}
-	case string:
+	case string, json.Number:
 		{
-			return CurrencyOfS(v), nil
+			s := reflect.ValueOf(v).String()
+			ret, err := currencyParse(s, CurrencyRounding())
+			if err != nil && strings.HasPrefix(err.Error(), EOverflow) {
+				currencyOverflow(ret.i64 < 0, s)
+			}
+			return ret, err
 		}
 	case uint, uint64, uint32, uint16, uint8:
 		{
@@ -166,6 +272,14 @@ func CurrencyE(value interface{}) (Currency, error) {
 		{
 			return v, nil
 		}
+	case BigCurrency:
+		{
+			ret, err := v.CurrencyE()
+			if err != nil {
+				currencyOverflow(ret.i64 < 0, v)
+			}
+			return ret, err
+		}
 	case bool:
 		{
 			if v {
@@ -217,41 +331,23 @@ func CurrencyOf(value interface{}) Currency {
 } //                                                                  CurrencyOf
 
 // CurrencyOfS converts a numeric string to a Currency.
+//
+// The string is parsed exactly, without converting it to a floating-point
+// number. It may contain a leading sign, commas and white-spaces (which
+// are ignored), a decimal point and an exponent, e.g. "-1,234.5e2".
+// Decimals beyond the 4th decimal place are rounded
+// as specified by SetCurrencyRounding().
+//
 // If the string is not numeric, logs an error and sets the Currency to zero.
+// If the number is too big for Currency, logs an error
+// and returns a negative or positive overflow value.
 func CurrencyOfS(s string) Currency {
-	var (
-		minus bool
-		fract bool
-		dp    int
-		ret   Currency
-	)
-	for _, r := range s {
-		if r == '-' {
-			minus = true
-		} else if r == '.' {
-			fract = true
-		} else if r >= '0' && r <= '9' {
-			if fract {
-				dp++
-				if dp > 4 {
-					break
-				}
-			}
-			ret.i64 *= 10
-			ret.i64 += int64(r - '0')
-		} else if r != ',' &&
-			r != ' ' && r != '\a' && r != '\b' && r != '\f' &&
-			r != '\n' && r != '\r' && r != '\t' && r != '\v' {
-			mod.Error("Non-numeric string:^", s)
-			break
+	ret, err := currencyParse(s, CurrencyRounding())
+	if err != nil {
+		if strings.HasPrefix(err.Error(), EOverflow) {
+			return currencyOverflow(ret.i64 < 0, s)
 		}
-	}
-	for dp < 4 {
-		dp++
-		ret.i64 *= 10
-	}
-	if minus {
-		ret.i64 = -ret.i64
+		mod.Error("Non-numeric string:^", s, ":", err)
 	}
 	return ret
 } //                                                                 CurrencyOfS
@@ -289,6 +385,7 @@ func (n Currency) Fmt(decimalPlaces int) string {
 	)
 	if negative {
 		intPart = -intPart
+		decPart = -decPart
 		ws("-")
 	}
 	// calculate length of number's integer part
@@ -376,6 +473,8 @@ func (n Currency) Fmt(decimalPlaces int) string {
 //      If both specifiers are omitted for either denomination, the
 //      denomination will not be returned. See the examples below.
 //
+// opts: optionally specifies the number words style (see InWordsOptions).
+//
 // Returns: The amount in words as a string, including the currency
 //          and the word "Only". Uses proper capitalisation.
 //
@@ -384,76 +483,8 @@ func (n Currency) Fmt(decimalPlaces int) string {
 //          (11.02,"Dollar;;Cent")  "Eleven Dollars and Two Cents"
 //          (11.02,"Euro")          "Eleven Euros"
 //          (11.02,"Pound;;;Pence") "Eleven Pounds and Two Pence"
-func (n Currency) InWordsEN(fmt string) string {
-	i := n.i64
-	if i < 0 {
-		i = -n.i64
-	}
-	var (
-		bigUnits = i / 1e4
-		smlUnits = (i - bigUnits*1e4) / 100
-		hasOnly  = strings.HasSuffix(strings.ToLower(fmt), "only")
-	)
-	if hasOnly {
-		fmt = fmt[:len(fmt)-4]
-	}
-	getPart := func(partNo int) string {
-		parts := strings.Split(fmt, ";")
-		if partNo < 0 || partNo >= len(parts) {
-			return ""
-		}
-		return parts[partNo]
-	}
-	var (
-		big1 = getPart(0)
-		bigN = getPart(1)
-		sml1 = getPart(2)
-		smlN = getPart(3)
-		ret  = ""
-	)
-	if bigUnits > 0 && (big1+bigN) != "" {
-		ret += IntInWordsEN(bigUnits) + " "
-		if big1 == "" && bigN != "" {
-			ret += bigN
-		} else if big1 != "" && bigN == "" {
-			ret += big1
-			if bigUnits > 1 {
-				ret += "s"
-			}
-		} else if big1 != "" && bigN != "" {
-			if bigUnits == 1 {
-				ret += big1
-			}
-			if bigUnits > 1 {
-				ret += bigN
-			}
-		}
-	}
-	if ((sml1 + smlN) != "") && smlUnits > 0 {
-		if (big1+bigN != "") && bigUnits > 0 {
-			ret += " and "
-		}
-		ret += IntInWordsEN(smlUnits) + " "
-		if sml1 == "" && smlN != "" {
-			ret += smlN
-		} else if sml1 != "" && smlN == "" {
-			ret += sml1
-			if smlUnits > 1 {
-				ret += "s"
-			}
-		} else if sml1 != "" && smlN != "" {
-			if smlUnits == 1 {
-				ret += sml1
-			}
-			if smlUnits > 1 {
-				ret += smlN
-			}
-		}
-	}
-	if hasOnly && len(strings.TrimSpace(ret)) > 0 {
-		ret += " Only"
-	}
-	return ret
+func (n Currency) InWordsEN(fmt string, opts ...InWordsOptions) string {
+	return AmountInWordsEN(n, fmt, opts...)
 } //                                                                   InWordsEN
 
 // String returns a string representing the currency value
@@ -480,98 +511,295 @@ func (n Currency) String() string {
 	return sint + sdec
 } //                                                                      String
 
+// -----------------------------------------------------------------------------
+// # Rounding:
+
+// Round rounds the currency value to the specified number of decimal
+// places using the given rounding mode, and returns the result.
+// The object's value isn't changed.
+//
+// decimalPlaces can range from 4 (no change) to -14. Negative values
+// round to tens (-1), hundreds (-2), thousands (-3) and so on.
+//
+// Example: CurrencyOf("2.345").Round(2, RoundHalfEven) returns 2.34
+//          CurrencyOf("2.345").Round(2, RoundHalfUp)   returns 2.35
+func (n Currency) Round(decimalPlaces int, mode RoundingMode) Currency {
+	if decimalPlaces >= 4 {
+		return n
+	}
+	if n.IsOverflow() {
+		currencyLogError(errCurrencyOverflowOperand)
+		return n
+	}
+	if decimalPlaces < -14 {
+		mod.Error(EInvalidArg, "^decimalPlaces", ":", decimalPlaces)
+		return n
+	}
+	unit := int64(1)
+	for i := decimalPlaces; i < 4; i++ {
+		unit *= 10
+	}
+	q := roundQuoInt64(n.i64, unit, mode)
+	if q > MaxCurrencyI64/unit || q < MinCurrencyI64/unit {
+		return currencyOverflow(q < 0, n, " rounded to ", decimalPlaces)
+	}
+	return Currency{q * unit}
+} //                                                                       Round
+
+// -----------------------------------------------------------------------------
+// # Allocation:
+
+// Allocate distributes the currency value into parts proportional to
+// the given ratios, without losing any fractions: the returned parts
+// always add up exactly to the original value.
+//
+// The remainder that can't be divided evenly is distributed one
+// unit (0.0001) at a time, to the parts in the order specified.
+// The object's value isn't changed.
+//
+// Ratios must not be negative and at least one must be greater than
+// zero, otherwise logs an error and returns nil. Also logs an error
+// and returns nil if the currency holds an overflow value.
+//
+// Example: CurrencyOf(100).Allocate(1, 1, 1)
+//          returns 33.3334, 33.3333, 33.3333
+func (n Currency) Allocate(ratios ...int) []Currency {
+	return n.AllocateAt(4, ratios...)
+} //                                                                    Allocate
+
+// AllocateAt distributes the currency value into parts proportional to
+// the given ratios, like Allocate(), but each part is a multiple of the
+// unit specified by decimalPlaces. E.g. 2 allocates whole cents, 0 whole
+// dollars. The returned parts always add up exactly to the original value.
+//
+// The remainder that can't be divided evenly is distributed one unit
+// at a time, to the parts in the order specified. If the value itself
+// has more decimals than decimalPlaces, the excess fraction is added
+// to the first part with a non-zero ratio.
+// The object's value isn't changed.
+//
+// Example: CurrencyOf(100).AllocateAt(2, 1, 1, 1)
+//          returns 33.34, 33.33, 33.33
+func (n Currency) AllocateAt(decimalPlaces int, ratios ...int) []Currency {
+	if decimalPlaces > 4 {
+		decimalPlaces = 4
+	}
+	if decimalPlaces < -14 {
+		mod.Error(EInvalidArg, "^decimalPlaces", ":", decimalPlaces)
+		return nil
+	}
+	if n.IsOverflow() {
+		mod.Error(EOverflow, ": can not allocate an overflow value")
+		return nil
+	}
+	total := int64(0)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			mod.Error(EInvalidArg, "negative ^ratio", ":", ratio)
+			return nil
+		}
+		total += int64(ratio)
+	}
+	if total == 0 {
+		mod.Error(EInvalidArg, "^ratios", ":", ratios)
+		return nil
+	}
+	unit := int64(1)
+	for i := decimalPlaces; i < 4; i++ {
+		unit *= 10
+	}
+	var (
+		units = n.i64 / unit // whole units to allocate
+		rest  = n.i64 % unit // excess fraction of a unit
+		ret   = make([]Currency, len(ratios))
+		left  = units
+		x     = new(big.Int)
+		bigU  = big.NewInt(units)
+		bigT  = big.NewInt(total)
+	)
+	for i, ratio := range ratios {
+		x.Mul(bigU, big.NewInt(int64(ratio)))
+		x.Quo(x, bigT)
+		ret[i].i64 = x.Int64()
+		left -= ret[i].i64
+	}
+	// distribute the remaining units, one at a time
+	step := int64(1)
+	if left < 0 {
+		step = -1
+	}
+	for i := 0; left != 0; i = (i + 1) % len(ratios) {
+		if ratios[i] == 0 {
+			continue
+		}
+		ret[i].i64 += step
+		left -= step
+	}
+	for i, ratio := range ratios {
+		ret[i].i64 *= unit
+		if rest != 0 && ratio != 0 {
+			ret[i].i64 += rest
+			rest = 0
+		}
+	}
+	return ret
+} //                                                                  AllocateAt
+
+// Split divides the currency value into the specified number of equal
+// parts, without losing any fractions: the returned parts always add
+// up exactly to the original value. The remainder that can't be divided
+// evenly is added one unit (0.0001) at a time, starting from the first
+// part. Use AllocateAt() to split into larger units such as cents.
+//
+// If parts is less than 1, logs an error and returns nil.
+func (n Currency) Split(parts int) []Currency {
+	if parts < 1 {
+		mod.Error(EInvalidArg, "^parts", ":", parts)
+		return nil
+	}
+	ratios := make([]int, parts)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return n.AllocateAt(4, ratios...)
+} //                                                                       Split
+
 // -----------------------------------------------------------------------------
 // # Division:
 
 // Div divides a currency object by one or more currency values
 // and returns the result. The object's value isn't changed.
+// The result is truncated to 4 decimal places.
+//
+// If there is an overflow or a division by zero, logs an error and
+// returns math.MinInt64 or math.MaxInt64 depending on the result's sign.
+// See DivE() for a version that returns an error instead of logging it.
 func (n Currency) Div(nums ...Currency) Currency {
+	ret, err := n.DivE(nums...)
+	currencyLogError(err)
+	return ret
+} //                                                                         Div
+
+// DivE divides a currency object by one or more currency values
+// and returns the result truncated to 4 decimal places, or an
+// overflow value and an error if the result doesn't fit in Currency,
+// if a divisor is zero, or if any operand is an overflow value.
+// Does not log errors. The object's value isn't changed.
+func (n Currency) DivE(nums ...Currency) (Currency, error) {
+	var err error
 	for _, num := range nums {
-		n.i64 *= 1e4
-		n.i64 /= num.i64
+		n, err = currencyDiv(n, num, RoundDown)
+		if err != nil {
+			break
+		}
 	}
-	return n
-} //                                                                         Div
+	return n, err
+} //                                                                        DivE
 
 // DivFloat divides a currency object by one or more floating-point
 // numbers and returns the result. The object's value isn't changed.
+// Each number is first truncated to 4 decimal places.
 func (n Currency) DivFloat(nums ...float64) Currency {
+	return currencyFloatOp(n, nums,
+		func(a, b Currency) (Currency, error) {
+			return currencyDiv(a, b, RoundDown)
+		})
+} //                                                                    DivFloat
+
+// DivFloatRound divides a currency object by one or more floating-point
+// numbers and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+//
+// Each float is used with its shortest decimal representation,
+// so dividing by 1.1 divides by exactly 11/10.
+func (n Currency) DivFloatRound(mode RoundingMode, nums ...float64) Currency {
 	for _, num := range nums {
-		n.i64 *= 1e4
-		n.i64 /= int64(num * 1e4)
+		if n.IsOverflow() {
+			currencyLogError(errCurrencyOverflowOperand)
+			break
+		}
+		r := currencyFloatRat(num)
+		if r == nil || r.Sign() == 0 {
+			return currencyOverflow(n.i64 < 0, n, " / ", num)
+		}
+		x := new(big.Int).Mul(big.NewInt(n.i64), r.Denom())
+		n = currencyOfBig(roundQuo(x, r.Num(), mode), n, " / ", num)
 	}
 	return n
-} //                                                                    DivFloat
+} //                                                               DivFloatRound
 
 // DivInt divides a currency object by one or more integer values
 // and returns the result. The object's value isn't changed.
 func (n Currency) DivInt(nums ...int) Currency {
+	return currencyIntOp(n, nums,
+		func(a, b Currency) (Currency, error) {
+			return currencyDiv(a, b, RoundDown)
+		})
+} //                                                                      DivInt
+
+// DivRound divides a currency object by one or more currency values
+// and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+func (n Currency) DivRound(mode RoundingMode, nums ...Currency) Currency {
+	var err error
 	for _, num := range nums {
-		n.i64 *= 1e4
-		n.i64 /= (int64(num) * 1e4)
+		n, err = currencyDiv(n, num, mode)
+		if err != nil {
+			currencyLogError(err)
+			break
+		}
 	}
 	return n
-} //                                                                      DivInt
+} //                                                                    DivRound
 
 // -----------------------------------------------------------------------------
 // # Multiplication:
 
 // Mul multiplies a currency object by one or more currency values
 // and returns the result. The object's value isn't changed.
+// The result is truncated to 4 decimal places.
+//
+// If there is an overflow, logs an error and returns math.MinInt64
+// or math.MaxInt64 depending on if the result is negative.
+// See MulE() for a version that returns an error instead of logging it.
 func (n Currency) Mul(nums ...Currency) Currency {
+	ret, err := n.MulE(nums...)
+	currencyLogError(err)
+	return ret
+} //                                                                         Mul
+
+// MulE multiplies a currency object by one or more currency values
+// and returns the result truncated to 4 decimal places, or an
+// overflow value and an error if the result doesn't fit in
+// Currency or if any operand is an overflow value.
+// Does not log errors. The object's value isn't changed.
+func (n Currency) MulE(nums ...Currency) (Currency, error) {
+	var err error
 	for _, num := range nums {
-		var (
-			a = n.i64
-			b = num.i64
-		)
-		// return zero if either number is zero
-		if a == 0 || b == 0 {
-			return Currency{0}
-		}
-		// if direct multiplication will overflow int64, use big.Int
-		lim := MaxCurrencyI64 / a
-		if lim < 0 {
-			lim = -lim
-		}
-		if b >= lim || b <= -lim {
-			x := big.NewInt(a)
-			x.Mul(x, big.NewInt(b))
-			x.Div(x, big1E4)
-			//
-			// if result can't be stored in Currency, return overflow
-			//
-			// TODO: IsInt64() is not available in older Go versions ``
-			overflow := !x.IsInt64()
-			var ret int64
-			if !overflow {
-				ret = x.Int64()
-				if ret < MinCurrencyI64 || ret > MaxCurrencyI64 {
-					overflow = true
-				}
-			}
-			if overflow {
-				return currencyOverflow(
-					(a < 0 || b < 0) && (a > 0 || b > 0),
-					a, " * ", b, " = ", x)
-			}
-			return Currency{ret}
+		n, err = currencyMul(n, num, RoundDown)
+		if err != nil {
+			break
 		}
-		n.i64 = a * b / 1e4
 	}
-	return n
-} //                                                                         Mul
+	return n, err
+} //                                                                        MulE
 
 // MulFloat multiplies a currency object by one or more floating-point
 // numbers and returns the result. The object's value isn't changed.
 func (n Currency) MulFloat(nums ...float64) Currency {
-	a := float64(n.i64)
 	for _, b := range nums {
+		if n.IsOverflow() {
+			currencyLogError(errCurrencyOverflowOperand)
+			break
+		}
+		a := float64(n.i64)
+		//
 		// check for negative or positive overflow
 		lim := MaxCurrencyI64 / a
 		if lim < 0 {
 			lim = -lim
 		}
-		if b < -lim || b > lim {
+		if math.IsNaN(b) || b < -lim || b > lim {
 			return currencyOverflow(
 				(a < 0 || b < 0) && (a > 0 || b > 0),
 				a, " * ", b, " = ", a*b)
@@ -582,67 +810,96 @@ func (n Currency) MulFloat(nums ...float64) Currency {
 	return n
 } //                                                                    MulFloat
 
+// MulFloatRound multiplies a currency object by one or more floating-point
+// numbers and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+//
+// Each float is used with its shortest decimal representation,
+// so multiplying by 1.1 multiplies by exactly 11/10.
+func (n Currency) MulFloatRound(mode RoundingMode, nums ...float64) Currency {
+	for _, num := range nums {
+		if n.IsOverflow() {
+			currencyLogError(errCurrencyOverflowOperand)
+			break
+		}
+		r := currencyFloatRat(num)
+		if r == nil {
+			return currencyOverflow(n.i64 < 0, n, " * ", num)
+		}
+		x := new(big.Int).Mul(big.NewInt(n.i64), r.Num())
+		n = currencyOfBig(roundQuo(x, r.Denom(), mode), n, " * ", num)
+	}
+	return n
+} //                                                               MulFloatRound
+
 // MulInt multiplies a currency object by one or more integer values
 // and returns the result. The object's value isn't changed.
 func (n Currency) MulInt(nums ...int) Currency {
 	for _, num := range nums {
-		n = n.Mul(Currency{int64(num * 1e4)})
+		if n.IsOverflow() {
+			currencyLogError(errCurrencyOverflowOperand)
+			break
+		}
+		x := new(big.Int).Mul(big.NewInt(n.i64), big.NewInt(int64(num)))
+		n = currencyOfBig(x, n, " * ", num)
 	}
 	return n
 } //                                                                      MulInt
 
+// MulRound multiplies a currency object by one or more currency values
+// and returns the result rounded to 4 decimal places using the
+// specified rounding mode. The object's value isn't changed.
+func (n Currency) MulRound(mode RoundingMode, nums ...Currency) Currency {
+	var err error
+	for _, num := range nums {
+		n, err = currencyMul(n, num, mode)
+		if err != nil {
+			currencyLogError(err)
+			break
+		}
+	}
+	return n
+} //                                                                    MulRound
+
 // -----------------------------------------------------------------------------
 // # Addition:
 
 // Add adds one or more currency values and returns a new Currency object.
 // The value in the object to which this method is applied isn't changed.
-// If there is an overflow, sets the Currency's internal value to
-// math.MinInt64 or math.MaxInt64 depending on if the result is negative.
+// If there is an overflow, logs an error and sets the Currency's internal
+// value to math.MinInt64 or math.MaxInt64 depending on if the result is
+// negative. See AddE() for a version that returns an error instead.
 func (n Currency) Add(nums ...Currency) Currency {
+	ret, err := n.AddE(nums...)
+	currencyLogError(err)
+	return ret
+} //                                                                         Add
+
+// AddE adds one or more currency values and returns the result,
+// or an overflow value and an error if the result doesn't fit in
+// Currency or if any operand is an overflow value.
+// Does not log errors. The object's value isn't changed.
+func (n Currency) AddE(nums ...Currency) (Currency, error) {
+	var err error
 	for _, num := range nums {
-		var (
-			a = n.i64
-			b = num.i64
-			c = a + b
-		)
-		// check for overflow
-		if c < MinCurrencyI64 || (a < 0 && b < 0 && b < (MinCurrencyI64-a)) {
-			return currencyOverflow(true, a, " + ", b)
-		}
-		if c > MaxCurrencyI64 || (a > 0 && b > 0 && b > (MaxCurrencyI64-a)) {
-			return currencyOverflow(false, a, " + ", b)
+		n, err = currencyAdd(n, num)
+		if err != nil {
+			break
 		}
-		n.i64 = c
 	}
-	return n
-} //                                                                         Add
+	return n, err
+} //                                                                        AddE
 
 // AddFloat adds one or more floating-point numbers to a currency
 // object and returns the result. The object's value isn't changed.
 func (n Currency) AddFloat(nums ...float64) Currency {
-	const lim float64 = math.MaxInt64 / 1e4
-	a := n.i64
-	for _, b := range nums {
-		//
-		// check for overflow
-		if b < -lim || b > lim {
-			return currencyOverflow(
-				(a < 0 || b < 0) && (a > 0 || b > 0),
-				a, " * ", b, " = ", float64(a)*b)
-		}
-		// use Add() because it has other overflow checks
-		n = n.Add(Currency{int64(b * 1e4)})
-	}
-	return n
+	return currencyFloatOp(n, nums, currencyAdd)
 } //                                                                    AddFloat
 
 // AddInt adds one or more integer values to a currency object
 // and returns the result. The object's value isn't changed.
 func (n Currency) AddInt(nums ...int) Currency {
-	for _, num := range nums {
-		n = n.Add(Currency{int64(num) * 1e4})
-	}
-	return n
+	return currencyIntOp(n, nums, currencyAdd)
 } //                                                                      AddInt
 
 // -----------------------------------------------------------------------------
@@ -650,41 +907,40 @@ func (n Currency) AddInt(nums ...int) Currency {
 
 // Sub subtracts one or more currency values from a currency object
 // and returns the result. The object's value isn't changed.
+// If there is an overflow, logs an error and returns math.MinInt64
+// or math.MaxInt64 depending on if the result is negative.
+// See SubE() for a version that returns an error instead of logging it.
 func (n Currency) Sub(nums ...Currency) Currency {
+	ret, err := n.SubE(nums...)
+	currencyLogError(err)
+	return ret
+} //                                                                         Sub
+
+// SubE subtracts one or more currency values from a currency object
+// and returns the result, or an overflow value and an error if the
+// result doesn't fit in Currency or if any operand is an overflow
+// value. Does not log errors. The object's value isn't changed.
+func (n Currency) SubE(nums ...Currency) (Currency, error) {
+	var err error
 	for _, num := range nums {
-		var (
-			a = n.i64
-			b = num.i64
-			c = a - b
-		)
-		// check for overflow
-		if c < MinCurrencyI64 || (a < 0 && b > 0 && b > (-MinCurrencyI64+a)) {
-			return currencyOverflow(true, a, " - ", b)
-		}
-		if c > MaxCurrencyI64 || (a > 0 && b < 0 && b < (-MaxCurrencyI64+a)) {
-			return currencyOverflow(false, a, " - ", b)
+		n, err = currencySub(n, num)
+		if err != nil {
+			break
 		}
-		n.i64 = c
 	}
-	return n
-} //                                                                         Sub
+	return n, err
+} //                                                                        SubE
 
 // SubFloat subtracts one or more floating-point numbers from a currency
 // object and returns the result. The object's value isn't changed.
 func (n Currency) SubFloat(nums ...float64) Currency {
-	for _, num := range nums {
-		n.i64 -= int64(num * 1e4)
-	}
-	return n
+	return currencyFloatOp(n, nums, currencySub)
 } //                                                                    SubFloat
 
 // SubInt subtracts one or more integer values from a currency object
 // and returns the result. The object's value isn't changed.
 func (n Currency) SubInt(nums ...int) Currency {
-	for _, num := range nums {
-		n.i64 -= int64(num) * 1e4
-	}
-	return n
+	return currencyIntOp(n, nums, currencySub)
 } //                                                                      SubInt
 
 // -----------------------------------------------------------------------------
@@ -735,6 +991,13 @@ func (n Currency) IsNegative() bool {
 	return n.i64 < 0
 } //                                                                  IsNegative
 
+// IsOverflow returns true if the currency holds a negative or positive
+// overflow value. Overflow values are sticky: the result of any
+// arithmetic on an overflow value is also an overflow value.
+func (n Currency) IsOverflow() bool {
+	return n.i64 < MinCurrencyI64 || n.i64 > MaxCurrencyI64
+} //                                                                  IsOverflow
+
 // IsZero returns true if the value of the currency object is zero.
 func (n Currency) IsZero() bool {
 	return n.i64 == 0
@@ -786,19 +1049,110 @@ func (n Currency) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON unmarshals a JSON description of zr.Currency.
 // This method alters the object's value.
+//
+// Accepts JSON numbers and strings containing numbers, e.g. 12.5 or "12.5".
+// The decimal text is parsed exactly, without converting it to float64.
+// Decimals beyond the 4th decimal place are rounded as specified by
+// SetCurrencyRounding(). A JSON null leaves the value unchanged.
+//
+// Returns an error if the number is too big to be stored in Currency.
 func (n *Currency) UnmarshalJSON(data []byte) error {
 	//   ^  don't remove pointer receiver, it is necessary
-	var num float64
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	var num json.Number
 	err := mod.json.Unmarshal(data, &num)
 	if err != nil {
 		return err
 	}
-	n.i64 = int64(num * 1e4)
+	if num == "" { // null
+		return nil
+	}
+	ret, err := currencyParse(string(num), CurrencyRounding())
+	if err != nil {
+		return err
+	}
+	n.i64 = ret.i64
 	return nil
 } //                                                               UnmarshalJSON
 
 // -----------------------------------------------------------------------------
-// # Helper Function
+// # Text, Binary and XML Encoding:
+
+// MarshalBinary encodes the currency's internal value as a compact
+// variable-length integer (1 to 10 bytes) and implements the
+// encoding.BinaryMarshaler interface. This is also used by encoding/gob.
+func (n Currency) MarshalBinary() ([]byte, error) {
+	var buf [binary.MaxVarintLen64]byte
+	size := binary.PutVarint(buf[:], n.i64)
+	return buf[:size], nil
+} //                                                               MarshalBinary
+
+// MarshalText returns the currency value as text, the same as
+// String(), and implements the encoding.TextMarshaler interface.
+// This allows Currency to be used as a JSON map key, etc.
+func (n Currency) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+} //                                                                 MarshalText
+
+// MarshalXML writes the currency value as the text of an
+// XML element, the same as String(), and implements
+// the xml.Marshaler interface.
+func (n Currency) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.EncodeElement(n.String(), start)
+} //                                                                  MarshalXML
+
+// UnmarshalBinary decodes a value encoded by MarshalBinary() and
+// implements the encoding.BinaryUnmarshaler interface.
+// This method alters the object's value.
+func (n *Currency) UnmarshalBinary(data []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	i64, size := binary.Varint(data)
+	if size <= 0 || size != len(data) {
+		return fmt.Errorf("%s binary Currency: %v", EInvalid, data)
+	}
+	n.i64 = i64
+	return nil
+} //                                                             UnmarshalBinary
+
+// UnmarshalText parses a numeric string into the currency value and
+// implements the encoding.TextUnmarshaler interface. Decimals beyond
+// the 4th decimal place are rounded as specified by SetCurrencyRounding().
+// This method alters the object's value.
+func (n *Currency) UnmarshalText(text []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	ret, err := currencyParse(string(text), CurrencyRounding())
+	if err != nil {
+		return err
+	}
+	n.i64 = ret.i64
+	return nil
+} //                                                               UnmarshalText
+
+// UnmarshalXML reads the currency value from the text of an
+// XML element and implements the xml.Unmarshaler interface.
+// This method alters the object's value.
+func (n *Currency) UnmarshalXML(
+	dec *xml.Decoder, start xml.StartElement,
+) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	var s string
+	err := dec.DecodeElement(&s, &start)
+	if err != nil {
+		return err
+	}
+	return n.UnmarshalText([]byte(s))
+} //                                                                UnmarshalXML
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
 
 // currencyOverflow returns a negative (math.MinInt64)
 // or positive (math.MaxInt64) overflow value.
@@ -818,4 +1172,330 @@ func currencyOverflow(isNegative bool, a ...interface{}) Currency {
 	return Currency{math.MaxInt64}
 } //                                                            currencyOverflow
 
+// currencyFloatRat converts a float to a rational number using the
+// float's shortest decimal representation, e.g. 0.1 becomes 1/10.
+// Returns nil if num is NaN or infinite.
+func currencyFloatRat(num float64) *big.Rat {
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return nil
+	}
+	ret, _ := new(big.Rat).SetString(strconv.FormatFloat(num, 'g', -1, 64))
+	return ret
+} //                                                            currencyFloatRat
+
+// currencyOfBig returns a Currency holding the internal value x,
+// or an overflow value (see currencyOverflow) if x doesn't fit in
+// Currency. The values in 'a' are used to build the error message.
+func currencyOfBig(x *big.Int, a ...interface{}) Currency {
+	if !x.IsInt64() || x.Int64() < MinCurrencyI64 ||
+		x.Int64() > MaxCurrencyI64 {
+		return currencyOverflow(x.Sign() < 0, a...)
+	}
+	return Currency{x.Int64()}
+} //                                                               currencyOfBig
+
+// currencyAdd returns a + b, or an overflow value and an error if the
+// sum doesn't fit in Currency or if a or b is an overflow value.
+// Does not log errors.
+func currencyAdd(a, b Currency) (Currency, error) {
+	if a.IsOverflow() {
+		return a, errCurrencyOverflowOperand
+	}
+	if b.IsOverflow() {
+		return b, errCurrencyOverflowOperand
+	}
+	// a and b are within Currency's range, which is narrower than
+	// int64's, but their sum can still exceed int64, so check first
+	if b.i64 > 0 && a.i64 > MaxCurrencyI64-b.i64 {
+		return currencyOverflowE(false, a, " + ", b)
+	}
+	if b.i64 < 0 && a.i64 < MinCurrencyI64-b.i64 {
+		return currencyOverflowE(true, a, " + ", b)
+	}
+	return Currency{a.i64 + b.i64}, nil
+} //                                                                 currencyAdd
+
+// currencyDiv returns a / b rounded to 4 decimal places using
+// the specified rounding mode, or an overflow value and an error
+// if b is zero, if the result doesn't fit in Currency, or if
+// a or b is an overflow value. Does not log errors.
+func currencyDiv(a, b Currency, mode RoundingMode) (Currency, error) {
+	if a.IsOverflow() || b.IsOverflow() {
+		return currencyOverflowOf(a, b)
+	}
+	if b.i64 == 0 {
+		ret, _ := currencyOverflowE(a.i64 < 0)
+		return ret, fmt.Errorf("%s: %v / 0", EDivByZero, a)
+	}
+	// use int64 unless scaling the dividend will overflow
+	if a.i64 > -math.MaxInt64/10000 && a.i64 < math.MaxInt64/10000 {
+		return currencyOfBigE(
+			big.NewInt(roundQuoInt64(a.i64*1e4, b.i64, mode)), a, " / ", b)
+	}
+	x := new(big.Int).Mul(big.NewInt(a.i64), big1E4)
+	return currencyOfBigE(roundQuo(x, big.NewInt(b.i64), mode), a, " / ", b)
+} //                                                                 currencyDiv
+
+// currencyFloatOp applies op to n and each of the numbers, converted
+// to Currency by truncating them to 4 decimal places, and returns the
+// result. Logs an error and returns an overflow value if a number
+// can't be converted or op fails.
+func currencyFloatOp(
+	n Currency,
+	nums []float64,
+	op func(a, b Currency) (Currency, error),
+) Currency {
+	for _, num := range nums {
+		b, err := currencyOfFloat(num)
+		ret, opErr := op(n, b)
+		if err == nil {
+			err = opErr
+		}
+		n = ret
+		if err != nil {
+			currencyLogError(err)
+			break
+		}
+	}
+	return n
+} //                                                             currencyFloatOp
+
+// currencyIntOp applies op to n and each of the numbers,
+// converted to Currency, and returns the result. Logs an
+// error and returns an overflow value if a number can't
+// be converted or op fails.
+func currencyIntOp(
+	n Currency,
+	nums []int,
+	op func(a, b Currency) (Currency, error),
+) Currency {
+	for _, num := range nums {
+		b, err := currencyOfInt(int64(num))
+		ret, opErr := op(n, b)
+		if err == nil {
+			err = opErr
+		}
+		n = ret
+		if err != nil {
+			currencyLogError(err)
+			break
+		}
+	}
+	return n
+} //                                                               currencyIntOp
+
+// currencyLogError logs err, unless it is nil.
+func currencyLogError(err error) {
+	if err != nil {
+		mod.Error(err)
+	}
+} //                                                            currencyLogError
+
+// currencyMul returns a * b rounded to 4 decimal places using the
+// specified rounding mode, or an overflow value and an error if
+// the result doesn't fit in Currency or if a or b is an overflow
+// value. Does not log errors.
+func currencyMul(a, b Currency, mode RoundingMode) (Currency, error) {
+	if a.IsOverflow() || b.IsOverflow() {
+		return currencyOverflowOf(a, b)
+	}
+	// multiply using int64 if the product can't overflow
+	const lim = 3037000499 // square root of math.MaxInt64
+	if a.i64 > -lim && a.i64 < lim && b.i64 > -lim && b.i64 < lim {
+		return Currency{roundQuoInt64(a.i64*b.i64, 1e4, mode)}, nil
+	}
+	x := new(big.Int).Mul(big.NewInt(a.i64), big.NewInt(b.i64))
+	return currencyOfBigE(roundQuo(x, big1E4, mode), a, " * ", b)
+} //                                                                 currencyMul
+
+// currencyOfBigE returns a Currency holding the internal value x, or
+// an overflow value and an error if x doesn't fit in Currency. The
+// values in 'a' are used to build the error message.
+func currencyOfBigE(x *big.Int, a ...interface{}) (Currency, error) {
+	if !x.IsInt64() || x.Int64() < MinCurrencyI64 ||
+		x.Int64() > MaxCurrencyI64 {
+		return currencyOverflowE(x.Sign() < 0, a...)
+	}
+	return Currency{x.Int64()}, nil
+} //                                                              currencyOfBigE
+
+// currencyOfFloat converts a float to Currency, truncating it to 4
+// decimal places. Returns an overflow value and an error if the
+// number is out of range, infinite or NaN. Does not log errors.
+func currencyOfFloat(num float64) (Currency, error) {
+	const lim = float64(MaxCurrencyI64) / 1e4
+	if math.IsNaN(num) || num < -lim || num > lim {
+		return currencyOverflowE(num < 0, num)
+	}
+	return Currency{int64(num * 1e4)}, nil
+} //                                                             currencyOfFloat
+
+// currencyOfInt converts an integer to Currency. Returns an overflow
+// value and an error if the number is out of range. Does not log errors.
+func currencyOfInt(num int64) (Currency, error) {
+	if num < -CurrencyIntLimit || num > CurrencyIntLimit {
+		return currencyOverflowE(num < 0, num)
+	}
+	return Currency{num * 1e4}, nil
+} //                                                               currencyOfInt
+
+// currencyOverflowE returns an overflow value (see currencyOverflow)
+// and an error. The values in 'a' are used to build the error message.
+// Does not log errors.
+func currencyOverflowE(isNegative bool, a ...interface{}) (Currency, error) {
+	ret := Currency{math.MaxInt64}
+	if isNegative {
+		ret = Currency{math.MinInt64}
+	}
+	if len(a) == 0 {
+		return ret, errors.New(EOverflow)
+	}
+	return ret, errors.New(EOverflow + ": " + fmt.Sprint(a...))
+} //                                                           currencyOverflowE
+
+// currencyOverflowOf returns the overflow value that results from
+// multiplying or dividing a and b, when either holds an overflow
+// value, and an error.
+func currencyOverflowOf(a, b Currency) (Currency, error) {
+	ret, _ := currencyOverflowE((a.i64 < 0) != (b.i64 < 0))
+	return ret, errCurrencyOverflowOperand
+} //                                                          currencyOverflowOf
+
+// currencySub returns a - b, or an overflow value and an error if the
+// difference doesn't fit in Currency or if a or b is an overflow value.
+// Does not log errors.
+func currencySub(a, b Currency) (Currency, error) {
+	if a.IsOverflow() {
+		return a, errCurrencyOverflowOperand
+	}
+	if b.IsOverflow() {
+		ret, _ := currencyOverflowE(b.i64 > 0)
+		return ret, errCurrencyOverflowOperand
+	}
+	return currencyAdd(a, Currency{-b.i64})
+} //                                                                 currencySub
+
+// currencyParse converts a decimal string to Currency without using
+// floating-point arithmetic, so the conversion is always exact.
+//
+// The string may contain a leading sign, commas and white-spaces
+// (which are ignored), a decimal point and an exponent (e.g. "1.5e3").
+// Decimals beyond the 4th decimal place are rounded using mode.
+//
+// A blank string is converted to zero. If the string is not
+// numeric, returns zero and an error. If the number is too big
+// for Currency, returns a negative or positive overflow value
+// and an error starting with EOverflow. Does not log errors.
+func currencyParse(s string, mode RoundingMode) (Currency, error) {
+	// shifting by more than 20 places will overflow in any case
+	num, err := currencyParseBig(s, mode, 20)
+	if err != nil {
+		return Currency{0}, err
+	}
+	if !num.IsInt64() || num.Int64() < MinCurrencyI64 ||
+		num.Int64() > MaxCurrencyI64 {
+		if num.Sign() < 0 {
+			return Currency{math.MinInt64}, errors.New(EOverflow + ": " + s)
+		}
+		return Currency{math.MaxInt64}, errors.New(EOverflow + ": " + s)
+	}
+	return Currency{num.Int64()}, nil
+} //                                                               currencyParse
+
+// currencyParseBig converts a decimal string to an integer scaled by 10^4
+// (the internal value of Currency and BigCurrency), as described in
+// currencyParse(). maxShift limits how many places the number can be
+// shifted left by its exponent. Does not log errors.
+func currencyParseBig(
+	s string, mode RoundingMode, maxShift int,
+) (*big.Int, error) {
+	var (
+		digits   = make([]byte, 0, len(s))
+		minus    bool
+		hasSign  bool
+		hasPoint bool
+		hasDigit bool
+		decimals int // number of digits after the decimal point
+		exp      int // value of the exponent, if any
+		i        int
+	)
+	fail := func(pos int) (*big.Int, error) {
+		return nil, fmt.Errorf("%s %q: invalid character %q at %d",
+			EFailedParsing, s, s[pos], pos)
+	}
+	for ; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= '0' && ch <= '9':
+			hasDigit = true
+			if hasPoint {
+				decimals++
+			}
+			if ch != '0' || len(digits) > 0 {
+				digits = append(digits, ch)
+			} else if hasPoint {
+				digits = append(digits, ch)
+			}
+		case ch == '-' || ch == '+':
+			if hasSign || hasDigit || hasPoint {
+				return fail(i)
+			}
+			hasSign, minus = true, ch == '-'
+		case ch == '.':
+			if hasPoint {
+				return fail(i)
+			}
+			hasPoint = true
+		case ch == ',' && !hasPoint:
+			// ignore digit group separators
+		case ch == 'e' || ch == 'E':
+			if !hasDigit || i == len(s)-1 {
+				return fail(i)
+			}
+			n, err := strconv.Atoi(s[i+1:])
+			if err != nil || n < -1e6 || n > 1e6 {
+				return fail(i)
+			}
+			exp = n
+			i = len(s)
+		case strings.IndexByte(SPACES, ch) != -1:
+			// ignore white-spaces
+		default:
+			return fail(i)
+		}
+	}
+	if len(s) > 0 && !hasDigit && strings.Trim(s, SPACES) != "" {
+		return fail(0)
+	}
+	// the number's value is: digits * 10^shift / 10^4
+	var (
+		shift = exp - decimals + 4
+		num   = new(big.Int)
+	)
+	if len(digits) > 0 {
+		num.SetString(string(digits), 10)
+	}
+	if minus {
+		num.Neg(num)
+	}
+	if num.Sign() != 0 {
+		if shift > 0 {
+			if shift > maxShift {
+				shift = maxShift
+			}
+			pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil)
+			num.Mul(num, pow)
+		} else if shift < 0 {
+			// dividing by more than 10^(digits+1) gives the
+			// same rounding result as any larger divisor
+			if -shift > len(digits)+1 {
+				shift = -(len(digits) + 1)
+			}
+			pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil)
+			num = roundQuo(num, pow, mode)
+		}
+	}
+	return num, nil
+} //                                                            currencyParseBig
+
 // end
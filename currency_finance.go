@@ -0,0 +1,345 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                           zr/[currency_finance.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Percentages:
+//   (n Currency) AddPercent(percent float64) Currency
+//   (n Currency) Percent(percent float64) Currency
+//   (n Currency) PercentRound(
+//       decimalPlaces int, mode RoundingMode, percent float64,
+//   ) Currency
+//   (n Currency) SubPercent(percent float64) Currency
+//
+// # Tax:
+//   (n Currency) SplitTax(
+//       decimalPlaces int, mode RoundingMode, ratePercent float64,
+//   ) (net, tax Currency)
+//
+// # Interest:
+//   AmortizationRow struct
+//   (n Currency) Amortize(
+//       decimalPlaces int, mode RoundingMode, ratePercent float64, periods int,
+//   ) []AmortizationRow
+//   (n Currency) CompoundInterest(
+//       mode RoundingMode, ratePercent float64, periods int,
+//   ) Currency
+
+// # Helper Functions
+//   currencyMulRat(
+//       n Currency, r *big.Rat, decimalPlaces int, mode RoundingMode,
+//       a ...interface{},
+//   ) Currency
+//   currencyPercentRat(percent float64, base int64) *big.Rat
+
+import (
+	"math/big"
+)
+
+// -----------------------------------------------------------------------------
+// # Constants:
+
+// CurrencyMaxPeriods is the highest number of periods accepted by
+// Amortize() and CompoundInterest(). It limits the size of the
+// schedule and of the exact powers used to calculate interest.
+const CurrencyMaxPeriods = 10000
+
+// -----------------------------------------------------------------------------
+// # Percentages:
+
+// AddPercent increases the currency value by the given percentage
+// and returns the result. The percentage is calculated using Percent(),
+// so the result is n.Add(n.Percent(percent)). Use it to add a markup
+// or a tax. The object's value isn't changed.
+//
+// Example: CurrencyOf(80).AddPercent(17.5) returns 94
+func (n Currency) AddPercent(percent float64) Currency {
+	p := n.Percent(percent)
+	if p.IsOverflow() {
+		return p
+	}
+	return n.Add(p)
+} //                                                                  AddPercent
+
+// Percent returns the given percentage of the currency value, rounded
+// to 4 decimal places, rounding half away from zero.
+// The object's value isn't changed.
+//
+// The percentage is used with its shortest decimal representation,
+// so 7.7 percent is exactly 77/1000 of the value.
+//
+// If the percentage is NaN or infinite, or the result doesn't fit
+// in Currency, logs an error and returns an overflow value.
+//
+// Example: CurrencyOf("19.99").Percent(15) returns 2.9985
+func (n Currency) Percent(percent float64) Currency {
+	return n.PercentRound(4, RoundHalfUp, percent)
+} //                                                                     Percent
+
+// PercentRound returns the given percentage of the currency value,
+// rounded to the specified number of decimal places using the given
+// rounding mode. Unlike n.Percent(percent).Round(decimalPlaces, mode)
+// the result is only rounded once. The object's value isn't changed.
+//
+// decimalPlaces can range from 4 to -14, like in Round().
+//
+// Example: CurrencyOf("19.99").PercentRound(2, RoundHalfUp, 15) returns 3
+func (n Currency) PercentRound(
+	decimalPlaces int,
+	mode RoundingMode,
+	percent float64,
+) Currency {
+	return currencyMulRat(n, currencyPercentRat(percent, 0), decimalPlaces,
+		mode, n, " * ", percent, "%")
+} //                                                                PercentRound
+
+// SubPercent decreases the currency value by the given percentage
+// and returns the result. The percentage is calculated using Percent(),
+// so the result is n.Sub(n.Percent(percent)). Use it to apply a
+// discount. The object's value isn't changed.
+//
+// Example: CurrencyOf(80).SubPercent(12.5) returns 70
+func (n Currency) SubPercent(percent float64) Currency {
+	p := n.Percent(percent)
+	if p.IsOverflow() {
+		return p
+	}
+	return n.Sub(p)
+} //                                                                  SubPercent
+
+// -----------------------------------------------------------------------------
+// # Tax:
+
+// SplitTax splits a tax-inclusive (gross) currency value into the net
+// amount and the tax charged at the given rate. The net amount is
+// rounded to the specified number of decimal places using the given
+// rounding mode, and the tax is the difference, so that net and tax
+// always add up exactly to the original value.
+// The object's value isn't changed.
+//
+// If the rate is negative, NaN or infinite, or decimalPlaces is less
+// than -14, logs an error and returns the original value and zero.
+//
+// Example: CurrencyOf(120).SplitTax(2, RoundHalfUp, 20) returns 100, 20
+func (n Currency) SplitTax(
+	decimalPlaces int,
+	mode RoundingMode,
+	ratePercent float64,
+) (net, tax Currency) {
+	r := currencyPercentRat(ratePercent, 100)
+	if r == nil || r.Cmp(big.NewRat(1, 1)) < 0 {
+		mod.Error(EInvalidArg, "^ratePercent", ":", ratePercent)
+		return n, Currency{0}
+	}
+	if decimalPlaces < -14 {
+		mod.Error(EInvalidArg, "^decimalPlaces", ":", decimalPlaces)
+		return n, Currency{0}
+	}
+	net = currencyMulRat(n, r.Inv(r), decimalPlaces, mode,
+		n, " / ", ratePercent, "%")
+	if net.IsOverflow() {
+		return net, net
+	}
+	return net, Currency{n.i64 - net.i64}
+} //                                                                    SplitTax
+
+// -----------------------------------------------------------------------------
+// # Interest:
+
+// AmortizationRow holds the amounts of one period of a loan repayment
+// schedule returned by Currency.Amortize().
+type AmortizationRow struct {
+	Period    int      // number of the period, starting from 1
+	Payment   Currency // amount paid in the period
+	Principal Currency // part of the payment that repays the loan
+	Interest  Currency // part of the payment that pays the interest
+	Balance   Currency // amount still owed after the payment
+} //                                                             AmortizationRow
+
+// Amortize returns the repayment schedule of a loan of the currency
+// value, repaid in the given number of equal periodic payments that
+// include interest at ratePercent per period (e.g. 0.5 for 6% a year
+// repaid monthly). All amounts are rounded to the specified number of
+// decimal places using the given rounding mode.
+//
+// The schedule reconciles exactly: the principal amounts add up to the
+// loan, each payment is its principal plus interest, and the balance
+// after the last payment is zero. To achieve this, the last payment
+// repays whatever balance remains, so it may differ slightly from the
+// other payments. A payment is also reduced if it would repay more
+// than the balance.
+//
+// The interest of each period is the balance at the start of the
+// period multiplied by the rate. The object's value isn't changed.
+//
+// If the currency value is negative or an overflow value, the rate is
+// negative, NaN or infinite, periods is less than 1 or more than
+// CurrencyMaxPeriods, or decimalPlaces is less than -14, logs an
+// error and returns nil.
+func (n Currency) Amortize(
+	decimalPlaces int,
+	mode RoundingMode,
+	ratePercent float64,
+	periods int,
+) []AmortizationRow {
+	if n.IsOverflow() {
+		currencyLogError(errCurrencyOverflowOperand)
+		return nil
+	}
+	if n.i64 < 0 {
+		mod.Error(EInvalidArg, "negative loan", ":", n)
+		return nil
+	}
+	r := currencyPercentRat(ratePercent, 0)
+	if r == nil || r.Sign() < 0 {
+		mod.Error(EInvalidArg, "^ratePercent", ":", ratePercent)
+		return nil
+	}
+	if periods < 1 || periods > CurrencyMaxPeriods {
+		mod.Error(EInvalidArg, "^periods", ":", periods)
+		return nil
+	}
+	if decimalPlaces < -14 {
+		mod.Error(EInvalidArg, "^decimalPlaces", ":", decimalPlaces)
+		return nil
+	}
+	// payment = loan * r * (1 + r)^periods / ((1 + r)^periods - 1)
+	factor := big.NewRat(1, int64(periods))
+	if r.Sign() > 0 {
+		var (
+			g   = new(big.Rat).Add(r, big.NewRat(1, 1))
+			exp = big.NewInt(int64(periods))
+			num = new(big.Int).Exp(g.Num(), exp, nil)
+			den = new(big.Int).Exp(g.Denom(), exp, nil)
+		)
+		g.SetFrac(num, den) // (1 + r)^periods
+		factor.Sub(g, big.NewRat(1, 1))
+		factor.Quo(g, factor)
+		factor.Mul(factor, r)
+	}
+	payment := currencyMulRat(n, factor, decimalPlaces, mode, n, " / ", periods)
+	if payment.IsOverflow() {
+		return nil
+	}
+	var (
+		ret     = make([]AmortizationRow, periods)
+		balance = n
+	)
+	for i := range ret {
+		interest := currencyMulRat(balance, r, decimalPlaces, mode,
+			balance, " * ", ratePercent, "%")
+		if interest.IsOverflow() {
+			return nil
+		}
+		principal := Currency{payment.i64 - interest.i64}
+		if i == periods-1 || principal.i64 > balance.i64 {
+			principal = balance
+		}
+		if principal.i64 < 0 {
+			principal = Currency{0}
+		}
+		balance.i64 -= principal.i64
+		ret[i] = AmortizationRow{
+			Period:    i + 1,
+			Payment:   Currency{principal.i64 + interest.i64},
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		}
+	}
+	return ret
+} //                                                                    Amortize
+
+// CompoundInterest returns the interest earned by the currency value
+// over the given number of periods, when interest at ratePercent per
+// period is added to the value at the end of each period. The result
+// is calculated exactly and then rounded to 4 decimal places using
+// the given rounding mode. Add it to the value to get the final
+// amount. The object's value isn't changed.
+//
+// If the rate is NaN, infinite or -100 or less, or periods is negative
+// or more than CurrencyMaxPeriods, logs an error and returns zero.
+// If the result doesn't fit in Currency, logs an error and returns
+// an overflow value.
+//
+// Example: CurrencyOf(1000).CompoundInterest(RoundHalfUp, 10, 2) returns 210
+func (n Currency) CompoundInterest(
+	mode RoundingMode,
+	ratePercent float64,
+	periods int,
+) Currency {
+	g := currencyPercentRat(ratePercent, 100)
+	if g == nil || g.Sign() <= 0 {
+		mod.Error(EInvalidArg, "^ratePercent", ":", ratePercent)
+		return Currency{0}
+	}
+	if periods < 0 || periods > CurrencyMaxPeriods {
+		mod.Error(EInvalidArg, "^periods", ":", periods)
+		return Currency{0}
+	}
+	// interest = value * ((1 + r)^periods - 1)
+	exp := big.NewInt(int64(periods))
+	g.SetFrac(
+		new(big.Int).Exp(g.Num(), exp, nil),
+		new(big.Int).Exp(g.Denom(), exp, nil),
+	)
+	g.Sub(g, big.NewRat(1, 1))
+	return currencyMulRat(n, g, 4, mode,
+		n, " at ", ratePercent, "% for ", periods, " periods")
+} //                                                            CompoundInterest
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// currencyMulRat returns n multiplied by r, rounded to the specified
+// number of decimal places using the given rounding mode.
+//
+// If n is an overflow value, r is nil, decimalPlaces is less than -14,
+// or the result doesn't fit in Currency, logs an error and returns
+// an overflow value (or n when decimalPlaces is invalid).
+// The values in 'a' are used to build the error message.
+func currencyMulRat(
+	n Currency,
+	r *big.Rat,
+	decimalPlaces int,
+	mode RoundingMode,
+	a ...interface{},
+) Currency {
+	if n.IsOverflow() {
+		currencyLogError(errCurrencyOverflowOperand)
+		return n
+	}
+	if r == nil {
+		return currencyOverflow(n.i64 < 0, a...)
+	}
+	if decimalPlaces > 4 {
+		decimalPlaces = 4
+	}
+	if decimalPlaces < -14 {
+		mod.Error(EInvalidArg, "^decimalPlaces", ":", decimalPlaces)
+		return n
+	}
+	var (
+		unit = bigCurrencyPow10(4 - decimalPlaces)
+		num  = new(big.Int).Mul(big.NewInt(n.i64), r.Num())
+		den  = new(big.Int).Mul(r.Denom(), unit)
+	)
+	q := roundQuo(num, den, mode)
+	return currencyOfBig(q.Mul(q, unit), a...)
+} //                                                              currencyMulRat
+
+// currencyPercentRat returns (base + percent) / 100 as a rational
+// number, using the shortest decimal representation of percent.
+// Returns nil if percent is NaN or infinite.
+func currencyPercentRat(percent float64, base int64) *big.Rat {
+	ret := currencyFloatRat(percent)
+	if ret == nil {
+		return nil
+	}
+	ret.Add(ret, big.NewRat(base, 1))
+	return ret.Quo(ret, big.NewRat(100, 1))
+} //                                                          currencyPercentRat
+
+// end
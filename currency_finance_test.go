@@ -0,0 +1,365 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                      zr/[currency_finance_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Percentages:
+//   Test_cfin_Currency_AddPercent_
+//   Test_cfin_Currency_Percent_
+//   Test_cfin_Currency_SubPercent_
+//
+// # Tax:
+//   Test_cfin_Currency_SplitTax_
+//
+// # Interest:
+//   Test_cfin_Currency_Amortize_
+//   Test_cfin_Currency_CompoundInterest_
+
+//  to test all items in currency_finance.go use:
+//      go test --run Test_cfin_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Percentages:
+
+// go test --run Test_cfin_Currency_AddPercent_
+func Test_cfin_Currency_AddPercent_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) AddPercent(percent float64) Currency
+	//
+	test := func(n Currency, percent float64, expect Currency) {
+		got := n.AddPercent(percent)
+		if got != expect {
+			TFailf(t, `%v.AddPercent(%v) returned %v instead of %v`,
+				n, percent, got, expect)
+		}
+		if got != n.Add(n.Percent(percent)) {
+			TFailf(t, `%v.AddPercent(%v) doesn't match Percent()`, n, percent)
+		}
+	}
+	test(cur(80), 17.5, cur(94))
+	test(cur(100), 0, cur(100))
+	test(cur(10.99), 20, cur(13.188))
+	test(cur("0.0005"), 50, cur("0.0008"))
+	test(cur("-0.0005"), 50, cur("-0.0008"))
+	test(cur(50), -100, cur(0))
+} //                                              Test_cfin_Currency_AddPercent_
+
+// go test --run Test_cfin_Currency_Percent_
+func Test_cfin_Currency_Percent_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Percent(percent float64) Currency
+	// (n Currency) PercentRound(
+	//     decimalPlaces int, mode RoundingMode, percent float64,
+	// ) Currency
+	//
+	test := func(n Currency, percent float64, expect Currency) {
+		got := n.Percent(percent)
+		if got != expect {
+			TFailf(t, `%v.Percent(%v) returned %v instead of %v`,
+				n, percent, got, expect)
+		}
+	}
+	test(cur(19.99), 15, cur(2.9985))
+	test(cur(200), 7.7, cur(15.4))
+	test(cur(3), 33.3333, cur(1))
+	test(cur("0.0001"), 50, cur("0.0001"))
+	test(cur("-0.0001"), 50, cur("-0.0001"))
+	test(cur(-40), 2.5, cur(-1))
+	//
+	TEqual(t, cur(19.99).PercentRound(2, RoundHalfUp, 15), cur(3))
+	TEqual(t, cur(19.99).PercentRound(2, RoundDown, 15), cur(2.99))
+	TEqual(t, cur(1234).PercentRound(-1, RoundHalfEven, 10), cur(120))
+	//
+	// a single rounding differs from rounding twice
+	TEqual(t, cur("1.4998").PercentRound(1, RoundHalfUp, 10), cur(0.1))
+	TEqual(t, cur("1.4998").Percent(10).Round(2, RoundHalfUp).
+		Round(1, RoundHalfUp), cur(0.2))
+	//
+	// errors are logged and return overflow values
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got1 := cur(1).Percent(math.NaN())
+		got2 := CurrencyOf(CurrencyIntLimit).Percent(200)
+		got3 := cur(-1).Percent(math.Inf(1))
+		got4 := got2.Percent(1)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TTrue(t, got1.IsOverflow())
+		TEqual(t, got2.Overflow(), 1)
+		TEqual(t, got3.Overflow(), -1)
+		TEqual(t, got4.Overflow(), 1)
+		TEqual(t, ec2-ec1, 4)
+	}
+} //                                                 Test_cfin_Currency_Percent_
+
+// go test --run Test_cfin_Currency_SubPercent_
+func Test_cfin_Currency_SubPercent_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) SubPercent(percent float64) Currency
+	//
+	test := func(n Currency, percent float64, expect Currency) {
+		got := n.SubPercent(percent)
+		if got != expect {
+			TFailf(t, `%v.SubPercent(%v) returned %v instead of %v`,
+				n, percent, got, expect)
+		}
+		if got != n.Sub(n.Percent(percent)) {
+			TFailf(t, `%v.SubPercent(%v) doesn't match Percent()`, n, percent)
+		}
+	}
+	test(cur(80), 12.5, cur(70))
+	test(cur(100), 100, cur(0))
+	test(cur(19.99), 15, cur(16.9915))
+	test(cur("0.0005"), 50, cur("0.0002"))
+	test(cur("-0.0005"), 50, cur("-0.0002"))
+} //                                              Test_cfin_Currency_SubPercent_
+
+// -----------------------------------------------------------------------------
+// # Tax:
+
+// go test --run Test_cfin_Currency_SplitTax_
+func Test_cfin_Currency_SplitTax_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) SplitTax(
+	//     decimalPlaces int, mode RoundingMode, ratePercent float64,
+	// ) (net, tax Currency)
+	//
+	test := func(
+		n Currency, places int, mode RoundingMode, rate float64,
+		expectNet, expectTax Currency,
+	) {
+		net, tax := n.SplitTax(places, mode, rate)
+		if net != expectNet || tax != expectTax {
+			TFailf(t, `%v.SplitTax(%d, %v, %v) returned %v, %v`+
+				` instead of %v, %v`, n, places, mode, rate,
+				net, tax, expectNet, expectTax)
+		}
+	}
+	test(cur(120), 2, RoundHalfUp, 20, cur(100), cur(20))
+	test(cur(10.99), 2, RoundHalfUp, 20, cur(9.16), cur(1.83))
+	test(cur(10.99), 2, RoundDown, 20, cur(9.15), cur(1.84))
+	test(cur(10.99), 4, RoundHalfUp, 20, cur(9.1583), cur(1.8317))
+	test(cur(107.7), 2, RoundHalfEven, 7.7, cur(100), cur(7.7))
+	test(cur(-10.99), 2, RoundHalfUp, 20, cur(-9.16), cur(-1.83))
+	test(cur(50), 0, RoundHalfUp, 0, cur(50), cur(0))
+	//
+	// net plus tax is always the gross value
+	for _, gross := range []Currency{cur(0.01), cur(1), cur(99.99),
+		cur(123456.78), cur(-0.05)} {
+		for _, rate := range []float64{5, 7.5, 8.25, 17.5, 19, 21} {
+			for _, mode := range []RoundingMode{RoundHalfEven, RoundHalfUp,
+				RoundDown, RoundUp} {
+				net, tax := gross.SplitTax(2, mode, rate)
+				TEqual(t, net.Add(tax), gross)
+				TEqual(t, net, net.Round(2, RoundDown))
+			}
+		}
+	}
+	// invalid arguments are logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		net1, tax1 := cur(120).SplitTax(2, RoundHalfUp, -20)
+		net2, tax2 := cur(120).SplitTax(2, RoundHalfUp, math.NaN())
+		net3, tax3 := cur(120).SplitTax(-15, RoundHalfUp, 20)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, ec2-ec1, 3)
+		for _, net := range []Currency{net1, net2, net3} {
+			TEqual(t, net, cur(120))
+		}
+		for _, tax := range []Currency{tax1, tax2, tax3} {
+			TEqual(t, tax, cur(0))
+		}
+	}
+} //                                                Test_cfin_Currency_SplitTax_
+
+// -----------------------------------------------------------------------------
+// # Interest:
+
+// go test --run Test_cfin_Currency_Amortize_
+func Test_cfin_Currency_Amortize_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Amortize(
+	//     decimalPlaces int, mode RoundingMode, ratePercent float64, periods int,
+	// ) []AmortizationRow
+	//
+	// checks that the schedule reconciles exactly
+	check := func(loan Currency, rows []AmortizationRow) {
+		var payments, principal, interest Currency
+		balance := loan
+		for i, row := range rows {
+			TEqual(t, row.Period, i+1)
+			TEqual(t, row.Payment, row.Principal.Add(row.Interest))
+			balance = balance.Sub(row.Principal)
+			TEqual(t, row.Balance, balance)
+			payments = payments.Add(row.Payment)
+			principal = principal.Add(row.Principal)
+			interest = interest.Add(row.Interest)
+		}
+		TEqual(t, balance, cur(0))
+		TEqual(t, principal, loan)
+		TEqual(t, payments, principal.Add(interest))
+	}
+	// 1,000 at 1% per period repaid in 12 periods
+	{
+		rows := cur(1000).Amortize(2, RoundHalfUp, 1, 12)
+		check(cur(1000), rows)
+		TEqual(t, len(rows), 12)
+		TEqual(t, rows[0], AmortizationRow{
+			Period:    1,
+			Payment:   cur(88.85),
+			Principal: cur(78.85),
+			Interest:  cur(10),
+			Balance:   cur(921.15),
+		})
+		TEqual(t, rows[1], AmortizationRow{
+			Period:    2,
+			Payment:   cur(88.85),
+			Principal: cur(79.64),
+			Interest:  cur(9.21),
+			Balance:   cur(841.51),
+		})
+		TEqual(t, rows[11], AmortizationRow{
+			Period:    12,
+			Payment:   cur(88.84),
+			Principal: cur(87.96),
+			Interest:  cur(0.88),
+			Balance:   cur(0),
+		})
+	}
+	// 30-year mortgage of 100,000 at 6% a year, repaid monthly
+	{
+		rows := cur(100000).Amortize(2, RoundHalfEven, 0.5, 360)
+		check(cur(100000), rows)
+		TEqual(t, rows[0].Payment, cur(599.55))
+		TEqual(t, rows[0].Interest, cur(500))
+		TEqual(t, rows[359].Payment, cur(600))
+		TEqual(t, rows[359].Interest, cur(2.99))
+	}
+	// no interest
+	{
+		rows := cur(100).Amortize(2, RoundHalfEven, 0, 3)
+		check(cur(100), rows)
+		TEqual(t, rows[0].Payment, cur(33.33))
+		TEqual(t, rows[2].Payment, cur(33.34))
+	}
+	// payments that would repay more than the balance are reduced
+	{
+		rows := cur(0.01).Amortize(2, RoundUp, 0, 5)
+		check(cur(0.01), rows)
+		TEqual(t, rows[0].Payment, cur(0.01))
+		TEqual(t, rows[1].Payment, cur(0))
+	}
+	// other loans and rounding modes
+	for _, loan := range []Currency{cur(0), cur(1), cur(999.99),
+		cur("12345.6789"), cur(2500000)} {
+		for _, rate := range []float64{0, 0.25, 1.5, 4.99, 25} {
+			for _, mode := range []RoundingMode{RoundHalfEven, RoundUp,
+				RoundDown} {
+				for _, periods := range []int{1, 7, 60} {
+					rows := loan.Amortize(2, mode, rate, periods)
+					TEqual(t, len(rows), periods)
+					check(loan, rows)
+				}
+			}
+		}
+	}
+	// the highest number of periods is accepted
+	rows := cur(100000).Amortize(2, RoundHalfUp, 0.01, CurrencyMaxPeriods)
+	TEqual(t, len(rows), CurrencyMaxPeriods)
+	check(cur(100000), rows)
+	// invalid arguments are logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		rows1 := cur(-1000).Amortize(2, RoundHalfUp, 1, 12)
+		rows2 := cur(1000).Amortize(2, RoundHalfUp, -1, 12)
+		rows3 := cur(1000).Amortize(2, RoundHalfUp, math.NaN(), 12)
+		rows4 := cur(1000).Amortize(2, RoundHalfUp, 1, 0)
+		rows5 := cur(1000).Amortize(-15, RoundHalfUp, 1, 12)
+		rows6 := CurrencyOf(CurrencyIntLimit).Mul(cur(2)).
+			Amortize(2, RoundHalfUp, 1, 12)
+		rows7 := cur(1000).Amortize(2, RoundHalfUp, 1, CurrencyMaxPeriods+1)
+		rows8 := cur(1000).Amortize(2, RoundHalfUp, 1, math.MaxInt32)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, ec2-ec1, 9)
+		for _, rows := range [][]AmortizationRow{
+			rows1, rows2, rows3, rows4, rows5, rows6, rows7, rows8,
+		} {
+			TTrue(t, rows == nil)
+		}
+	}
+} //                                                Test_cfin_Currency_Amortize_
+
+// go test --run Test_cfin_Currency_CompoundInterest_
+func Test_cfin_Currency_CompoundInterest_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) CompoundInterest(
+	//     mode RoundingMode, ratePercent float64, periods int,
+	// ) Currency
+	//
+	test := func(
+		n Currency, mode RoundingMode, rate float64, periods int,
+		expect Currency,
+	) {
+		got := n.CompoundInterest(mode, rate, periods)
+		if got != expect {
+			TFailf(t, `%v.CompoundInterest(%v, %v, %d) returned %v`+
+				` instead of %v`, n, mode, rate, periods, got, expect)
+		}
+	}
+	test(cur(1000), RoundHalfUp, 10, 2, cur(210))
+	test(cur(1000), RoundHalfUp, 10, 0, cur(0))
+	test(cur(1000), RoundHalfUp, 0, 12, cur(0))
+	test(cur(1000), RoundHalfEven, 0.5, 360, cur("5022.5752"))
+	test(cur(1000), RoundDown, 0.5, 360, cur("5022.5752"))
+	test(cur(100), RoundHalfUp, 1, 3, cur("3.0301"))
+	test(cur(100), RoundHalfUp, -10, 2, cur(-19))
+	test(cur(-100), RoundHalfUp, 5, 2, cur(-10.25))
+	test(cur(0.01), RoundHalfUp, 1, 1, cur("0.0001"))
+	test(cur(0.01), RoundDown, 0.5, 1, cur(0))
+	test(cur(0.01), RoundDown, 0.1, CurrencyMaxPeriods, cur("219.1568"))
+	//
+	// invalid arguments are logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got1 := cur(100).CompoundInterest(RoundHalfUp, -100, 2)
+		got2 := cur(100).CompoundInterest(RoundHalfUp, 5, -1)
+		got3 := CurrencyOf(CurrencyIntLimit).
+			CompoundInterest(RoundHalfUp, 100, 2)
+		got4 := cur(100).CompoundInterest(RoundHalfUp, 5,
+			CurrencyMaxPeriods+1)
+		got5 := cur(100).CompoundInterest(RoundHalfUp, 5, math.MaxInt32)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, ec2-ec1, 5)
+		TEqual(t, got1, cur(0))
+		TEqual(t, got2, cur(0))
+		TEqual(t, got3.Overflow(), 1)
+		TEqual(t, got4, cur(0))
+		TEqual(t, got5, cur(0))
+	}
+} //                                        Test_cfin_Currency_CompoundInterest_
+
+// end
@@ -0,0 +1,353 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                             zr/[currency_parse.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Error Type
+//   CurrencyParseError struct
+//   (ob *CurrencyParseError) Error() string
+//
+// # Parsing Functions
+//   ParseCurrency(s string) (Currency, error)
+//   ParseCurrencyLocale(s string, loc NumberLocale) (Currency, error)
+//
+// # Internal Functions
+//   parseCurrency(s string, loc *NumberLocale) (Currency, error)
+//   parseCurrencyDigits(
+//       s string, num []currencyParseChar, loc *NumberLocale,
+//   ) (string, error)
+//   parseCurrencyIsGroup(ch rune, loc *NumberLocale) bool
+//   parseCurrencySeparator(num []currencyParseChar, loc *NumberLocale) rune
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------------------------------------------
+// # Error Type
+
+// CurrencyParseError describes why a string could not be
+// parsed by ParseCurrency() or ParseCurrencyLocale().
+type CurrencyParseError struct {
+	Input  string // the string that was being parsed
+	Pos    int    // byte offset of the offending character in Input
+	Reason string // description of the problem
+} //                                                          CurrencyParseError
+
+// Error returns a description of the parsing
+// error and implements the error interface.
+func (ob *CurrencyParseError) Error() string {
+	if ob == nil {
+		return ENilReceiver
+	}
+	return fmt.Sprintf("%s %q at %d: %s",
+		EFailedParsing, ob.Input, ob.Pos, ob.Reason)
+} //                                                                       Error
+
+// -----------------------------------------------------------------------------
+// # Parsing Functions
+
+// ParseCurrency converts a formatted amount, such as found in bank
+// statements and invoices, to Currency. Does not log errors.
+//
+// Understands currency symbols and codes placed before or after the
+// number ("$1,234.50", "1.234,50 €", "USD 12.00"), digit group
+// separators (commas, periods, spaces and apostrophes), leading
+// and trailing minus signs ("-12", "12-"), and accounting
+// parentheses for negative amounts ("(1,234.50)"). The currency
+// symbol or code can be inside or outside the parentheses, e.g.
+// "($12.00)", "$(12.00)" or "(12.00) USD".
+//
+// The decimal mark is detected automatically: when both periods and
+// commas are used, the last one is the decimal mark; a separator that
+// is repeated is a group separator. A single comma followed by
+// exactly three digits, as in "1,234", is treated as a group separator,
+// while a single period is always a decimal mark. Use
+// ParseCurrencyLocale() when the locale of the input is known.
+//
+// Decimals beyond the four places stored by Currency are rounded
+// using CurrencyRounding().
+//
+// If the string can't be parsed, returns zero and
+// a *CurrencyParseError describing the offending position.
+func ParseCurrency(s string) (Currency, error) {
+	return parseCurrency(s, nil)
+} //                                                               ParseCurrency
+
+// ParseCurrencyLocale converts a formatted amount to Currency like
+// ParseCurrency(), but uses the decimal and group separators of the
+// specified locale instead of detecting them. Does not log errors.
+//
+// For example, "1.234" is parsed as 1234 when loc is
+// NumberLocales["de-DE"], and as 1.234 when it is NumberLocales["en-US"].
+func ParseCurrencyLocale(s string, loc NumberLocale) (Currency, error) {
+	return parseCurrency(s, &loc)
+} //                                                         ParseCurrencyLocale
+
+// -----------------------------------------------------------------------------
+// # Internal Functions
+
+// currencyParseChar is a digit or separator of
+// the number being parsed, with its position.
+type currencyParseChar struct {
+	ch  rune
+	pos int
+}
+
+// parseCurrency implements ParseCurrency() and ParseCurrencyLocale().
+// If loc is nil, the decimal mark is detected automatically.
+func parseCurrency(s string, loc *NumberLocale) (Currency, error) {
+	fail := func(pos int, reason string) (Currency, error) {
+		return Currency{0},
+			&CurrencyParseError{Input: s, Pos: pos, Reason: reason}
+	}
+	const (
+		beforeNumber = iota
+		inNumber
+		afterNumber
+	)
+	var (
+		phase     = beforeNumber
+		num       []currencyParseChar // digits and separators
+		hasSign   bool
+		negative  bool
+		hasSymbol bool
+		parenPos  = -1 // position of '(' if any
+		closed    bool // true after ')'
+	)
+	for i := 0; i < len(s); {
+		ch, size := utf8.DecodeRuneInString(s[i:])
+		if ch == utf8.RuneError && size == 1 {
+			return fail(i, "invalid UTF-8 encoding")
+		}
+		isSymbol := unicode.IsLetter(ch) || unicode.Is(unicode.Sc, ch)
+		if closed && !unicode.IsSpace(ch) && !isSymbol {
+			return fail(i, "unexpected character after ')'")
+		}
+		switch {
+		case unicode.IsSpace(ch):
+			// spaces between digits separate digit groups
+			if phase == inNumber {
+				next, _ := utf8.DecodeRuneInString(s[i+size:])
+				if next >= '0' && next <= '9' &&
+					parseCurrencyIsGroup(ch, loc) {
+					num = append(num, currencyParseChar{' ', i})
+				} else {
+					phase = afterNumber
+				}
+			}
+		case ch >= '0' && ch <= '9':
+			if phase == afterNumber {
+				return fail(i, "unexpected digit")
+			}
+			phase = inNumber
+			num = append(num, currencyParseChar{ch, i})
+		case ch == '.' || ch == ',' || ch == '\'' || ch == '’':
+			next, _ := utf8.DecodeRuneInString(s[i+size:])
+			switch {
+			case phase == inNumber:
+				num = append(num, currencyParseChar{ch, i})
+			case ch == '.' && hasSymbol && i > 0 &&
+				unicode.IsLetter(rune(s[i-1])):
+				// allow abbreviated symbols like "Fr." or "Rs."
+			case phase == beforeNumber && next >= '0' && next <= '9':
+				phase = inNumber
+				num = append(num, currencyParseChar{ch, i})
+			default:
+				return fail(i, fmt.Sprintf("unexpected %q", ch))
+			}
+		case ch == '-' || ch == '+' || ch == '−':
+			if hasSign {
+				return fail(i, "more than one sign")
+			}
+			if phase != beforeNumber && ch == '+' {
+				return fail(i, "unexpected '+'")
+			}
+			if phase != beforeNumber && parenPos != -1 {
+				return fail(i, "trailing minus inside parentheses")
+			}
+			if phase == inNumber {
+				phase = afterNumber
+			}
+			hasSign, negative = true, ch != '+'
+		case ch == '(':
+			if phase != beforeNumber || parenPos != -1 || hasSign {
+				return fail(i, "unexpected '('")
+			}
+			parenPos = i
+		case ch == ')':
+			if parenPos == -1 || phase == beforeNumber {
+				return fail(i, "unexpected ')'")
+			}
+			if hasSign {
+				return fail(i, "sign inside parentheses")
+			}
+			closed, phase = true, afterNumber
+		case isSymbol:
+			if hasSymbol {
+				return fail(i, "more than one currency symbol")
+			}
+			hasSymbol = true
+			if phase == inNumber {
+				phase = afterNumber
+			}
+			// skip the whole symbol, e.g. "USD" or "R$"
+			for i+size < len(s) {
+				next, n := utf8.DecodeRuneInString(s[i+size:])
+				if !unicode.IsLetter(next) && !unicode.Is(unicode.Sc, next) {
+					break
+				}
+				size += n
+			}
+		default:
+			return fail(i, fmt.Sprintf("invalid character %q", ch))
+		}
+		i += size
+	}
+	if parenPos != -1 && !closed {
+		return fail(len(s), "missing ')'")
+	}
+	if len(num) == 0 {
+		return fail(len(s), "no digits")
+	}
+	digits, err := parseCurrencyDigits(s, num, loc)
+	if err != nil {
+		return Currency{0}, err
+	}
+	if negative || parenPos != -1 {
+		digits = "-" + digits
+	}
+	ret, err := currencyParse(digits, CurrencyRounding())
+	if err != nil {
+		return fail(num[0].pos, err.Error())
+	}
+	return ret, nil
+} //                                                               parseCurrency
+
+// parseCurrencyDigits validates the digits and separators of a
+// number and returns the number without group separators and
+// with a period as the decimal mark.
+func parseCurrencyDigits(
+	s string, num []currencyParseChar, loc *NumberLocale,
+) (string, error) {
+	fail := func(pos int, reason string) (string, error) {
+		return "", &CurrencyParseError{Input: s, Pos: pos, Reason: reason}
+	}
+	var (
+		decimal   = parseCurrencySeparator(num, loc)
+		sb        strings.Builder
+		hasPoint  bool
+		groups    []int // sizes of digit groups before the decimal mark
+		groupPos  []int // positions of group separators
+		groupSize int
+	)
+	for i, c := range num {
+		if c.ch >= '0' && c.ch <= '9' {
+			sb.WriteRune(c.ch)
+			if !hasPoint {
+				groupSize++
+			}
+			continue
+		}
+		if i == len(num)-1 || num[i+1].ch < '0' || num[i+1].ch > '9' {
+			return fail(c.pos, fmt.Sprintf("misplaced %q", c.ch))
+		}
+		switch {
+		case c.ch == decimal && !hasPoint:
+			hasPoint = true
+			sb.WriteRune('.')
+		case c.ch == decimal:
+			return fail(c.pos, "more than one decimal mark")
+		case hasPoint:
+			return fail(c.pos, "group separator after decimal mark")
+		case i == 0 || !parseCurrencyIsGroup(c.ch, loc):
+			return fail(c.pos, fmt.Sprintf("unexpected %q", c.ch))
+		default:
+			groups = append(groups, groupSize)
+			groupPos = append(groupPos, c.pos)
+			groupSize = 0
+		}
+	}
+	// the last group must have 3 digits, the groups between it and
+	// the first one 2 or 3 digits (to allow lakh grouping)
+	for i, size := range groups {
+		if (i == 0 && size > 3) || (i > 0 && size != 2 && size != 3) {
+			return fail(groupPos[i], "invalid digit grouping")
+		}
+	}
+	if len(groups) > 0 && groupSize != 3 {
+		return fail(groupPos[len(groupPos)-1], "invalid digit grouping")
+	}
+	return sb.String(), nil
+} //                                                         parseCurrencyDigits
+
+// parseCurrencyIsGroup returns true if ch can be
+// a digit group separator in the specified locale.
+// If loc is nil, accepts any group separator.
+func parseCurrencyIsGroup(ch rune, loc *NumberLocale) bool {
+	if loc == nil {
+		return true
+	}
+	group, _ := utf8.DecodeRuneInString(loc.GroupSeparator)
+	if unicode.IsSpace(group) {
+		return unicode.IsSpace(ch)
+	}
+	if group == '\'' || group == '’' {
+		return ch == '\'' || ch == '’'
+	}
+	return loc.GroupSeparator != "" && ch == group
+} //                                                        parseCurrencyIsGroup
+
+// parseCurrencySeparator returns the decimal mark of a number: the
+// locale's decimal separator, or if loc is nil, the decimal mark
+// detected from the separators used (see ParseCurrency()).
+// Returns zero if the number has no decimal mark.
+func parseCurrencySeparator(num []currencyParseChar, loc *NumberLocale) rune {
+	if loc != nil {
+		if loc.DecimalSeparator == "" {
+			return '.'
+		}
+		ret, _ := utf8.DecodeRuneInString(loc.DecimalSeparator)
+		return ret
+	}
+	var (
+		last      = -1 // index of the last period or comma
+		count     = 0  // number of times it is used
+		hasOthers = false
+	)
+	for i, c := range num {
+		switch c.ch {
+		case '.', ',':
+			if last == -1 || c.ch != num[last].ch {
+				count = 0
+			}
+			last = i
+			count++
+		case ' ', '\'', '’':
+			hasOthers = true
+		}
+	}
+	if last == -1 {
+		return 0
+	}
+	ch := num[last].ch
+	for _, c := range num[:last] {
+		if (c.ch == '.' || c.ch == ',') && c.ch != ch {
+			return ch // both are used: the last one is the decimal mark
+		}
+	}
+	if count > 1 {
+		return 0
+	}
+	if ch == ',' && !hasOthers && len(num)-last-1 == 3 {
+		return 0 // "1,234"
+	}
+	return ch
+} //                                                      parseCurrencySeparator
+
+// end
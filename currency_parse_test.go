@@ -0,0 +1,214 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                        zr/[currency_parse_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Error Type
+//   Test_cprs_CurrencyParseError_Error_
+//
+// # Parsing Functions
+//   Test_cprs_ParseCurrency_
+//   Test_cprs_ParseCurrencyLocale_
+
+//  to test all items in currency_parse.go use:
+//      go test --run Test_cprs_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Error Type
+
+// go test --run Test_cprs_CurrencyParseError_Error_
+func Test_cprs_CurrencyParseError_Error_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *CurrencyParseError) Error() string
+	//
+	_, err := ParseCurrency("$12x")
+	TEqual(t, err.Error(),
+		`failed parsing "$12x" at 3: more than one currency symbol`)
+	//
+	perr, ok := err.(*CurrencyParseError)
+	TTrue(t, ok)
+	if ok {
+		TEqual(t, *perr, CurrencyParseError{
+			Input: "$12x", Pos: 3, Reason: "more than one currency symbol",
+		})
+	}
+	var nilErr *CurrencyParseError
+	TEqual(t, nilErr.Error(), ENilReceiver)
+} //                                         Test_cprs_CurrencyParseError_Error_
+
+// -----------------------------------------------------------------------------
+// # Parsing Functions
+
+// go test --run Test_cprs_ParseCurrency_
+func Test_cprs_ParseCurrency_(t *testing.T) {
+	TBegin(t)
+	//
+	// ParseCurrency(s string) (Currency, error)
+	//
+	test := func(s string, expect Currency) {
+		got, err := ParseCurrency(s)
+		if err != nil || got != expect {
+			TFailf(t, `ParseCurrency(%q) returned %v, %v instead of %v`,
+				s, got, err, expect)
+		}
+	}
+	// plain numbers
+	test("0", cur(0))
+	test("12", cur(12))
+	test("+12", cur(12))
+	test(".5", cur(0.5))
+	test(" 1234.5678 ", cur("1234.5678"))
+	test("900719925474.0993", cur("900719925474.0993"))
+	//
+	// symbols and codes
+	test("$1,234.50", cur(1234.5))
+	test("1.234,50 €", cur(1234.5))
+	test("1.234,50 €", cur(1234.5))
+	test("USD 12.00", cur(12))
+	test("12.00 USD", cur(12))
+	test("R$ 1.234,56", cur(1234.56))
+	test("Fr. 1'234.50", cur(1234.5))
+	test("CHF 1’234.50", cur(1234.5))
+	test("₹12,34,567.89", cur(1234567.89))
+	test("1\u00a0234\u00a0567,89 zł", cur(1234567.89))
+	test("1 234,5 €", cur(1234.5))
+	//
+	// negative amounts
+	test("-$1,234.50", cur(-1234.5))
+	test("$-1,234.50", cur(-1234.5))
+	test("(1,234.50)", cur(-1234.5))
+	test("($1,234.50)", cur(-1234.5))
+	test("(1.234,50 €)", cur(-1234.5))
+	test("(USD 12.00)", cur(-12))
+	test("USD (12.00)", cur(-12))
+	test("$(12.00)", cur(-12))
+	test("$ (12.00)", cur(-12))
+	test("(12.00) USD", cur(-12))
+	test("(12.00)USD", cur(-12))
+	test("(1.234,50) € ", cur(-1234.5))
+	test("1,234.50-", cur(-1234.5))
+	test("1.234,50 €-", cur(-1234.5))
+	test("EUR 12,50-", cur(-12.5))
+	test("−7.5", cur(-7.5))
+	//
+	// decimal mark detection
+	test("1,234", cur(1234))
+	test("1,5", cur(1.5))
+	test("1,23", cur(1.23))
+	test("1,2345", cur(1.2345))
+	test("1.234", cur(1.234))
+	test("1.234.567", cur(1234567))
+	test("1,234,567", cur(1234567))
+	test("1.234.567,8", cur(1234567.8))
+	test("1,234,567.8", cur(1234567.8))
+	test("1 234,567", cur(1234.567))
+	//
+	// decimals beyond 4 places are rounded using CurrencyRounding()
+	test("0.00019", cur("0.0001"))
+	//
+	testErr := func(s string, pos int, reason string) {
+		got, err := ParseCurrency(s)
+		perr, _ := err.(*CurrencyParseError)
+		if perr == nil || perr.Pos != pos || perr.Reason != reason ||
+			got != cur(0) {
+			TFailf(t, `ParseCurrency(%q) returned %v, %v.`+
+				` expected error at %d: %s`, s, got, err, pos, reason)
+		}
+	}
+	testErr("", 0, "no digits")
+	testErr("   ", 3, "no digits")
+	testErr("USD", 3, "no digits")
+	testErr("12 34 5", 5, "invalid digit grouping")
+	testErr("1,23,4", 4, "invalid digit grouping")
+	testErr("1234,567", 4, "invalid digit grouping")
+	testErr("1,234.567,8", 5, "group separator after decimal mark")
+	testErr("1.234,5,6", 7, "more than one decimal mark")
+	testErr("1.23.4,5", 4, "invalid digit grouping")
+	testErr("12.", 2, "misplaced '.'")
+	testErr("1,,234", 1, "misplaced ','")
+	testErr("1.5.", 3, "misplaced '.'")
+	testErr("$12 $", 4, "more than one currency symbol")
+	testErr("12 x 3", 5, "unexpected digit")
+	testErr("--12", 1, "more than one sign")
+	testErr("-12-", 3, "more than one sign")
+	testErr("12+", 2, "unexpected '+'")
+	testErr("(12", 3, "missing ')'")
+	testErr("12)", 2, "unexpected ')'")
+	testErr("(-12)", 4, "sign inside parentheses")
+	testErr("(12-)", 3, "trailing minus inside parentheses")
+	testErr("(12) 3", 5, "unexpected character after ')'")
+	testErr("(12) -", 5, "unexpected character after ')'")
+	testErr("$(12) USD", 6, "more than one currency symbol")
+	testErr("(USD 12) EUR", 9, "more than one currency symbol")
+	testErr("(12) USD)", 8, "unexpected character after ')'")
+	testErr("$-(12)", 2, "unexpected '('")
+	testErr("1(2)", 1, "unexpected '('")
+	testErr("12#", 2, "invalid character '#'")
+	testErr("12\xff", 2, "invalid UTF-8 encoding")
+	testErr("922,337,203,685,477.00", 0, "overflow: 922337203685477.00")
+} //                                                    Test_cprs_ParseCurrency_
+
+// go test --run Test_cprs_ParseCurrencyLocale_
+func Test_cprs_ParseCurrencyLocale_(t *testing.T) {
+	TBegin(t)
+	//
+	// ParseCurrencyLocale(s string, loc NumberLocale) (Currency, error)
+	//
+	test := func(s, tag string, expect Currency) {
+		got, err := ParseCurrencyLocale(s, NumberLocales[tag])
+		if err != nil || got != expect {
+			TFailf(t, `ParseCurrencyLocale(%q, %q) returned %v, %v`+
+				` instead of %v`, s, tag, got, err, expect)
+		}
+	}
+	test("1.234", "de-DE", cur(1234))
+	test("1.234", "en-US", cur(1.234))
+	test("1,234", "de-DE", cur(1.234))
+	test("1,234", "en-US", cur(1234))
+	test("1.234.567,89 €", "de-DE", cur(1234567.89))
+	test("1\u202f234\u202f567,89 €", "fr-FR", cur(1234567.89))
+	test("1 234 567,89 €", "fr-FR", cur(1234567.89))
+	test("CHF 1'234.50", "de-CH", cur(1234.5))
+	test("₹12,34,567.89", "en-IN", cur(1234567.89))
+	test("(1,234.50)", "en-US", cur(-1234.5))
+	test("5", "", cur(5))
+	test("5.5", "", cur(5.5))
+	//
+	// output of FmtLocale() can be parsed back
+	for tag, loc := range NumberLocales {
+		for _, n := range []Currency{cur(-1234567.89), cur(0.5), cur(42)} {
+			s := n.FmtLocale(2, loc, "XYZ")
+			got, err := ParseCurrencyLocale(s, loc)
+			if err != nil || got != n {
+				TFailf(t, `%s: ParseCurrencyLocale(%q) returned %v, %v`,
+					tag, s, got, err)
+			}
+		}
+	}
+	testErr := func(s, tag string, pos int, reason string) {
+		_, err := ParseCurrencyLocale(s, NumberLocales[tag])
+		perr, _ := err.(*CurrencyParseError)
+		if perr == nil || perr.Pos != pos || perr.Reason != reason {
+			TFailf(t, `ParseCurrencyLocale(%q, %q) returned %v.`+
+				` expected error at %d: %s`, s, tag, err, pos, reason)
+		}
+	}
+	testErr("1,234.50", "de-DE", 5, "group separator after decimal mark")
+	testErr("1.234,50", "en-US", 5, "group separator after decimal mark")
+	testErr("1'234.50", "en-US", 1, "unexpected '\\''")
+	testErr("1 234", "en-US", 2, "unexpected digit")
+	testErr("1,234", "", 1, "unexpected ','")
+} //                                              Test_cprs_ParseCurrencyLocale_
+
+// end
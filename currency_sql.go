@@ -0,0 +1,151 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                               zr/[currency_sql.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # NullCurrency Type:
+//   NullCurrency struct
+//
+// # sql.Scanner Interface:
+//   (n *Currency) Scan(src interface{}) error
+//   (n *NullCurrency) Scan(src interface{}) error
+//
+// # driver.Valuer Interface:
+//   (n Currency) Value() (driver.Value, error)
+//   (n NullCurrency) Value() (driver.Value, error)
+//
+// # Helper Function
+//   currencyOfSQL(src interface{}) (Currency, error)
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// currencySQLNumberEx matches the text of a number received from
+// a database: an optional sign, digits and an optional decimal part.
+var currencySQLNumberEx = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)$`)
+
+// -----------------------------------------------------------------------------
+// # NullCurrency Type:
+
+// NullCurrency represents a Currency that may be NULL.
+// It implements the sql.Scanner and driver.Valuer interfaces,
+// so it can be used as a scan destination and a query argument,
+// similar to sql.NullInt64 and sql.NullString.
+type NullCurrency struct {
+	Currency Currency
+	Valid    bool // Valid is true if Currency is not NULL
+} //                                                                NullCurrency
+
+// -----------------------------------------------------------------------------
+// # sql.Scanner Interface:
+
+// Scan assigns a value from a database driver to the currency
+// and implements the sql.Scanner interface.
+//
+// Accepts int64, float64, []byte and string values. Text values,
+// e.g. NUMERIC columns returned by Postgres, are parsed exactly and
+// must be plain numbers like "-1234.5678", without digit grouping,
+// exponents or surrounding spaces. Returns an error if src is NULL
+// (use NullCurrency to read nullable columns), if src is blank or
+// can't be converted, or if the number is too big for Currency.
+func (n *Currency) Scan(src interface{}) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	if src == nil {
+		return errors.New("can not scan NULL into Currency")
+	}
+	ret, err := currencyOfSQL(src)
+	if err != nil {
+		return err
+	}
+	*n = ret
+	return nil
+} //                                                                        Scan
+
+// Scan assigns a value from a database driver to the nullable
+// currency and implements the sql.Scanner interface.
+// A NULL value sets Valid to false and Currency to zero.
+// See Currency.Scan() for a list of accepted types.
+func (n *NullCurrency) Scan(src interface{}) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if n == nil {
+		return errors.New(ENilReceiver)
+	}
+	if src == nil {
+		n.Currency, n.Valid = Currency{0}, false
+		return nil
+	}
+	ret, err := currencyOfSQL(src)
+	if err != nil {
+		return err
+	}
+	n.Currency, n.Valid = ret, true
+	return nil
+} //                                                                        Scan
+
+// -----------------------------------------------------------------------------
+// # driver.Valuer Interface:
+
+// Value returns the currency as a numeric string without any loss of
+// precision (e.g. "1234.5678") and implements the driver.Valuer interface.
+// Returns an error if the currency holds an overflow value.
+func (n Currency) Value() (driver.Value, error) {
+	if n.Overflow() != 0 {
+		return nil, errors.New(EOverflow)
+	}
+	return n.String(), nil
+} //                                                                       Value
+
+// Value returns nil if the currency is NULL, otherwise the
+// currency as a numeric string, and implements driver.Valuer.
+func (n NullCurrency) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Currency.Value()
+} //                                                                       Value
+
+// -----------------------------------------------------------------------------
+// # Helper Function
+
+// currencyOfSQL converts a value received from a database driver to
+// Currency. Does not log errors. See Currency.Scan() for details.
+func currencyOfSQL(src interface{}) (Currency, error) {
+	switch v := src.(type) {
+	case int64:
+		if v < -CurrencyIntLimit || v > CurrencyIntLimit {
+			return Currency{0}, errors.New(EOverflow + ": " +
+				strconv.FormatInt(v, 10))
+		}
+		return Currency{v * 1e4}, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Currency{0}, fmt.Errorf("%s: %v", EInvalidArg, v)
+		}
+		return currencyParse(
+			strconv.FormatFloat(v, 'f', -1, 64), CurrencyRounding())
+	case []byte:
+		return currencyOfSQL(string(v))
+	case string:
+		if !currencySQLNumberEx.MatchString(v) {
+			return Currency{0}, fmt.Errorf(
+				"can not scan %q into Currency: not a number", v)
+		}
+		return currencyParse(v, CurrencyRounding())
+	}
+	return Currency{0}, fmt.Errorf("can not scan %s into Currency: %v",
+		reflect.TypeOf(src), src)
+} //                                                               currencyOfSQL
+
+// end
@@ -0,0 +1,286 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                          zr/[currency_sql_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # sql.Scanner Interface:
+//   Test_csql_Currency_Scan_
+//   Test_csql_NullCurrency_Scan_
+//
+// # driver.Valuer Interface:
+//   Test_csql_Currency_Value_
+//
+// # Fake Driver
+//   Test_csql_FakeDriver_
+//   csqlDB(t *testing.T, rows ...driver.Value) *sql.DB
+
+//  to test all items in currency_sql.go use:
+//      go test --run Test_csql_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # sql.Scanner Interface:
+
+// go test --run Test_csql_Currency_Scan_
+func Test_csql_Currency_Scan_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n *Currency) Scan(src interface{}) error
+	//
+	test := func(src interface{}, expect int64) {
+		var n Currency
+		err := n.Scan(src)
+		if err != nil || n.i64 != expect {
+			TFailf(t, `Scan(%#v) returned %d, %v instead of %d`,
+				src, n.i64, err, expect)
+		}
+	}
+	test(int64(12), 120000)
+	test(int64(-922337203685476), -9223372036854760000)
+	test(float64(0.29), 2900)
+	test(float64(-1234.5678), -12345678)
+	test([]byte("900719925474.0993"), 9007199254740993)
+	test([]byte("-0.0001"), -1)
+	test("1234.50", 12345000)
+	test("+12.", 120000)
+	test(".5", 5000)
+	//
+	testErr := func(src interface{}) {
+		n := cur(7)
+		err := n.Scan(src)
+		if err == nil || n != cur(7) {
+			TFailf(t, `Scan(%#v) returned %v, %v. must fail`, src, n, err)
+		}
+	}
+	testErr(nil)
+	testErr(int64(922337203685477))
+	testErr(math.NaN())
+	testErr("abc")
+	testErr([]byte("1e20"))
+	testErr("1e3")
+	testErr("1,000")
+	testErr("")
+	testErr([]byte("  "))
+	testErr(" 12")
+	testErr("-")
+	testErr(".")
+	testErr("1.2.3")
+	testErr("NaN")
+	testErr("99999999999999999")
+	testErr(true)
+	{
+		var n *Currency
+		TTrue(t, n.Scan("1") != nil)
+	}
+} //                                                    Test_csql_Currency_Scan_
+
+// go test --run Test_csql_NullCurrency_Scan_
+func Test_csql_NullCurrency_Scan_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n *NullCurrency) Scan(src interface{}) error
+	//
+	{
+		n := NullCurrency{Currency: cur(1), Valid: true}
+		TTrue(t, n.Scan(nil) == nil)
+		TEqual(t, n, NullCurrency{})
+	}
+	{
+		var n NullCurrency
+		TTrue(t, n.Scan("12.34") == nil)
+		TEqual(t, n, NullCurrency{Currency: cur("12.34"), Valid: true})
+	}
+	{
+		var n NullCurrency
+		TTrue(t, n.Scan("x") != nil)
+		TFalse(t, n.Valid)
+	}
+	{
+		var n *NullCurrency
+		TTrue(t, n.Scan("1") != nil)
+	}
+} //                                                Test_csql_NullCurrency_Scan_
+
+// -----------------------------------------------------------------------------
+// # driver.Valuer Interface:
+
+// go test --run Test_csql_Currency_Value_
+func Test_csql_Currency_Value_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Value() (driver.Value, error)
+	// (n NullCurrency) Value() (driver.Value, error)
+	//
+	test := func(v driver.Valuer, expect driver.Value) {
+		got, err := v.Value()
+		if err != nil || got != expect {
+			TFailf(t, `Value() returned %#v, %v instead of %#v`,
+				got, err, expect)
+		}
+	}
+	test(cur("1234.5678"), "1234.5678")
+	test(cur("-0.01"), "-0.01")
+	test(cur(0), "0")
+	test(NullCurrency{}, nil)
+	test(NullCurrency{Currency: cur(5), Valid: true}, "5")
+	//
+	_, err := Currency{math.MaxInt64}.Value()
+	TTrue(t, err != nil)
+} //                                                   Test_csql_Currency_Value_
+
+// -----------------------------------------------------------------------------
+// # Fake Driver
+
+// go test --run Test_csql_FakeDriver_
+func Test_csql_FakeDriver_(t *testing.T) {
+	TBegin(t)
+	//
+	// write values through database/sql
+	{
+		db := csqlDB(t)
+		defer db.Close()
+		_, err := db.Exec("INSERT", cur("900719925474.0993"),
+			NullCurrency{}, NullCurrency{Currency: cur("-2.5"), Valid: true})
+		TTrue(t, err == nil)
+		TEqual(t, len(csqlArgs), 3)
+		if len(csqlArgs) == 3 {
+			TEqual(t, csqlArgs[0], "900719925474.0993")
+			TTrue(t, csqlArgs[1] == nil)
+			TEqual(t, csqlArgs[2], "-2.5")
+		}
+	}
+	// read values the way different drivers return them
+	{
+		db := csqlDB(t, int64(12), float64(0.29), []byte("1234.5678"),
+			"-1.5", nil)
+		defer db.Close()
+		rows, err := db.Query("SELECT")
+		TTrue(t, err == nil)
+		var got []string
+		for rows.Next() {
+			var n NullCurrency
+			if err := rows.Scan(&n); err != nil {
+				TFail(t, err)
+			}
+			if !n.Valid {
+				got = append(got, "NULL")
+				continue
+			}
+			got = append(got, n.Currency.String())
+		}
+		rows.Close()
+		TEqual(t, strings.Join(got, " "), "12 0.29 1234.5678 -1.5 NULL")
+	}
+	// NULL can't be scanned into Currency
+	{
+		db := csqlDB(t, nil)
+		defer db.Close()
+		var n Currency
+		err := db.QueryRow("SELECT").Scan(&n)
+		TTrue(t, err != nil)
+	}
+} //                                                       Test_csql_FakeDriver_
+
+var (
+	csqlArgs []driver.Value // arguments received by the last Exec()
+	csqlRows []driver.Value // values returned by the next Query()
+	csqlOnce sync.Once
+)
+
+// csqlDB registers the fake driver (once) and returns
+// a database whose queries will return the given rows.
+func csqlDB(t *testing.T, rows ...driver.Value) *sql.DB {
+	csqlOnce.Do(func() {
+		sql.Register("zr_csql", csqlDriver{})
+	})
+	csqlArgs, csqlRows = nil, rows
+	db, err := sql.Open("zr_csql", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+} //                                                                      csqlDB
+
+// csqlDriver is a minimal database driver that
+// stores arguments and returns preset values.
+type csqlDriver struct{}
+
+// csqlConn is the fake driver's connection.
+type csqlConn struct{}
+
+// csqlStmt is the fake driver's prepared statement.
+type csqlStmt struct{}
+
+// csqlRowSet is the fake driver's result set with a single column.
+type csqlRowSet struct {
+	values []driver.Value
+	at     int
+}
+
+func (csqlDriver) Open(string) (driver.Conn, error) {
+	return csqlConn{}, nil
+}
+
+func (csqlConn) Begin() (driver.Tx, error) {
+	return nil, errors.New(ENotHandled)
+}
+
+func (csqlConn) Close() error {
+	return nil
+}
+
+func (csqlConn) Prepare(string) (driver.Stmt, error) {
+	return csqlStmt{}, nil
+}
+
+func (csqlStmt) Close() error {
+	return nil
+}
+
+func (csqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	csqlArgs = args
+	return driver.RowsAffected(1), nil
+}
+
+func (csqlStmt) NumInput() int {
+	return -1
+}
+
+func (csqlStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &csqlRowSet{values: csqlRows}, nil
+}
+
+func (*csqlRowSet) Close() error {
+	return nil
+}
+
+func (*csqlRowSet) Columns() []string {
+	return []string{"amount"}
+}
+
+func (ob *csqlRowSet) Next(dest []driver.Value) error {
+	if ob.at >= len(ob.values) {
+		return io.EOF
+	}
+	dest[0] = ob.values[ob.at]
+	ob.at++
+	return nil
+}
+
+// end
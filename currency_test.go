@@ -6,30 +6,49 @@
 package zr
 
 // # Currency Factory:
+//   Test_crcy_CurrencyE_
 //   Test_crcy_CurrencyOf_
+//   Test_crcy_CurrencyOfS_
 //
 // # String Output:
 //   Test_crcy_Currency_Fmt_
+//   Test_crcy_Currency_Fmt_negative_
 //   Test_crcy_Currency_InWordsEN_
 //   Test_crcy_Currency_String_
 //
+// # Rounding:
+//   Test_crcy_Currency_Round_
+//
+// # Allocation:
+//   Test_crcy_Currency_Allocate_
+//   Test_crcy_Currency_AllocateAt_
+//   Test_crcy_Currency_Split_
+//
 // # Division:
 //   Test_crcy_Currency_Div_
+//   Test_crcy_Currency_DivE_
 //   Test_crcy_Currency_DivFloat_
+//   Test_crcy_Currency_DivFloatRound_
 //   Test_crcy_Currency_DivInt_
+//   Test_crcy_Currency_DivRound_
 //
 // # Multiplication:
 //   Test_crcy_Currency_Mul_
+//   Test_crcy_Currency_MulE_
 //   Test_crcy_Currency_MulFloat_
+//   Test_crcy_Currency_MulFloatRound_
 //   Test_crcy_Currency_MulInt_
+//   Test_crcy_Currency_MulRound_
 //
 // # Addition:
 //   Test_crcy_Currency_Add_
+//   Test_crcy_Currency_AddE_
 //   Test_crcy_Currency_AddFloat_
 //   Test_crcy_Currency_AddInt_
 //
 // # Subtraction:
 //   Test_crcy_Currency_Sub_
+//   Test_crcy_Currency_SubE_
 //   Test_crcy_Currency_SubFloat_
 //   Test_crcy_Currency_SubInt_
 //
@@ -39,6 +58,7 @@ package zr
 //   Test_crcy_Currency_Int64_
 //   Test_crcy_Currency_IsEqual_
 //   Test_crcy_Currency_IsNegative_
+//   Test_crcy_Currency_IsOverflow_
 //   Test_crcy_Currency_IsZero_
 //   Test_crcy_Currency_Overflow_
 //
@@ -46,6 +66,11 @@ package zr
 //   Test_crcy_Currency_MarshalJSON_
 //   Test_crcy_Currency_UnmarshalJSON_
 //
+// # Text, Binary and XML Encoding:
+//   Test_crcy_Currency_MarshalBinary_
+//   Test_crcy_Currency_MarshalText_
+//   Test_crcy_Currency_MarshalXML_
+//
 // # Helper Functions
 //   Test_crcy_currencyOverflow_
 //
@@ -53,6 +78,18 @@ package zr
 //   arC(ar ...interface{}) (ret []Currency)
 //   arF(ar ...interface{}) (ret []float64)
 //   arI(ar ...interface{}) (ret []int)
+//   crcyErrTest(
+//       t *testing.T,
+//       opName string,
+//       n Currency,
+//       values []Currency,
+//       got Currency,
+//       err error,
+//       expect Currency,
+//       expectErr string,
+//   )
+//   crcyJoin(values []Currency) string
+//   crcySum(values []Currency) Currency
 //   cur = CurrencyOf
 //   curFloatOpTest(
 //       t *testing.T,
@@ -90,7 +127,10 @@ package zr
 //      go tool cover -html=cover.out
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"math"
 	"strings"
@@ -101,6 +141,44 @@ import (
 // -----------------------------------------------------------------------------
 // # Currency Factory:
 
+// go test --run Test_crcy_CurrencyE_
+func Test_crcy_CurrencyE_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyE(value interface{}) (Currency, error)
+	//
+	test := func(input interface{}, expect Currency, expectErr bool) {
+		got, err := CurrencyE(input)
+		if got.i64 != expect.i64 || (err != nil) != expectErr {
+			TFailf(t, `CurrencyE(%v) returned %v, %v instead of %v`,
+				input, got, err, expect)
+		}
+	}
+	// floats are converted using their shortest decimal representation
+	test(0.29, Currency{2900}, false)
+	test(1.1, Currency{11000}, false)
+	test(-0.29, Currency{-2900}, false)
+	test(float32(0.29), Currency{2900}, false)
+	test(float32(1.1), Currency{11000}, false)
+	test(1234.56789, Currency{12345678}, false)
+	test(math.NaN(), Currency{0}, true)
+	//
+	// json.Number
+	test(json.Number("12.34"), Currency{123400}, false)
+	test(json.Number("-1e2"), Currency{-1000000}, false)
+	//
+	// non-numeric strings
+	test("abc", Currency{0}, true)
+	test("1.2.3", Currency{0}, true)
+	test("1-2", Currency{0}, true)
+	//
+	// overflow
+	DisableErrors()
+	test("922337203685477", Currency{math.MaxInt64}, true)
+	test("-1e20", Currency{math.MinInt64}, true)
+	EnableErrors()
+} //                                                        Test_crcy_CurrencyE_
+
 // go test --run Test_crcy_CurrencyOf_
 func Test_crcy_CurrencyOf_(t *testing.T) {
 	TBegin(t)
@@ -326,6 +404,90 @@ func Test_crcy_CurrencyOf_(t *testing.T) {
 	EnableErrors()
 } //                                                       Test_crcy_CurrencyOf_
 
+// go test --run Test_crcy_CurrencyOfS_
+func Test_crcy_CurrencyOfS_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyOfS(s string) Currency
+	//
+	test := func(input string, expect int64) {
+		got := CurrencyOfS(input)
+		if got.i64 != expect {
+			TFailf(t, `CurrencyOfS(%q) returned %d instead of %d`,
+				input, got.i64, expect)
+		}
+	}
+	test("", 0)
+	test("  ", 0)
+	test("0", 0)
+	test("-0", 0)
+	test("+1", 1e4)
+	test("0.29", 2900)
+	test(".5", 5000)
+	test("5.", 50000)
+	test(" -1,234.5 ", -12345000)
+	test("1.5e3", 15000000)
+	test("15E-4", 15)
+	test("1e-9", 0)
+	//
+	// beyond the precision of float64 (2^53 = 9007199254740992)
+	test("900719925474.0993", 9007199254740993)
+	test("922337203685476.9999", 9223372036854769999)
+	test("-922337203685476.9999", -9223372036854769999)
+	//
+	// rounding beyond the 4th decimal place
+	test("1234.56789", 12345678)
+	test("-1234.56789", -12345678)
+	func() {
+		defer SetCurrencyRounding(CurrencyRounding())
+		SetCurrencyRounding(RoundHalfEven)
+		test("1234.56789", 12345679)
+		test("-1234.56789", -12345679)
+		test("0.00005", 0)
+		test("0.00015", 2)
+		test("0.000050000000000000000001", 1)
+		SetCurrencyRounding(RoundHalfUp)
+		test("0.00005", 1)
+		test("-0.00005", -1)
+		SetCurrencyRounding(RoundCeiling)
+		test("0.00001", 1)
+		test("-0.00001", 0)
+		test("1e-30", 1)
+	}()
+	//
+	// the mode can be changed while other goroutines convert values
+	func() {
+		defer SetCurrencyRounding(CurrencyRounding())
+		done := make(chan bool)
+		go func() {
+			for i := 0; i < 100; i++ {
+				mode := RoundDown
+				if i%2 == 1 {
+					mode = RoundHalfUp
+				}
+				SetCurrencyRounding(mode)
+			}
+			done <- true
+		}()
+		for i := 0; i < 100; i++ {
+			if n := CurrencyOfS("0.00005").i64; n != 0 && n != 1 {
+				TFailf(t, `CurrencyOfS("0.00005") returned %d`, n)
+			}
+		}
+		<-done
+	}()
+	// errors
+	DisableErrors()
+	{
+		ec := GetErrorCount()
+		test("12x", 0)
+		test("922337203685477", math.MaxInt64)
+		test("-922337203685477", math.MinInt64)
+		TEqual(t, GetErrorCount(), ec+3)
+	}
+	EnableErrors()
+} //                                                      Test_crcy_CurrencyOfS_
+
 // -----------------------------------------------------------------------------
 // # String Output:
 
@@ -414,10 +576,44 @@ func Test_crcy_Currency_Fmt_(t *testing.T) {
 	test("100000000000000", 0, "100,000,000,000,000")
 	test("900000000000000", 0, "900,000,000,000,000")
 	test("99999999999999", 0, "99,999,999,999,999")
+	DisableErrors() // overflow values
 	test("922337203685477", 0, "922,337,203,685,477")
 	test("-922337203685477", 0, "-922,337,203,685,477")
+	EnableErrors()
 } //                                                     Test_crcy_Currency_Fmt_
 
+// go test --run Test_crcy_Currency_Fmt_negative_
+func Test_crcy_Currency_Fmt_negative_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Fmt(decimalPlaces int) string
+	//
+	// the decimals of negative numbers were written with a second
+	// minus sign, e.g. "-1,234.-5000", before Fmt() used their
+	// absolute value
+	test := func(input string, decimalPlaces int, expect string) {
+		got := cur(input).Fmt(decimalPlaces)
+		if got != expect {
+			TFailf(t, `Currency(%s).Fmt(%v) returned %q instead of %q`,
+				input, decimalPlaces, got, expect)
+		}
+	}
+	test("-1234.5", 2, "-1,234.50")
+	test("-1234.5", -1, "-1,234.5")
+	test("-1234.5678", 4, "-1,234.5678")
+	test("-0.0123", -1, "-0.0123")
+	test("-0.5", 1, "-0.5")
+	test("-0.0001", 4, "-0.0001")
+	test("-1", 2, "-1.00")
+	test("-999999.99", 2, "-999,999.99")
+	//
+	// the same decimals as positive numbers
+	for _, s := range []string{"0.0001", "0.5", "12.34", "1234.5678"} {
+		TEqual(t, cur("-"+s).Fmt(4), "-"+cur(s).Fmt(4))
+		TEqual(t, cur("-"+s).String(), "-"+cur(s).String())
+	}
+} //                                            Test_crcy_Currency_Fmt_negative_
+
 // go test --run Test_crcy_Currency_InWordsEN_
 func Test_crcy_Currency_InWordsEN_(t *testing.T) {
 	// (n Currency) InWordsEN(fmt string) string
@@ -538,6 +734,142 @@ func Test_crcy_Currency_String_(t *testing.T) {
 	}
 } //                                                  Test_crcy_Currency_String_
 
+// -----------------------------------------------------------------------------
+// # Rounding:
+
+// go test --run Test_crcy_Currency_Round_
+func Test_crcy_Currency_Round_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Round(decimalPlaces int, mode RoundingMode) Currency
+	//
+	test := func(
+		input string, decimalPlaces int, mode RoundingMode, expect string,
+	) {
+		n := cur(input)
+		got := n.Round(decimalPlaces, mode)
+		if got != cur(expect) {
+			TFailf(t, `Currency(%s).Round(%d, %v) returned %s instead of %s`,
+				input, decimalPlaces, mode, got, expect)
+		}
+		if n != cur(input) {
+			TFail(t, `(`, input, `) mutated to `, n)
+		}
+	}
+	// the standard rounding table (see rndgTable in rounding_test.go)
+	for _, row := range rndgTable {
+		for i, mode := range rndgModes {
+			input := cur(row.tenths).DivInt(10).String()
+			test(input, 0, mode, String(row.expect[i]))
+		}
+	}
+	// rounding to cents
+	test("2.345", 2, RoundHalfEven, "2.34")
+	test("2.355", 2, RoundHalfEven, "2.36")
+	test("2.345", 2, RoundHalfUp, "2.35")
+	test("2.345", 2, RoundHalfDown, "2.34")
+	test("2.3451", 2, RoundHalfDown, "2.35")
+	test("-2.345", 2, RoundHalfEven, "-2.34")
+	test("-2.345", 2, RoundHalfUp, "-2.35")
+	test("-2.341", 2, RoundFloor, "-2.35")
+	test("-2.349", 2, RoundCeiling, "-2.34")
+	test("2.3401", 2, RoundUp, "2.35")
+	test("2.3499", 2, RoundDown, "2.34")
+	//
+	// other decimal places
+	test("1.23456", 4, RoundUp, "1.2345")
+	test("1.2345", 3, RoundHalfEven, "1.234")
+	test("1.2355", 3, RoundHalfEven, "1.236")
+	test("1234.5", -2, RoundHalfUp, "1200")
+	test("1250", -2, RoundHalfEven, "1200")
+	test("1350", -2, RoundHalfEven, "1400")
+	//
+	// overflow
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got := cur("922337203685476.9").Round(0, RoundUp)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, got, Currency{math.MaxInt64})
+	TTrue(t, got.IsOverflow())
+	TEqual(t, ec2-ec1, 1)
+} //                                                   Test_crcy_Currency_Round_
+
+// -----------------------------------------------------------------------------
+// # Allocation:
+
+// go test --run Test_crcy_Currency_Allocate_
+func Test_crcy_Currency_Allocate_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Allocate(ratios ...int) []Currency
+	//
+	test := func(input string, ratios []int, expect ...string) {
+		got := cur(input).Allocate(ratios...)
+		TEqual(t, crcyJoin(got), strings.Join(expect, " "))
+		TEqual(t, crcySum(got), cur(input))
+	}
+	test("100", arI(1, 1, 1), "33.3334", "33.3333", "33.3333")
+	test("100", arI(1, 1), "50", "50")
+	test("0.0005", arI(1, 1), "0.0003", "0.0002")
+	test("-100", arI(1, 1, 1), "-33.3334", "-33.3333", "-33.3333")
+	test("0.05", arI(3, 7), "0.015", "0.035")
+	test("0.0005", arI(3, 7), "0.0002", "0.0003")
+	test("10", arI(0, 1, 0, 1), "0", "5", "0", "5")
+	test("0.0001", arI(0, 1, 1), "0", "0.0001", "0")
+	test("922337203685476.9999", arI(1, 1),
+		"461168601842738.5", "461168601842738.4999")
+	//
+	DisableErrors()
+	TTrue(t, cur(1).Allocate() == nil)
+	TTrue(t, cur(1).Allocate(0, 0) == nil)
+	TTrue(t, cur(1).Allocate(1, -1) == nil)
+	EnableErrors()
+} //                                                Test_crcy_Currency_Allocate_
+
+// go test --run Test_crcy_Currency_AllocateAt_
+func Test_crcy_Currency_AllocateAt_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) AllocateAt(decimalPlaces int, ratios ...int) []Currency
+	//
+	test := func(input string, decimalPlaces int, ratios []int,
+		expect ...string) {
+		got := cur(input).AllocateAt(decimalPlaces, ratios...)
+		TEqual(t, crcyJoin(got), strings.Join(expect, " "))
+		TEqual(t, crcySum(got), cur(input))
+	}
+	test("100", 2, arI(1, 1, 1), "33.34", "33.33", "33.33")
+	test("100", 0, arI(1, 1, 1), "34", "33", "33")
+	test("-100", 2, arI(1, 1, 1), "-33.34", "-33.33", "-33.33")
+	test("0.05", 2, arI(3, 7), "0.02", "0.03")
+	test("1", 2, arI(1, 1, 1, 1, 1, 1, 1), "0.15", "0.15", "0.14",
+		"0.14", "0.14", "0.14", "0.14")
+	test("10.0050", 2, arI(1, 1), "5.005", "5")
+	test("10.0050", 2, arI(0, 1, 1), "0", "5.005", "5")
+	test("1000", -2, arI(1, 1, 1), "400", "300", "300")
+	test("100", 9, arI(1, 1, 1), "33.3334", "33.3333", "33.3333")
+} //                                              Test_crcy_Currency_AllocateAt_
+
+// go test --run Test_crcy_Currency_Split_
+func Test_crcy_Currency_Split_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) Split(parts int) []Currency
+	//
+	TEqual(t, crcyJoin(cur(100).Split(3)), "33.3334 33.3333 33.3333")
+	TEqual(t, crcyJoin(cur("0.0002").Split(3)), "0.0001 0.0001 0")
+	TEqual(t, crcyJoin(cur(-1).Split(3)), "-0.3334 -0.3333 -0.3333")
+	TEqual(t, crcyJoin(cur(5).Split(1)), "5")
+	for parts := 1; parts <= 50; parts++ {
+		TEqual(t, crcySum(cur("1234.5677").Split(parts)),
+			cur("1234.5677"))
+	}
+	DisableErrors()
+	TTrue(t, cur(1).Split(0) == nil)
+	EnableErrors()
+} //                                                   Test_crcy_Currency_Split_
+
 // -----------------------------------------------------------------------------
 // # Division:
 
@@ -569,6 +901,53 @@ func Test_crcy_Currency_Div_(t *testing.T) {
 	test(Currency{123456789}, arC(Currency{10000}), Currency{123456789})
 } //                                                     Test_crcy_Currency_Div_
 
+// go test --run Test_crcy_Currency_DivE_
+func Test_crcy_Currency_DivE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) DivE(nums ...Currency) (Currency, error)
+	//
+	test := func(n Currency, nums []Currency, expect Currency, expectErr string) {
+		ec := GetErrorCount()
+		got, err := n.DivE(nums...)
+		crcyErrTest(t, "DivE", n, nums, got, err, expect, expectErr)
+		TEqual(t, GetErrorCount(), ec) // must not log
+	}
+	test(cur(10), arC(4), cur(2.5), "")
+	test(cur(1), arC(3), cur("0.3333"), "")
+	test(cur(-1), arC(3), cur("-0.3333"), "")
+	test(cur(100), arC(2, 5), cur(10), "")
+	test(cur("900000000000000"), arC(2), cur("450000000000000"), "")
+	test(cur(1000000), arC("0.0001"), cur(10000000000), "")
+	//
+	// division by zero
+	test(cur(1), arC(0), Currency{math.MaxInt64}, "division by zero: 1 / 0")
+	test(cur(-1), arC(2, 0), Currency{math.MinInt64},
+		"division by zero: -0.5 / 0")
+	//
+	// overflow
+	test(cur("900000000000000"), arC("0.5"), Currency{math.MaxInt64},
+		"overflow: 900000000000000 / 0.5")
+	//
+	// overflow values are sticky
+	test(Currency{math.MaxInt64}, arC(-2), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	test(cur(1), arC(Currency{math.MinInt64}, 5), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	//
+	// Div(), DivFloat() and DivInt() log division by zero
+	DisableErrors()
+	curOpTest(t, "Div", cur(5).Div, cur(5), arC(0),
+		Currency{math.MaxInt64}, 1)
+	curFloatOpTest(t, "DivFloat", cur(-5).DivFloat, cur(-5), arF(0),
+		Currency{math.MinInt64}, 1)
+	curFloatOpTest(t, "DivFloat", cur(5).DivFloat, cur(5), arF(0.00001),
+		Currency{math.MaxInt64}, 1)
+	curIntOpTest(t, "DivInt", cur(5).DivInt, cur(5), arI(0),
+		Currency{math.MaxInt64}, 1)
+	EnableErrors()
+} //                                                    Test_crcy_Currency_DivE_
+
 // go test --run Test_crcy_Currency_DivFloat_
 func Test_crcy_Currency_DivFloat_(t *testing.T) {
 	TBegin(t)
@@ -585,6 +964,34 @@ func Test_crcy_Currency_DivFloat_(t *testing.T) {
 	test(Currency{123456789}, arF(2.0), Currency{61728394})
 } //                                                Test_crcy_Currency_DivFloat_
 
+// go test --run Test_crcy_Currency_DivFloatRound_
+func Test_crcy_Currency_DivFloatRound_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) DivFloatRound(mode RoundingMode, nums ...float64) Currency
+	//
+	test := func(n Currency, mode RoundingMode, nums []float64,
+		expect Currency, expectErrors int) {
+		curFloatOpTest(t, "DivFloatRound",
+			func(values ...float64) Currency {
+				return n.DivFloatRound(mode, values...)
+			}, n, nums, expect, expectErrors)
+	}
+	test(cur(10), RoundHalfEven, arF(3), cur("3.3333"), 0)
+	test(cur(20), RoundHalfEven, arF(3), cur("6.6667"), 0)
+	test(cur(20), RoundDown, arF(3), cur("6.6666"), 0)
+	test(cur(-20), RoundFloor, arF(3), cur("-6.6667"), 0)
+	test(cur("1.21"), RoundHalfEven, arF(1.1), cur("1.1"), 0)
+	test(cur(1), RoundHalfUp, arF(8, 2), cur("0.0625"), 0)
+	test(cur(1), RoundHalfEven, arF(16, 2), cur("0.0312"), 0)
+	test(cur(1), RoundHalfUp, arF(16, 2), cur("0.0313"), 0)
+	//
+	DisableErrors()
+	test(cur(1), RoundHalfUp, arF(0), Currency{math.MaxInt64}, 1)
+	test(cur(-1), RoundHalfUp, arF(0), Currency{math.MinInt64}, 1)
+	EnableErrors()
+} //                                           Test_crcy_Currency_DivFloatRound_
+
 // go test --run Test_crcy_Currency_DivInt_
 func Test_crcy_Currency_DivInt_(t *testing.T) {
 	// (n Currency) DivInt(nums...int) Currency
@@ -602,6 +1009,41 @@ func Test_crcy_Currency_DivInt_(t *testing.T) {
 	test(Currency{123456789}, arI(2), Currency{61728394})
 } //                                                  Test_crcy_Currency_DivInt_
 
+// go test --run Test_crcy_Currency_DivRound_
+func Test_crcy_Currency_DivRound_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) DivRound(mode RoundingMode, nums ...Currency) Currency
+	//
+	test := func(n Currency, mode RoundingMode, nums []Currency,
+		expect Currency, expectErrors int) {
+		curOpTest(t, "DivRound",
+			func(values ...Currency) Currency {
+				return n.DivRound(mode, values...)
+			}, n, nums, expect, expectErrors)
+	}
+	test(cur(10), RoundHalfEven, arC(3), cur("3.3333"), 0)
+	test(cur(20), RoundHalfEven, arC(3), cur("6.6667"), 0)
+	test(cur(20), RoundDown, arC(3), cur("6.6666"), 0)
+	test(cur(20), RoundUp, arC(3), cur("6.6667"), 0)
+	test(cur(-20), RoundCeiling, arC(3), cur("-6.6666"), 0)
+	test(cur(-20), RoundHalfUp, arC(3), cur("-6.6667"), 0)
+	test(cur("0.0001"), RoundHalfEven, arC(2), cur("0"), 0)
+	test(cur("0.0003"), RoundHalfEven, arC(2), cur("0.0002"), 0)
+	test(cur("0.0003"), RoundHalfDown, arC(2), cur("0.0001"), 0)
+	test(cur(100), RoundHalfUp, arC(3, 3), cur("11.1111"), 0)
+	//
+	// large dividend that would overflow when scaled
+	test(cur("900000000000000"), RoundHalfUp, arC(3),
+		cur("300000000000000"), 0)
+	//
+	DisableErrors()
+	test(cur("900000000000000"), RoundHalfUp, arC("0.5"),
+		Currency{math.MaxInt64}, 1)
+	test(cur(1), RoundHalfUp, arC(0), Currency{math.MaxInt64}, 1)
+	EnableErrors()
+} //                                                Test_crcy_Currency_DivRound_
+
 // -----------------------------------------------------------------------------
 // # Multiplication:
 
@@ -650,6 +1092,40 @@ func Test_crcy_Currency_Mul_(t *testing.T) {
 	EnableErrors()
 } //                                                     Test_crcy_Currency_Mul_
 
+// go test --run Test_crcy_Currency_MulE_
+func Test_crcy_Currency_MulE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MulE(nums ...Currency) (Currency, error)
+	//
+	test := func(n Currency, nums []Currency, expect Currency, expectErr string) {
+		ec := GetErrorCount()
+		got, err := n.MulE(nums...)
+		crcyErrTest(t, "MulE", n, nums, got, err, expect, expectErr)
+		TEqual(t, GetErrorCount(), ec) // must not log
+	}
+	test(cur(0), arC(123), cur(0), "")
+	test(cur(2), arC("2.5"), cur(5), "")
+	test(cur(-2), arC("2.5", 2), cur(-10), "")
+	test(cur("0.0005"), arC("0.5"), cur("0.0002"), "") // truncated
+	test(Currency{4611686018427384999}, arC(2),
+		Currency{9223372036854769998}, "")
+	//
+	// overflow
+	test(cur(int64(922337203685476)), arC(2), Currency{math.MaxInt64},
+		"overflow: 922337203685476 * 2")
+	test(cur(int64(-922337203685476)), arC(2), Currency{math.MinInt64},
+		"overflow: -922337203685476 * 2")
+	//
+	// overflow values are sticky, even when multiplied by zero
+	test(Currency{math.MaxInt64}, arC(0), Currency{math.MaxInt64},
+		"overflow: operand is an overflow value")
+	test(Currency{math.MaxInt64}, arC(-1), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	test(cur(-3), arC(Currency{math.MinInt64}), Currency{math.MaxInt64},
+		"overflow: operand is an overflow value")
+} //                                                    Test_crcy_Currency_MulE_
+
 // go test --run Test_crcy_Currency_MulFloat_
 func Test_crcy_Currency_MulFloat_(t *testing.T) {
 	TBegin(t)
@@ -699,6 +1175,35 @@ func Test_crcy_Currency_MulFloat_(t *testing.T) {
 	EnableErrors()
 } //                                                Test_crcy_Currency_MulFloat_
 
+// go test --run Test_crcy_Currency_MulFloatRound_
+func Test_crcy_Currency_MulFloatRound_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MulFloatRound(mode RoundingMode, nums ...float64) Currency
+	//
+	test := func(n Currency, mode RoundingMode, nums []float64,
+		expect Currency, expectErrors int) {
+		curFloatOpTest(t, "MulFloatRound",
+			func(values ...float64) Currency {
+				return n.MulFloatRound(mode, values...)
+			}, n, nums, expect, expectErrors)
+	}
+	test(cur("1.15"), RoundHalfEven, arF(1.1), cur("1.265"), 0)
+	test(cur("0.0005"), RoundHalfEven, arF(0.5), cur("0.0002"), 0)
+	test(cur("0.0005"), RoundHalfUp, arF(0.5), cur("0.0003"), 0)
+	test(cur("0.0005"), RoundHalfDown, arF(0.5), cur("0.0002"), 0)
+	test(cur("-0.0005"), RoundHalfUp, arF(0.5), cur("-0.0003"), 0)
+	test(cur(3), RoundDown, arF(0.1, 0.1), cur("0.03"), 0)
+	test(cur(1), RoundCeiling, arF(0.00001), cur("0.0001"), 0)
+	test(cur(1), RoundFloor, arF(-0.00001), cur("-0.0001"), 0)
+	//
+	DisableErrors()
+	test(cur("900000000000000"), RoundHalfEven, arF(2),
+		Currency{math.MaxInt64}, 1)
+	test(cur(1), RoundHalfEven, arF(math.Inf(1)), Currency{math.MaxInt64}, 1)
+	EnableErrors()
+} //                                           Test_crcy_Currency_MulFloatRound_
+
 // go test --run Test_crcy_Currency_MulInt_
 func Test_crcy_Currency_MulInt_(t *testing.T) {
 	// (n Currency) MulInt(nums...int) Currency
@@ -721,6 +1226,37 @@ func Test_crcy_Currency_MulInt_(t *testing.T) {
 	//       pass MaxInt64 * just above currency limit.
 } //                                                  Test_crcy_Currency_MulInt_
 
+// go test --run Test_crcy_Currency_MulRound_
+func Test_crcy_Currency_MulRound_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MulRound(mode RoundingMode, nums ...Currency) Currency
+	//
+	test := func(n Currency, mode RoundingMode, nums []Currency,
+		expect Currency, expectErrors int) {
+		curOpTest(t, "MulRound",
+			func(values ...Currency) Currency {
+				return n.MulRound(mode, values...)
+			}, n, nums, expect, expectErrors)
+	}
+	test(cur("0.0005"), RoundHalfEven, arC("0.5"), cur("0.0002"), 0)
+	test(cur("0.0007"), RoundHalfEven, arC("0.5"), cur("0.0004"), 0)
+	test(cur("0.0005"), RoundHalfUp, arC("0.5"), cur("0.0003"), 0)
+	test(cur("0.0005"), RoundDown, arC("0.5"), cur("0.0002"), 0)
+	test(cur("-0.0005"), RoundFloor, arC("0.5"), cur("-0.0003"), 0)
+	test(cur("-0.0005"), RoundCeiling, arC("0.5"), cur("-0.0002"), 0)
+	test(cur("19.99"), RoundHalfUp, arC("0.0725"), cur("1.4493"), 0)
+	test(cur("19.99"), RoundHalfUp, arC("0.0725", 3), cur("4.3479"), 0)
+	test(cur("0"), RoundUp, arC("123.4567"), cur("0"), 0)
+	//
+	DisableErrors()
+	test(cur("900000000000000"), RoundHalfEven, arC(2),
+		Currency{math.MaxInt64}, 1)
+	test(cur("-900000000000000"), RoundHalfEven, arC(2),
+		Currency{math.MinInt64}, 1)
+	EnableErrors()
+} //                                                Test_crcy_Currency_MulRound_
+
 // -----------------------------------------------------------------------------
 // # Addition:
 
@@ -831,6 +1367,48 @@ func Test_crcy_Currency_Add_(t *testing.T) {
 	EnableErrors()
 } //                                                     Test_crcy_Currency_Add_
 
+// go test --run Test_crcy_Currency_AddE_
+func Test_crcy_Currency_AddE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) AddE(nums ...Currency) (Currency, error)
+	//
+	test := func(n Currency, nums []Currency, expect Currency, expectErr string) {
+		ec := GetErrorCount()
+		got, err := n.AddE(nums...)
+		crcyErrTest(t, "AddE", n, nums, got, err, expect, expectErr)
+		TEqual(t, GetErrorCount(), ec) // must not log
+	}
+	test(cur(0), nil, cur(0), "")
+	test(cur(1), arC(2, "3.5"), cur("6.5"), "")
+	test(Currency{MaxCurrencyI64 - 1}, arC(Currency{1}),
+		Currency{MaxCurrencyI64}, "")
+	test(Currency{MinCurrencyI64}, arC(Currency{MaxCurrencyI64}), cur(0), "")
+	//
+	// overflow
+	test(Currency{MaxCurrencyI64}, arC(Currency{1}), Currency{math.MaxInt64},
+		"overflow: 922337203685476.9999 + 0.0001")
+	test(Currency{MinCurrencyI64}, arC(Currency{MinCurrencyI64}),
+		Currency{math.MinInt64},
+		"overflow: -922337203685476.9999 + -922337203685476.9999")
+	//
+	// overflow values are sticky
+	test(Currency{math.MaxInt64}, arC(-1), Currency{math.MaxInt64},
+		"overflow: operand is an overflow value")
+	test(cur(1), arC(Currency{math.MinInt64}, 5), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	//
+	// AddFloat() and AddInt() detect overflows
+	DisableErrors()
+	curFloatOpTest(t, "AddFloat", cur(1).AddFloat, cur(1), arF(math.NaN()),
+		Currency{math.MaxInt64}, 1)
+	curFloatOpTest(t, "AddFloat", cur(1).AddFloat, cur(1), arF(-1e30),
+		Currency{math.MinInt64}, 1)
+	curIntOpTest(t, "AddInt", cur(1).AddInt, cur(1),
+		arI(CurrencyIntLimit), Currency{math.MaxInt64}, 1)
+	EnableErrors()
+} //                                                    Test_crcy_Currency_AddE_
+
 // go test --run Test_crcy_Currency_AddFloat_
 func Test_crcy_Currency_AddFloat_(t *testing.T) {
 	TBegin(t)
@@ -979,6 +1557,43 @@ func Test_crcy_Currency_Sub_(t *testing.T) {
 	EnableErrors()
 } //                                                     Test_crcy_Currency_Sub_
 
+// go test --run Test_crcy_Currency_SubE_
+func Test_crcy_Currency_SubE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) SubE(nums ...Currency) (Currency, error)
+	//
+	test := func(n Currency, nums []Currency, expect Currency, expectErr string) {
+		ec := GetErrorCount()
+		got, err := n.SubE(nums...)
+		crcyErrTest(t, "SubE", n, nums, got, err, expect, expectErr)
+		TEqual(t, GetErrorCount(), ec) // must not log
+	}
+	test(cur(10), arC(2, "3.5"), cur("4.5"), "")
+	test(cur(0), arC(Currency{MaxCurrencyI64}), Currency{MinCurrencyI64}, "")
+	//
+	// overflow
+	test(Currency{MinCurrencyI64}, arC(Currency{1}), Currency{math.MinInt64},
+		"overflow: -922337203685476.9999 + -0.0001")
+	test(Currency{MaxCurrencyI64}, arC(Currency{MinCurrencyI64}),
+		Currency{math.MaxInt64},
+		"overflow: 922337203685476.9999 + 922337203685476.9999")
+	//
+	// overflow values are sticky
+	test(Currency{math.MinInt64}, arC(-1), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	test(cur(1), arC(Currency{math.MaxInt64}), Currency{math.MinInt64},
+		"overflow: operand is an overflow value")
+	//
+	// SubFloat() and SubInt() detect overflows
+	DisableErrors()
+	curFloatOpTest(t, "SubFloat", cur(-1).SubFloat, cur(-1), arF(1e30),
+		Currency{math.MinInt64}, 1)
+	curIntOpTest(t, "SubInt", Currency{MinCurrencyI64}.SubInt,
+		Currency{MinCurrencyI64}, arI(1), Currency{math.MinInt64}, 1)
+	EnableErrors()
+} //                                                    Test_crcy_Currency_SubE_
+
 // go test --run Test_crcy_Currency_SubFloat_
 func Test_crcy_Currency_SubFloat_(t *testing.T) {
 	TBegin(t)
@@ -1119,6 +1734,32 @@ func Test_crcy_Currency_IsNegative_(t *testing.T) {
 	// TODO: more unit test cases
 } //                                              Test_crcy_Currency_IsNegative_
 
+// go test --run Test_crcy_Currency_IsOverflow_
+func Test_crcy_Currency_IsOverflow_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) IsOverflow() bool
+	//
+	TFalse(t, cur(0).IsOverflow())
+	TFalse(t, Currency{MaxCurrencyI64}.IsOverflow())
+	TFalse(t, Currency{MinCurrencyI64}.IsOverflow())
+	TTrue(t, Currency{MaxCurrencyI64 + 1}.IsOverflow())
+	TTrue(t, Currency{math.MinInt64}.IsOverflow())
+	//
+	// an overflow stays an overflow through any further arithmetic
+	DisableErrors()
+	n := cur(int64(900000000000000)).Add(cur(int64(900000000000000)))
+	TTrue(t, n.IsOverflow())
+	n = n.Sub(cur(int64(900000000000000))).Div(cur(1000)).MulInt(0).
+		AddFloat(1).SubInt(5).DivInt(2).MulFloat(0.5).
+		MulRound(RoundHalfEven, cur(0)).DivRound(RoundHalfUp, cur(3)).
+		MulFloatRound(RoundUp, 0.1).DivFloatRound(RoundDown, 0.1).
+		Round(0, RoundHalfEven)
+	TTrue(t, n.IsOverflow())
+	EnableErrors()
+	TEqual(t, n, Currency{math.MaxInt64})
+} //                                              Test_crcy_Currency_IsOverflow_
+
 // go test --run Test_crcy_Currency_IsZero_
 func Test_crcy_Currency_IsZero_(t *testing.T) {
 	TBegin(t)
@@ -1254,6 +1895,49 @@ func Test_crcy_Currency_UnmarshalJSON_(t *testing.T) {
 			TFail(t, err)
 		}
 	}
+	// exact decimals, strings, null and rounding
+	{
+		test := func(input string, expect int64) {
+			var num Currency
+			err := num.UnmarshalJSON([]byte(input))
+			if err != nil || num.i64 != expect {
+				TFail(t, `UnmarshalJSON(`, input, `) returned `, num.i64,
+					`, `, err, `. must be `, expect)
+			}
+		}
+		test(`0.29`, 2900)
+		test(`"0.29"`, 2900)
+		test(`-1234.5678`, -12345678)
+		test(`900719925474.0993`, 9007199254740993)
+		test(`1.5e2`, 1500000)
+		test(`null`, 0)
+		test(`1.23456`, 12345)
+		//
+		defer SetCurrencyRounding(CurrencyRounding())
+		SetCurrencyRounding(RoundHalfUp)
+		test(`1.23456`, 12346)
+	}
+	{
+		var v struct {
+			A Currency
+			B Currency
+		}
+		err := json.Unmarshal([]byte(`{"A": "12.34", "B": 0.1}`), &v)
+		TTrue(t, err == nil)
+		TEqual(t, v.A, cur("12.34"))
+		TEqual(t, v.B, cur("0.1"))
+	}
+	// errors for invalid or too large numbers
+	{
+		var num Currency
+		err := num.UnmarshalJSON([]byte(`"abc"`))
+		TTrue(t, err != nil)
+		err = num.UnmarshalJSON([]byte(`1e300`))
+		TTrue(t, err != nil && strings.HasPrefix(err.Error(), EOverflow))
+		TEqual(t, num, Currency{0})
+		err = num.UnmarshalJSON([]byte(`true`))
+		TTrue(t, err != nil)
+	}
 	// error with nil receiver
 	{
 		DisableErrors()
@@ -1292,6 +1976,121 @@ func Test_crcy_Currency_UnmarshalJSON_(t *testing.T) {
 	}
 } //                                           Test_crcy_Currency_UnmarshalJSON_
 
+// -----------------------------------------------------------------------------
+// # Text, Binary and XML Encoding:
+
+// go test --run Test_crcy_Currency_MarshalBinary_
+func Test_crcy_Currency_MarshalBinary_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MarshalBinary() ([]byte, error)
+	// (n *Currency) UnmarshalBinary(data []byte) error
+	//
+	test := func(n Currency, expectSize int) {
+		data, err := n.MarshalBinary()
+		TTrue(t, err == nil)
+		TEqual(t, len(data), expectSize)
+		var back Currency
+		TTrue(t, back.UnmarshalBinary(data) == nil)
+		TEqual(t, back.i64, n.i64)
+	}
+	test(cur(0), 1)
+	test(Currency{-1}, 1)
+	test(cur("0.0063"), 1)
+	test(cur("1.5"), 3)
+	test(cur("-1234.5678"), 4)
+	test(Currency{MaxCurrencyI64}, 10)
+	test(Currency{MinCurrencyI64}, 10)
+	//
+	// invalid data
+	{
+		var n Currency
+		TTrue(t, n.UnmarshalBinary(nil) != nil)
+		TTrue(t, n.UnmarshalBinary([]byte{0x80}) != nil)
+		TTrue(t, n.UnmarshalBinary([]byte{2, 2}) != nil)
+		var p *Currency
+		TTrue(t, p.UnmarshalBinary([]byte{2}) != nil)
+	}
+	// encoding/gob uses the binary marshaler
+	{
+		type T struct {
+			A Currency
+			B []Currency
+		}
+		var (
+			buf  bytes.Buffer
+			in   = T{A: cur("12.3456"), B: arC(-1, "0.01")}
+			back T
+		)
+		TTrue(t, gob.NewEncoder(&buf).Encode(in) == nil)
+		TTrue(t, gob.NewDecoder(&buf).Decode(&back) == nil)
+		TEqual(t, back.A, in.A)
+		TEqual(t, crcyJoin(back.B), "-1 0.01")
+	}
+} //                                           Test_crcy_Currency_MarshalBinary_
+
+// go test --run Test_crcy_Currency_MarshalText_
+func Test_crcy_Currency_MarshalText_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MarshalText() ([]byte, error)
+	// (n *Currency) UnmarshalText(text []byte) error
+	//
+	for _, s := range []string{"0", "1", "-1", "0.0001", "-0.5", "1234.5678"} {
+		data, err := cur(s).MarshalText()
+		TTrue(t, err == nil)
+		TEqual(t, string(data), s)
+		TEqual(t, string(data), cur(s).String())
+		//
+		var back Currency
+		TTrue(t, back.UnmarshalText(data) == nil)
+		TEqual(t, back, cur(s))
+	}
+	{
+		var n Currency
+		TTrue(t, n.UnmarshalText([]byte("12a")) != nil)
+		var p *Currency
+		TTrue(t, p.UnmarshalText([]byte("1")) != nil)
+	}
+	// Currency can be used as a JSON map key
+	{
+		m := map[Currency]string{cur("1.5"): "a", cur(-2): "b"}
+		data, err := json.Marshal(m)
+		TTrue(t, err == nil)
+		TEqual(t, string(data), `{"-2":"b","1.5":"a"}`)
+		//
+		var back map[Currency]string
+		TTrue(t, json.Unmarshal(data, &back) == nil)
+		TEqual(t, back[cur("1.5")], "a")
+		TEqual(t, back[cur(-2)], "b")
+	}
+} //                                             Test_crcy_Currency_MarshalText_
+
+// go test --run Test_crcy_Currency_MarshalXML_
+func Test_crcy_Currency_MarshalXML_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) MarshalXML(enc *xml.Encoder, start xml.StartElement) error
+	// (n *Currency) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error
+	//
+	type Item struct {
+		Price Currency `xml:"price"`
+		Tax   Currency `xml:"tax,attr"`
+	}
+	in := Item{Price: cur("1234.5"), Tax: cur("-0.07")}
+	data, err := xml.Marshal(in)
+	TTrue(t, err == nil)
+	TEqual(t, string(data), `<Item tax="-0.07"><price>1234.5</price></Item>`)
+	//
+	var back Item
+	TTrue(t, xml.Unmarshal(data, &back) == nil)
+	TEqual(t, back.Price, in.Price)
+	TEqual(t, back.Tax, in.Tax)
+	//
+	err = xml.Unmarshal([]byte(`<Item><price>x</price></Item>`), &back)
+	TTrue(t, err != nil)
+} //                                              Test_crcy_Currency_MarshalXML_
+
 // -----------------------------------------------------------------------------
 // # Helper Functions
 
@@ -1364,6 +2163,47 @@ func arI(ar ...interface{}) (ret []int) {
 
 var cur = CurrencyOf // a short CurrencyOf() alias used in many unit tests here
 
+// crcyErrTest checks the result of an arithmetic method that returns an
+// error, such as AddE(). expectErr is the expected error message, or
+// a blank string if no error is expected.
+func crcyErrTest(
+	t *testing.T,
+	opName string,
+	n Currency,
+	values []Currency,
+	got Currency,
+	err error,
+	expect Currency,
+	expectErr string,
+) {
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if got.i64 != expect.i64 || errMsg != expectErr {
+		TFailf(t, `(%v).%s(%v) returned %v, %q. must be %v, %q`,
+			n, opName, values, got, errMsg, expect, expectErr)
+	}
+} //                                                                 crcyErrTest
+
+// crcyJoin returns currency values as a space-delimited string.
+func crcyJoin(values []Currency) string {
+	ar := make([]string, len(values))
+	for i, n := range values {
+		ar[i] = n.String()
+	}
+	return strings.Join(ar, " ")
+} //                                                                    crcyJoin
+
+// crcySum adds up currency values (used to check allocations).
+func crcySum(values []Currency) Currency {
+	var ret Currency
+	for _, n := range values {
+		ret = ret.Add(n)
+	}
+	return ret
+} //                                                                     crcySum
+
 // curFloatOpTest tests basic arithmetic
 // operations with Currency and float64.
 func curFloatOpTest(
@@ -0,0 +1,239 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                             zr/[currency_total.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Aggregate Functions:
+//   CurrencyAvg(values []Currency, mode RoundingMode) (Currency, bool)
+//   CurrencyMax(values []Currency) (max Currency, found bool)
+//   CurrencyMin(values []Currency) (min Currency, found bool)
+//   CurrencySum(values []Currency) Currency
+//   CurrencySumE(values []Currency) (Currency, error)
+//
+// # CurrencyTotal Type:
+//   CurrencyTotal struct
+//
+// # Methods (ob *CurrencyTotal)
+//   ) Add(values ...Currency)
+//   ) Avg(mode RoundingMode) Currency
+//   ) Count() int
+//   ) Err() error
+//   ) IsOverflow() bool
+//   ) Merge(other *CurrencyTotal)
+//   ) Reset()
+//   ) Sum() Currency
+//
+// # Private Methods (ob *CurrencyTotal)
+//   ) add(sum Currency, count int, err error, values ...Currency)
+//   ) get() (sum Currency, count int, err error)
+
+import (
+	"math/big"
+	"sync"
+)
+
+// -----------------------------------------------------------------------------
+// # Aggregate Functions:
+
+// CurrencyAvg returns the average of the specified values, rounded to
+// 4 decimal places using the given rounding mode. The average is exact
+// even when the sum of the values is too big to be stored in Currency.
+//
+// Returns zero and false if values is empty. If any value is an
+// overflow value, logs an error and returns an overflow value.
+func CurrencyAvg(values []Currency, mode RoundingMode) (Currency, bool) {
+	if len(values) == 0 {
+		return Currency{0}, false
+	}
+	var (
+		sum = new(big.Int)
+		x   = new(big.Int)
+	)
+	for _, n := range values {
+		if n.IsOverflow() {
+			currencyLogError(errCurrencyOverflowOperand)
+			return n, true
+		}
+		sum.Add(sum, x.SetInt64(n.i64))
+	}
+	ret := roundQuo(sum, x.SetInt64(int64(len(values))), mode)
+	return Currency{ret.Int64()}, true
+} //                                                                 CurrencyAvg
+
+// CurrencyMax returns the highest of the specified values.
+// Returns zero and false if values is empty.
+func CurrencyMax(values []Currency) (max Currency, found bool) {
+	for _, n := range values {
+		if n.i64 > max.i64 || !found {
+			max = n
+			found = true
+		}
+	}
+	return max, found
+} //                                                                 CurrencyMax
+
+// CurrencyMin returns the lowest of the specified values.
+// Returns zero and false if values is empty.
+func CurrencyMin(values []Currency) (min Currency, found bool) {
+	for _, n := range values {
+		if n.i64 < min.i64 || !found {
+			min = n
+			found = true
+		}
+	}
+	return min, found
+} //                                                                 CurrencyMin
+
+// CurrencySum returns the sum of the specified values.
+// Returns zero if values is empty.
+//
+// If the sum overflows, or any value is an overflow value, logs an
+// error and returns math.MinInt64 or math.MaxInt64 depending on if
+// the result is negative. See CurrencySumE() to get an error instead.
+func CurrencySum(values []Currency) Currency {
+	return Currency{0}.Add(values...)
+} //                                                                 CurrencySum
+
+// CurrencySumE returns the sum of the specified values, or an
+// overflow value and an error if the sum doesn't fit in Currency
+// or if any value is an overflow value. Does not log errors.
+func CurrencySumE(values []Currency) (Currency, error) {
+	return Currency{0}.AddE(values...)
+} //                                                                CurrencySumE
+
+// -----------------------------------------------------------------------------
+// # CurrencyTotal Type:
+
+// CurrencyTotal accumulates a running total and count of currency
+// values. It is safe for concurrent use, so several goroutines
+// can add to the same total, or each goroutine can keep its own
+// total and then Merge() it into a grand total.
+//
+// The zero value is an empty total ready to use. Overflows are not
+// logged: once the total overflows, it keeps the overflow value,
+// IsOverflow() returns true and Err() returns the error.
+type CurrencyTotal struct {
+	mu    sync.Mutex
+	sum   Currency
+	count int
+	err   error
+} //                                                               CurrencyTotal
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *CurrencyTotal)
+
+// Add adds one or more values to the total.
+func (ob *CurrencyTotal) Add(values ...Currency) {
+	if ob == nil {
+		mod.Error(ENilReceiver)
+		return
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.add(Currency{0}, 0, nil, values...)
+} //                                                                         Add
+
+// Avg returns the average of the values added to the total, rounded to
+// 4 decimal places using the given rounding mode. Returns zero if no
+// values were added, or the overflow value if the total overflowed.
+func (ob *CurrencyTotal) Avg(mode RoundingMode) Currency {
+	sum, count, _ := ob.get()
+	if count == 0 || sum.IsOverflow() {
+		return sum
+	}
+	return Currency{roundQuoInt64(sum.i64, int64(count), mode)}
+} //                                                                         Avg
+
+// Count returns the number of values added to the total.
+func (ob *CurrencyTotal) Count() int {
+	_, count, _ := ob.get()
+	return count
+} //                                                                       Count
+
+// Err returns the error that occurred when the total
+// overflowed, or nil if there was no overflow.
+func (ob *CurrencyTotal) Err() error {
+	_, _, err := ob.get()
+	return err
+} //                                                                         Err
+
+// IsOverflow returns true if the total overflowed.
+func (ob *CurrencyTotal) IsOverflow() bool {
+	sum, _, _ := ob.get()
+	return sum.IsOverflow()
+} //                                                                  IsOverflow
+
+// Merge adds the sum and count of another total to this total.
+// The other total isn't changed. Merging a total into itself
+// doubles its sum and count.
+func (ob *CurrencyTotal) Merge(other *CurrencyTotal) {
+	if ob == nil {
+		mod.Error(ENilReceiver)
+		return
+	}
+	if other == nil {
+		return
+	}
+	// read the other total before locking this one,
+	// so that two totals can be merged into each other
+	// at the same time without a deadlock
+	sum, count, err := other.get()
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.add(sum, count, err)
+} //                                                                       Merge
+
+// Reset clears the total, so it can be reused.
+func (ob *CurrencyTotal) Reset() {
+	if ob == nil {
+		mod.Error(ENilReceiver)
+		return
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.sum, ob.count, ob.err = Currency{0}, 0, nil
+} //                                                                       Reset
+
+// Sum returns the sum of the values added to the
+// total, or an overflow value if it overflowed.
+func (ob *CurrencyTotal) Sum() Currency {
+	sum, _, _ := ob.get()
+	return sum
+} //                                                                         Sum
+
+// -----------------------------------------------------------------------------
+// # Private Methods (ob *CurrencyTotal)
+
+// add adds a partial sum and count, and the given values, to the total.
+// err is the partial sum's overflow error. ob.mu must be locked.
+func (ob *CurrencyTotal) add(
+	sum Currency, count int, err error, values ...Currency,
+) {
+	ob.count += count + len(values)
+	if ob.err != nil {
+		return
+	}
+	if err != nil {
+		ob.sum, ob.err = sum, err
+		return
+	}
+	ob.sum, ob.err = ob.sum.AddE(sum)
+	if ob.err == nil {
+		ob.sum, ob.err = ob.sum.AddE(values...)
+	}
+} //                                                                         add
+
+// get returns the total's sum, count and error.
+// Returns zero values if ob is nil.
+func (ob *CurrencyTotal) get() (sum Currency, count int, err error) {
+	if ob == nil {
+		return Currency{0}, 0, nil
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	return ob.sum, ob.count, ob.err
+} //                                                                         get
+
+// end
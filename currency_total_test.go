@@ -0,0 +1,256 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                        zr/[currency_total_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Aggregate Functions:
+//   Test_ctot_CurrencyAvg_
+//   Test_ctot_CurrencyMax_
+//   Test_ctot_CurrencyMin_
+//   Test_ctot_CurrencySum_
+//
+// # Methods (ob *CurrencyTotal)
+//   Test_ctot_CurrencyTotal_
+//   Test_ctot_CurrencyTotal_Merge_
+
+//  to test all items in currency_total.go use:
+//      go test --run Test_ctot_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Aggregate Functions:
+
+// go test --run Test_ctot_CurrencyAvg_
+func Test_ctot_CurrencyAvg_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyAvg(values []Currency, mode RoundingMode) (Currency, bool)
+	//
+	test := func(values []Currency, mode RoundingMode, expect Currency) {
+		got, found := CurrencyAvg(values, mode)
+		if !found || got != expect {
+			TFailf(t, `CurrencyAvg(%v, %v) returned %v, %v instead of %v`,
+				values, mode, got, found, expect)
+		}
+	}
+	test(arC(5), RoundHalfEven, cur(5))
+	test(arC(1, 2), RoundHalfEven, cur(1.5))
+	test(arC(1, 1, 2), RoundHalfEven, cur("1.3333"))
+	test(arC(1, 2, 2), RoundHalfEven, cur("1.6667"))
+	test(arC(1, 2, 2), RoundDown, cur("1.6666"))
+	test(arC("0.0001", "0.0002"), RoundHalfEven, cur("0.0002"))
+	test(arC("0.0001", "0.0002"), RoundHalfDown, cur("0.0001"))
+	test(arC(-1, -2, -2), RoundFloor, cur("-1.6667"))
+	//
+	// the sum of these values overflows, but the average doesn't
+	test(arC(Currency{MaxCurrencyI64}, Currency{MaxCurrencyI64 - 2}),
+		RoundHalfEven, Currency{MaxCurrencyI64 - 1})
+	//
+	got, found := CurrencyAvg(nil, RoundHalfEven)
+	TEqual(t, got, cur(0))
+	TFalse(t, found)
+	//
+	DisableErrors()
+	got, found = CurrencyAvg(arC(1, Currency{math.MinInt64}), RoundHalfEven)
+	EnableErrors()
+	TTrue(t, got.IsOverflow())
+	TTrue(t, found)
+} //                                                      Test_ctot_CurrencyAvg_
+
+// go test --run Test_ctot_CurrencyMax_
+func Test_ctot_CurrencyMax_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyMax(values []Currency) (max Currency, found bool)
+	//
+	max, found := CurrencyMax(arC(-3, "-1.5", -2))
+	TEqual(t, max, cur(-1.5))
+	TTrue(t, found)
+	//
+	max, found = CurrencyMax(arC(1, "7.0001", 7))
+	TEqual(t, max, cur("7.0001"))
+	TTrue(t, found)
+	//
+	max, found = CurrencyMax(nil)
+	TEqual(t, max, cur(0))
+	TFalse(t, found)
+} //                                                      Test_ctot_CurrencyMax_
+
+// go test --run Test_ctot_CurrencyMin_
+func Test_ctot_CurrencyMin_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyMin(values []Currency) (min Currency, found bool)
+	//
+	min, found := CurrencyMin(arC(3, "1.5", 2))
+	TEqual(t, min, cur(1.5))
+	TTrue(t, found)
+	//
+	min, found = CurrencyMin(arC(1, "-7.0001", -7))
+	TEqual(t, min, cur("-7.0001"))
+	TTrue(t, found)
+	//
+	min, found = CurrencyMin([]Currency{})
+	TEqual(t, min, cur(0))
+	TFalse(t, found)
+} //                                                      Test_ctot_CurrencyMin_
+
+// go test --run Test_ctot_CurrencySum_
+func Test_ctot_CurrencySum_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencySum(values []Currency) Currency
+	// CurrencySumE(values []Currency) (Currency, error)
+	//
+	TEqual(t, CurrencySum(nil), cur(0))
+	TEqual(t, CurrencySum(arC("0.1", "0.2", "-0.3")), cur(0))
+	TEqual(t, CurrencySum(arC(1, 2, "3.0001")), cur("6.0001"))
+	{
+		got, err := CurrencySumE(arC(1, 2))
+		TEqual(t, got, cur(3))
+		TTrue(t, err == nil)
+	}
+	{
+		ec := GetErrorCount()
+		got, err := CurrencySumE(
+			arC(Currency{MaxCurrencyI64}, Currency{MaxCurrencyI64}))
+		TEqual(t, got, Currency{math.MaxInt64})
+		TTrue(t, err != nil)
+		TEqual(t, GetErrorCount(), ec)
+	}
+	{
+		DisableErrors()
+		ec := GetErrorCount()
+		got := CurrencySum(
+			arC(Currency{MinCurrencyI64}, Currency{MinCurrencyI64}))
+		TEqual(t, GetErrorCount(), ec+1)
+		EnableErrors()
+		TEqual(t, got, Currency{math.MinInt64})
+	}
+} //                                                      Test_ctot_CurrencySum_
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *CurrencyTotal)
+
+// go test --run Test_ctot_CurrencyTotal_
+func Test_ctot_CurrencyTotal_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *CurrencyTotal) Add(values ...Currency)
+	// (ob *CurrencyTotal) Avg(mode RoundingMode) Currency
+	// (ob *CurrencyTotal) Count() int
+	// (ob *CurrencyTotal) Err() error
+	// (ob *CurrencyTotal) IsOverflow() bool
+	// (ob *CurrencyTotal) Reset()
+	// (ob *CurrencyTotal) Sum() Currency
+	//
+	var tot CurrencyTotal
+	TEqual(t, tot.Sum(), cur(0))
+	TEqual(t, tot.Count(), 0)
+	TEqual(t, tot.Avg(RoundHalfEven), cur(0))
+	//
+	tot.Add(cur(1))
+	tot.Add(cur(2), cur(2))
+	TEqual(t, tot.Sum(), cur(5))
+	TEqual(t, tot.Count(), 3)
+	TEqual(t, tot.Avg(RoundHalfEven), cur("1.6667"))
+	TEqual(t, tot.Avg(RoundDown), cur("1.6666"))
+	TFalse(t, tot.IsOverflow())
+	TTrue(t, tot.Err() == nil)
+	//
+	// overflows are kept, without logging
+	ec := GetErrorCount()
+	tot.Add(Currency{MaxCurrencyI64})
+	TTrue(t, tot.IsOverflow())
+	TTrue(t, tot.Err() != nil)
+	tot.Add(Currency{MinCurrencyI64})
+	TTrue(t, tot.IsOverflow())
+	TEqual(t, tot.Sum(), Currency{math.MaxInt64})
+	TEqual(t, tot.Avg(RoundHalfEven), Currency{math.MaxInt64})
+	TEqual(t, tot.Count(), 5)
+	TEqual(t, GetErrorCount(), ec)
+	//
+	tot.Reset()
+	TEqual(t, tot.Sum(), cur(0))
+	TEqual(t, tot.Count(), 0)
+	TFalse(t, tot.IsOverflow())
+	TTrue(t, tot.Err() == nil)
+	//
+	// a nil total reads as empty
+	var nilTot *CurrencyTotal
+	TEqual(t, nilTot.Sum(), cur(0))
+	TEqual(t, nilTot.Count(), 0)
+} //                                                    Test_ctot_CurrencyTotal_
+
+// go test --run Test_ctot_CurrencyTotal_Merge_
+func Test_ctot_CurrencyTotal_Merge_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *CurrencyTotal) Merge(other *CurrencyTotal)
+	//
+	// each goroutine keeps its own total, merged into a grand total
+	var (
+		grand CurrencyTotal
+		wg    sync.WaitGroup
+	)
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var tot CurrencyTotal
+			for j := 0; j < 100; j++ {
+				tot.Add(Currency{int64(i)})
+			}
+			grand.Merge(&tot)
+		}(i)
+	}
+	// goroutines adding to the same total
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			grand.Add(cur(1))
+		}()
+	}
+	wg.Wait()
+	TEqual(t, grand.Count(), 1010)
+	TEqual(t, grand.Sum(), cur("10.55")) // 5500 * 0.0001 + 10
+	//
+	// merging into itself doubles the total
+	grand.Merge(&grand)
+	TEqual(t, grand.Count(), 2020)
+	TEqual(t, grand.Sum(), cur("21.1"))
+	//
+	grand.Merge(nil)
+	TEqual(t, grand.Count(), 2020)
+	//
+	// overflow of the merged total is kept
+	{
+		var a, b CurrencyTotal
+		a.Add(Currency{MaxCurrencyI64})
+		b.Add(Currency{MaxCurrencyI64})
+		a.Merge(&b)
+		TTrue(t, a.IsOverflow())
+		TTrue(t, a.Err() != nil)
+		TEqual(t, a.Count(), 2)
+		//
+		var c CurrencyTotal
+		c.Add(cur(1))
+		c.Merge(&a)
+		TTrue(t, c.IsOverflow())
+		TEqual(t, c.Count(), 3)
+	}
+} //                                              Test_ctot_CurrencyTotal_Merge_
+
+// end
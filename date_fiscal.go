@@ -0,0 +1,348 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                zr/[date_fiscal.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Fiscal Calendar Types
+//   FiscalPattern int
+//   FiscalCalendar struct
+//   DateFiscalCalendar FiscalCalendar
+//
+// # Methods (ob FiscalCalendar)
+//   ) FiscalPeriod(date time.Time) int
+//   ) FiscalQuarter(date time.Time) int
+//   ) FiscalYear(date time.Time) int
+//   ) PeriodRange(year, period int) DateRange
+//   ) QuarterRange(year, quarter int) DateRange
+//   ) YearRange(year int) DateRange
+//
+// # ISO Week Functions
+//   ISOWeekRange(year, week int) DateRange
+//   WeeksInYear(year int) int
+//
+// # Private Methods/Functions
+//   (ob FiscalCalendar) find(date time.Time) (year, period int)
+//   (ob FiscalCalendar) periods(year int) []DateRange
+//   (ob FiscalCalendar) yearEnd(year int, start time.Month) time.Time
+//   dateRangeFiscal(s string, loc *time.Location) (DateRange, bool)
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	// dateFiscalYearEx matches fiscal years and quarters in DateRangeOf
+	// after pre-formatting, e.g. "FY2024", "FY-24" or "FY2024-Q1".
+	dateFiscalYearEx = regexp.MustCompile(`^FY-?(\d{4}|\d{2})(?:-?Q([1-4]))?$`)
+
+	// dateISOWeekEx matches ISO weeks in DateRangeOf
+	// after pre-formatting, e.g. "2024-W07" or "2024W07".
+	dateISOWeekEx = regexp.MustCompile(`^(\d{4})-?W(\d{2})$`)
+)
+
+// -----------------------------------------------------------------------------
+// # Fiscal Calendar Types
+
+// FiscalPattern specifies how a fiscal year is divided into 12 periods.
+type FiscalPattern int
+
+const (
+	// FiscalMonths divides the fiscal year into calendar months.
+	FiscalMonths FiscalPattern = iota
+
+	// Fiscal445 divides the fiscal year into 52 or 53 whole weeks,
+	// with each quarter having periods of 4, 4 and 5 weeks.
+	// The extra week of 53-week years is added to the last period.
+	Fiscal445
+
+	// Fiscal454 is like Fiscal445, with periods of 4, 5 and 4 weeks.
+	Fiscal454
+
+	// Fiscal544 is like Fiscal445, with periods of 5, 4 and 4 weeks.
+	Fiscal544
+)
+
+// FiscalCalendar describes a fiscal year, which can start in any
+// month, and can be made of calendar months or of whole weeks as
+// in 4-4-5 and other 52/53-week retail calendars. Fiscal years
+// have 4 quarters of 3 periods each.
+//
+// The zero value is a fiscal year that is the same as the calendar
+// year. For example, a fiscal year that starts in April:
+//
+//	FiscalCalendar{StartMonth: time.April}
+//
+// A 4-5-4 retail calendar whose year ends on the Saturday nearest
+// to the end of January, named by the year in which it starts:
+//
+//	FiscalCalendar{
+//	    StartMonth:   time.February,
+//	    Pattern:      Fiscal454,
+//	    WeekEnd:      time.Saturday,
+//	    Nearest:      true,
+//	    NamedByStart: true,
+//	}
+//
+// Only the calendar date of the times passed to its methods is used.
+// Returned date ranges are in UTC.
+type FiscalCalendar struct {
+	// StartMonth is the month in which the fiscal year starts.
+	// Zero means January.
+	StartMonth time.Month
+
+	// Pattern specifies whether periods are calendar months
+	// or whole weeks, and how many weeks are in each period.
+	Pattern FiscalPattern
+
+	// WeekEnd is the last day of each week in week-based patterns.
+	// E.g. time.Saturday for weeks starting on Sunday.
+	WeekEnd time.Weekday
+
+	// Nearest applies to week-based patterns. If false, the fiscal year
+	// ends on the last WeekEnd day of the month before StartMonth.
+	// If true, it ends on the WeekEnd day nearest to the end of that
+	// month, which can be in the first days of StartMonth.
+	Nearest bool
+
+	// NamedByStart specifies that fiscal years are named by the calendar
+	// year in which they start. Otherwise, they are named by the year in
+	// which they end, so a fiscal year from April 2024 to March 2025 is
+	// fiscal year 2025.
+	NamedByStart bool
+} //                                                              FiscalCalendar
+
+// DateFiscalCalendar is the fiscal calendar used by
+// DateRangeOf to read fiscal years like "FY2024".
+var DateFiscalCalendar FiscalCalendar
+
+// -----------------------------------------------------------------------------
+// # Methods (ob FiscalCalendar)
+
+// FiscalPeriod returns the period (1 to 12) of the fiscal year
+// in which 'date' falls. Returns 0 if the calendar is not valid.
+func (ob FiscalCalendar) FiscalPeriod(date time.Time) int {
+	_, period := ob.find(date)
+	return period
+} //                                                                FiscalPeriod
+
+// FiscalQuarter returns the quarter (1 to 4) of the fiscal year
+// in which 'date' falls. Returns 0 if the calendar is not valid.
+func (ob FiscalCalendar) FiscalQuarter(date time.Time) int {
+	_, period := ob.find(date)
+	return (period + 2) / 3
+} //                                                               FiscalQuarter
+
+// FiscalYear returns the fiscal year in which 'date' falls.
+// Returns 0 if the calendar is not valid.
+func (ob FiscalCalendar) FiscalYear(date time.Time) int {
+	year, _ := ob.find(date)
+	return year
+} //                                                                  FiscalYear
+
+// PeriodRange returns the dates of a period (1 to 12) of a fiscal year.
+//
+// If the period or the calendar is not valid,
+// logs an error and returns a null DateRange.
+func (ob FiscalCalendar) PeriodRange(year, period int) DateRange {
+	if period < 1 || period > 12 {
+		mod.Error(EInvalidArg, "^period", ":", period)
+		return DateRange{}
+	}
+	periods := ob.periods(year)
+	if periods == nil {
+		return DateRange{}
+	}
+	return periods[period-1]
+} //                                                                 PeriodRange
+
+// QuarterRange returns the dates of a quarter (1 to 4) of a fiscal year.
+//
+// If the quarter or the calendar is not valid,
+// logs an error and returns a null DateRange.
+func (ob FiscalCalendar) QuarterRange(year, quarter int) DateRange {
+	if quarter < 1 || quarter > 4 {
+		mod.Error(EInvalidArg, "^quarter", ":", quarter)
+		return DateRange{}
+	}
+	periods := ob.periods(year)
+	if periods == nil {
+		return DateRange{}
+	}
+	return DateRange{
+		From: periods[quarter*3-3].From,
+		To:   periods[quarter*3-1].To,
+	}
+} //                                                                QuarterRange
+
+// YearRange returns the dates of a fiscal year.
+//
+// If the calendar is not valid, logs an error and returns a null DateRange.
+func (ob FiscalCalendar) YearRange(year int) DateRange {
+	periods := ob.periods(year)
+	if periods == nil {
+		return DateRange{}
+	}
+	return DateRange{From: periods[0].From, To: periods[11].To}
+} //                                                                   YearRange
+
+// -----------------------------------------------------------------------------
+// # ISO Week Functions
+
+// ISOWeekRange returns the dates of an ISO 8601 week, from Monday
+// to Sunday, in UTC. Week 1 is the week with the year's first
+// Thursday, so it can start in December of the previous year.
+//
+// If the week is not in the year (see WeeksInYear),
+// logs an error and returns a null DateRange.
+func ISOWeekRange(year, week int) DateRange {
+	if week < 1 || week > WeeksInYear(year) {
+		mod.Error(EInvalidArg, "^week", ":", week, "in", year)
+		return DateRange{}
+	}
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+(week-1)*7)
+	return dateRangeDays(monday, monday.AddDate(0, 0, 6))
+} //                                                                ISOWeekRange
+
+// WeeksInYear returns the number of ISO 8601 weeks in
+// the specified year, which is either 52 or 53.
+func WeeksInYear(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).
+		ISOWeek()
+	return week
+} //                                                                 WeeksInYear
+
+// -----------------------------------------------------------------------------
+// # Private Methods/Functions
+
+// find returns the fiscal year and period in which 'date' falls,
+// or zeros if the calendar is not valid.
+func (ob FiscalCalendar) find(date time.Time) (year, period int) {
+	day := businessDay(date)
+	for y := day.Year() - 1; y <= day.Year()+1; y++ {
+		periods := ob.periods(y)
+		if periods == nil {
+			return 0, 0
+		}
+		for i, it := range periods {
+			if it.Contains(day) {
+				return y, i + 1
+			}
+		}
+	}
+	return 0, 0
+} //                                                                        find
+
+// periods returns the 12 periods of a fiscal year in UTC.
+// If the calendar is not valid, logs an error and returns nil.
+func (ob FiscalCalendar) periods(year int) []DateRange {
+	start := ob.StartMonth
+	if start == 0 {
+		start = time.January
+	}
+	if start < time.January || start > time.December {
+		mod.Error(EInvalid, "^StartMonth", ":", int(ob.StartMonth))
+		return nil
+	}
+	if ob.WeekEnd < time.Sunday || ob.WeekEnd > time.Saturday {
+		mod.Error(EInvalid, "^WeekEnd", ":", int(ob.WeekEnd))
+		return nil
+	}
+	// the calendar year in which the fiscal year nominally starts
+	if !ob.NamedByStart && start != time.January {
+		year--
+	}
+	ret := make([]DateRange, 12)
+	var weeks [3]int
+	switch ob.Pattern {
+	case FiscalMonths:
+		for i := range ret {
+			from := time.Date(year, start+time.Month(i), 1, 0, 0, 0, 0,
+				time.UTC)
+			ret[i] = dateRangeDays(from, from.AddDate(0, 1, -1))
+		}
+		return ret
+	case Fiscal445:
+		weeks = [3]int{4, 4, 5}
+	case Fiscal454:
+		weeks = [3]int{4, 5, 4}
+	case Fiscal544:
+		weeks = [3]int{5, 4, 4}
+	default:
+		mod.Error(EInvalid, "^Pattern", ":", int(ob.Pattern))
+		return nil
+	}
+	var (
+		from = ob.yearEnd(year-1, start).AddDate(0, 0, 1)
+		to   = ob.yearEnd(year, start)
+		long = to.Sub(from) > 52*7*24*time.Hour
+	)
+	for i := range ret {
+		n := weeks[i%3]
+		if i == 11 && long {
+			n++
+		}
+		end := from.AddDate(0, 0, n*7-1)
+		ret[i] = dateRangeDays(from, end)
+		from = end.AddDate(0, 0, 1)
+	}
+	return ret
+} //                                                                     periods
+
+// yearEnd returns the last day of a week-based fiscal year that nominally
+// starts in month 'start' of 'year', which is near the end of the month
+// before 'start' in the following year. See FiscalCalendar.Nearest.
+func (ob FiscalCalendar) yearEnd(year int, start time.Month) time.Time {
+	var (
+		last = time.Date(year, start+12, 0, 0, 0, 0, 0, time.UTC)
+		back = (int(last.Weekday()) - int(ob.WeekEnd) + 7) % 7
+	)
+	if ob.Nearest && back > 3 {
+		back -= 7
+	}
+	return last.AddDate(0, 0, -back)
+} //                                                                     yearEnd
+
+// dateRangeFiscal returns the DateRange of a fiscal year or quarter,
+// e.g. "FY2024" or "FY2024-Q1" (see DateFiscalCalendar), or of an ISO
+// week, e.g. "2024-W07", with dates in time zone 'loc'. The string
+// must already be pre-formatted by DateRangeOf. Returns false if 's'
+// is not a fiscal year or ISO week.
+func dateRangeFiscal(s string, loc *time.Location) (DateRange, bool) {
+	var ret DateRange
+	if m := dateFiscalYearEx.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		if len(m[1]) == 2 {
+			year += 2000
+		}
+		if m[2] == "" {
+			ret = DateFiscalCalendar.YearRange(year)
+		} else {
+			quarter, _ := strconv.Atoi(m[2])
+			ret = DateFiscalCalendar.QuarterRange(year, quarter)
+		}
+	} else if m := dateISOWeekEx.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		ret = ISOWeekRange(year, week)
+	} else {
+		return DateRange{}, false
+	}
+	if ret.IsNull() {
+		return ret, true
+	}
+	var (
+		y1, m1, d1 = ret.From.Date()
+		y2, m2, d2 = ret.To.Date()
+	)
+	return dateRangeDays(
+		time.Date(y1, m1, d1, 0, 0, 0, 0, loc),
+		time.Date(y2, m2, d2, 0, 0, 0, 0, loc),
+	), true
+} //                                                             dateRangeFiscal
+
+// end
@@ -0,0 +1,265 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                           zr/[date_fiscal_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Methods (ob FiscalCalendar)
+//   Test_dfis_FiscalCalendar_months_
+//   Test_dfis_FiscalCalendar_weeks_
+//   Test_dfis_FiscalCalendar_errors_
+//
+// # ISO Week Functions
+//   Test_dfis_ISOWeekRange_
+//   Test_dfis_WeeksInYear_
+//
+// # Private Functions
+//   Test_dfis_dateRangeFiscal_
+
+//  to test all items in date_fiscal.go use:
+//      go test --run Test_dfis_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+	"time"
+)
+
+// dfisNRF is the 4-5-4 retail calendar of the National Retail
+// Federation, whose year ends on the Saturday nearest to January 31.
+var dfisNRF = FiscalCalendar{
+	StartMonth:   time.February,
+	Pattern:      Fiscal454,
+	WeekEnd:      time.Saturday,
+	Nearest:      true,
+	NamedByStart: true,
+}
+
+// dfisCheck checks the fiscal year, quarter and period of a date.
+func dfisCheck(t *testing.T, cal FiscalCalendar, date string,
+	year, quarter, period int) {
+	day := DateOf(date)
+	y, q, p := cal.FiscalYear(day), cal.FiscalQuarter(day),
+		cal.FiscalPeriod(day)
+	if y != year || q != quarter || p != period {
+		TFailf(t, `%s is in FY%d Q%d P%d instead of FY%d Q%d P%d`,
+			date, y, q, p, year, quarter, period)
+	}
+} //                                                                   dfisCheck
+
+// -----------------------------------------------------------------------------
+// # Methods (ob FiscalCalendar)
+
+// go test --run Test_dfis_FiscalCalendar_months_
+func Test_dfis_FiscalCalendar_months_(t *testing.T) {
+	TBegin(t)
+	//
+	// the zero value is the calendar year
+	var cal FiscalCalendar
+	dfisCheck(t, cal, "2024-01-01", 2024, 1, 1)
+	dfisCheck(t, cal, "2024-03-15", 2024, 1, 3)
+	dfisCheck(t, cal, "2024-12-31", 2024, 4, 12)
+	TEqual(t, cal.YearRange(2024).String(), "2024-01-01 2024-12-31")
+	TEqual(t, cal.QuarterRange(2024, 2).String(), "2024-04-01 2024-06-30")
+	TEqual(t, cal.PeriodRange(2024, 2).String(), "2024-02-01 2024-02-29")
+	//
+	// starting in April, named by the year in which it ends
+	cal = FiscalCalendar{StartMonth: time.April}
+	dfisCheck(t, cal, "2024-03-31", 2024, 4, 12)
+	dfisCheck(t, cal, "2024-04-01", 2025, 1, 1)
+	dfisCheck(t, cal, "2024-12-31", 2025, 3, 9)
+	dfisCheck(t, cal, "2025-01-01", 2025, 4, 10)
+	dfisCheck(t, cal, "2025-03-31", 2025, 4, 12)
+	TEqual(t, cal.YearRange(2025).String(), "2024-04-01 2025-03-31")
+	TEqual(t, cal.QuarterRange(2025, 4).String(), "2025-01-01 2025-03-31")
+	TEqual(t, cal.PeriodRange(2025, 11).String(), "2025-02-01 2025-02-28")
+	//
+	// the time of day and time zone don't matter
+	TEqual(t, cal.FiscalYear(time.Date(2024, 3, 31, 23, 30, 0, 0,
+		time.FixedZone("", -10*60*60))), 2024)
+	//
+	// named by the year in which it starts
+	cal.NamedByStart = true
+	dfisCheck(t, cal, "2024-04-01", 2024, 1, 1)
+	dfisCheck(t, cal, "2025-03-31", 2024, 4, 12)
+	TEqual(t, cal.YearRange(2024).String(), "2024-04-01 2025-03-31")
+	//
+	// US federal fiscal year
+	cal = FiscalCalendar{StartMonth: time.October}
+	TEqual(t, cal.YearRange(2024).String(), "2023-10-01 2024-09-30")
+	dfisCheck(t, cal, "2024-10-01", 2025, 1, 1)
+} //                                            Test_dfis_FiscalCalendar_months_
+
+// go test --run Test_dfis_FiscalCalendar_weeks_
+func Test_dfis_FiscalCalendar_weeks_(t *testing.T) {
+	TBegin(t)
+	//
+	// retail calendar with a 53-week year
+	cal := dfisNRF
+	rng := cal.YearRange(2023)
+	TEqual(t, rng.String(), "2023-01-29 2024-02-03")
+	TEqual(t, rng.Days(), 53*7)
+	TEqual(t, rng.From.Weekday(), time.Sunday)
+	TEqual(t, cal.PeriodRange(2023, 1).String(), "2023-01-29 2023-02-25")
+	TEqual(t, cal.PeriodRange(2023, 2).String(), "2023-02-26 2023-04-01")
+	TEqual(t, cal.PeriodRange(2023, 3).String(), "2023-04-02 2023-04-29")
+	TEqual(t, cal.PeriodRange(2023, 12).String(), "2023-12-31 2024-02-03")
+	TEqual(t, cal.QuarterRange(2023, 1).String(), "2023-01-29 2023-04-29")
+	TEqual(t, cal.YearRange(2024).String(), "2024-02-04 2025-02-01")
+	TEqual(t, cal.YearRange(2024).Days(), 52*7)
+	dfisCheck(t, cal, "2023-01-28", 2022, 4, 12)
+	dfisCheck(t, cal, "2023-01-29", 2023, 1, 1)
+	dfisCheck(t, cal, "2024-01-15", 2023, 4, 12)
+	dfisCheck(t, cal, "2024-02-03", 2023, 4, 12)
+	dfisCheck(t, cal, "2024-02-04", 2024, 1, 1)
+	//
+	// periods are whole weeks and cover the year without gaps
+	for year := 2000; year <= 2030; year++ {
+		prev := cal.YearRange(year - 1)
+		for period := 1; period <= 12; period++ {
+			rng := cal.PeriodRange(year, period)
+			TEqual(t, rng.From, prev.To.Add(1))
+			TEqual(t, rng.Days()%7, 0)
+			prev = rng
+		}
+		TEqual(t, prev.To, cal.YearRange(year).To)
+	}
+	// 4-4-5 ending on the last Saturday of December
+	cal = FiscalCalendar{Pattern: Fiscal445, WeekEnd: time.Saturday}
+	TEqual(t, cal.YearRange(2024).String(), "2023-12-31 2024-12-28")
+	TEqual(t, cal.PeriodRange(2024, 1).String(), "2023-12-31 2024-01-27")
+	TEqual(t, cal.PeriodRange(2024, 3).String(), "2024-02-25 2024-03-30")
+	dfisCheck(t, cal, "2023-12-31", 2024, 1, 1)
+	dfisCheck(t, cal, "2024-12-29", 2025, 1, 1)
+	//
+	// 5-4-4
+	cal.Pattern = Fiscal544
+	TEqual(t, cal.PeriodRange(2024, 1).String(), "2023-12-31 2024-02-03")
+	TEqual(t, cal.QuarterRange(2024, 1).String(), "2023-12-31 2024-03-30")
+} //                                             Test_dfis_FiscalCalendar_weeks_
+
+// go test --run Test_dfis_FiscalCalendar_errors_
+func Test_dfis_FiscalCalendar_errors_(t *testing.T) {
+	TBegin(t)
+	//
+	DisableErrors()
+	ec1 := GetErrorCount()
+	var cal FiscalCalendar
+	TTrue(t, cal.PeriodRange(2024, 0).IsNull())
+	TTrue(t, cal.PeriodRange(2024, 13).IsNull())
+	TTrue(t, cal.QuarterRange(2024, 5).IsNull())
+	TTrue(t, FiscalCalendar{StartMonth: 13}.YearRange(2024).IsNull())
+	TTrue(t, FiscalCalendar{Pattern: 9}.QuarterRange(2024, 1).IsNull())
+	TTrue(t, FiscalCalendar{Pattern: Fiscal445, WeekEnd: 7}.
+		PeriodRange(2024, 1).IsNull())
+	TEqual(t, FiscalCalendar{StartMonth: -1}.FiscalYear(DateOf("2024-01-01")),
+		0)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 7)
+} //                                            Test_dfis_FiscalCalendar_errors_
+
+// -----------------------------------------------------------------------------
+// # ISO Week Functions
+
+// go test --run Test_dfis_ISOWeekRange_
+func Test_dfis_ISOWeekRange_(t *testing.T) {
+	TBegin(t)
+	//
+	// ISOWeekRange(year, week int) DateRange
+	//
+	TEqual(t, ISOWeekRange(2024, 1).String(), "2024-01-01 2024-01-07")
+	TEqual(t, ISOWeekRange(2024, 7).String(), "2024-02-12 2024-02-18")
+	TEqual(t, ISOWeekRange(2024, 52).String(), "2024-12-23 2024-12-29")
+	TEqual(t, ISOWeekRange(2020, 53).String(), "2020-12-28 2021-01-03")
+	TEqual(t, ISOWeekRange(2021, 1).String(), "2021-01-04 2021-01-10")
+	TEqual(t, ISOWeekRange(2026, 1).String(), "2025-12-29 2026-01-04")
+	//
+	// agrees with time.ISOWeek
+	for year := 2000; year <= 2030; year++ {
+		for week := 1; week <= WeeksInYear(year); week++ {
+			rng := ISOWeekRange(year, week)
+			y, w := rng.From.ISOWeek()
+			TEqual(t, y*100+w, year*100+week)
+			TEqual(t, rng.From.Weekday(), time.Monday)
+			TEqual(t, rng.Days(), 7)
+		}
+	}
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, ISOWeekRange(2024, 0).IsNull())
+	TTrue(t, ISOWeekRange(2024, 53).IsNull())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 2)
+} //                                                     Test_dfis_ISOWeekRange_
+
+// go test --run Test_dfis_WeeksInYear_
+func Test_dfis_WeeksInYear_(t *testing.T) {
+	TBegin(t)
+	//
+	// WeeksInYear(year int) int
+	//
+	for year, expect := range map[int]int{
+		2004: 53, 2009: 53, 2015: 53, 2020: 53, 2026: 53,
+		2021: 52, 2022: 52, 2023: 52, 2024: 52, 2025: 52,
+	} {
+		TEqual(t, WeeksInYear(year), expect)
+	}
+} //                                                      Test_dfis_WeeksInYear_
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// go test --run Test_dfis_dateRangeFiscal_
+func Test_dfis_dateRangeFiscal_(t *testing.T) {
+	TBegin(t)
+	//
+	// used by DateRangeOf
+	//
+	prev := DateFiscalCalendar
+	defer func() { DateFiscalCalendar = prev }()
+	DateFiscalCalendar = FiscalCalendar{}
+	test := func(s, expect string) {
+		got := DateRangeOf(s).String()
+		if got != expect {
+			TFailf(t, `DateRangeOf(%q) returned %q instead of %q`,
+				s, got, expect)
+		}
+	}
+	test("2024-W07", "2024-02-12 2024-02-18")
+	test("2024W07", "2024-02-12 2024-02-18")
+	test("2024 w07", "2024-02-12 2024-02-18")
+	test("2020-W53 to 2021-W01", "2020-12-28 2021-01-10")
+	test("2024-W07 Europe/Paris", "2024-02-12 2024-02-18 Europe/Paris")
+	test("FY2024", "2024-01-01 2024-12-31")
+	//
+	DateFiscalCalendar = FiscalCalendar{StartMonth: time.April}
+	test("FY2025", "2024-04-01 2025-03-31")
+	test("fy25", "2024-04-01 2025-03-31")
+	test("FY 2025 Q2", "2024-07-01 2024-09-30")
+	test("FY2025-Q4", "2025-01-01 2025-03-31")
+	test("FY2025 to FY2026", "2024-04-01 2026-03-31")
+	//
+	DateFiscalCalendar = dfisNRF
+	test("FY2023", "2023-01-29 2024-02-03")
+	//
+	// not fiscal years or ISO weeks
+	for _, s := range []string{"FY", "FY2024Q5", "FY202", "2024-W7", "W07"} {
+		if _, ok := dateRangeFiscal(s, time.UTC); ok {
+			TFailf(t, `dateRangeFiscal(%q) returned true`, s)
+		}
+	}
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, DateRangeOf("2024-W53").IsNull())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 1)
+} //                                                  Test_dfis_dateRangeFiscal_
+
+// end
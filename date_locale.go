@@ -0,0 +1,416 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                zr/[date_locale.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Types
+//   DateOrder int
+//   DateLocale struct
+//
+// # Built-in Locales
+//   DateLocales = map[string]DateLocale
+//   DateLocaleOf(tag string) (DateLocale, bool)
+//
+// # Locale Functions
+//   DateRangeOfLocale(s string, loc DateLocale) DateRange
+//   FormatDate(format string, date time.Time, loc DateLocale) string
+//   MonthNumber(monthName string, loc DateLocale) int
+//
+// # Internal Methods/Functions
+//   (loc DateLocale) monthName(month time.Month, short bool) string
+//   (loc DateLocale) ordinal(n int) string
+//   (loc DateLocale) weekdayName(day time.Weekday, short bool) string
+//   dateLocaleWord(word string, words []string) bool
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// # Types
+
+// DateOrder specifies the order of the day, month and year
+// in dates written with numbers only, e.g. "7/3/2024".
+type DateOrder int
+
+const (
+	// DateOrderDMY writes the day first, e.g. 31/12/2024
+	DateOrderDMY DateOrder = iota
+
+	// DateOrderMDY writes the month first, e.g. 12/31/2024
+	DateOrderMDY
+
+	// DateOrderYMD writes the year first, e.g. 2024/12/31
+	DateOrderYMD
+)
+
+// DateLocale describes how dates are written in a particular
+// language or region.
+type DateLocale struct {
+	// Months contains the 12 month names, starting with January.
+	Months []string
+
+	// MonthsShort contains the 12 abbreviated month names.
+	MonthsShort []string
+
+	// Weekdays contains the 7 weekday names, starting with Sunday
+	// like time.Weekday.
+	Weekdays []string
+
+	// WeekdaysShort contains the 7 abbreviated weekday names.
+	WeekdaysShort []string
+
+	// AM and PM are written after the hour in 12-hour times.
+	AM, PM string
+
+	// FirstWeekday is the first day of the week, e.g. in calendars.
+	FirstWeekday time.Weekday
+
+	// Order is the usual order of numeric dates.
+	Order DateOrder
+
+	// RangeWords are written between the start and the end of
+	// a date range, e.g. "to" in "Jan to Mar".
+	RangeWords []string
+
+	// Particles are words ignored when reading dates,
+	// e.g. "de" in "7 de marzo de 2024".
+	Particles []string
+
+	// Ordinal returns the ordinal suffix of a number, e.g. "st" for 1
+	// in English. If nil, a period is used: "7." in German.
+	Ordinal func(n int) string
+} //                                                                  DateLocale
+
+// -----------------------------------------------------------------------------
+// # Built-in Locales
+
+// dateLocaleEN is the English locale used by functions with
+// the 'EN' suffix and by Calendar.String()
+var dateLocaleEN = DateLocale{
+	Months: MonthNamesEN,
+	MonthsShort: []string{
+		"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+		"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+	},
+	Weekdays: []string{
+		"Sunday", "Monday", "Tuesday", "Wednesday",
+		"Thursday", "Friday", "Saturday",
+	},
+	WeekdaysShort: []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	AM:            "AM",
+	PM:            "PM",
+	FirstWeekday:  time.Monday,
+	Order:         DateOrderDMY,
+	RangeWords:    []string{"to"},
+	Ordinal:       datePatternOrdinal,
+} //                                                                dateLocaleEN
+
+// DateLocales contains the date formats of common locales, indexed by
+// language tag (e.g. "en-US", "de-DE"). You can add more locales, or
+// copy an existing one and change it, e.g. to set FirstWeekday.
+var DateLocales = map[string]DateLocale{
+	"de-DE": {
+		Months: []string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		MonthsShort: []string{
+			"Jan", "Feb", "Mär", "Apr", "Mai", "Jun",
+			"Jul", "Aug", "Sep", "Okt", "Nov", "Dez",
+		},
+		Weekdays: []string{
+			"Sonntag", "Montag", "Dienstag", "Mittwoch",
+			"Donnerstag", "Freitag", "Samstag",
+		},
+		WeekdaysShort: []string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		AM:            "AM",
+		PM:            "PM",
+		FirstWeekday:  time.Monday,
+		Order:         DateOrderDMY,
+		RangeWords:    []string{"bis"},
+	},
+	"en-GB": dateLocaleEN,
+	"en-US": func() DateLocale {
+		ret := dateLocaleEN
+		ret.FirstWeekday = time.Sunday
+		ret.Order = DateOrderMDY
+		return ret
+	}(),
+	"es-ES": {
+		Months: []string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+			"agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		},
+		MonthsShort: []string{
+			"ene", "feb", "mar", "abr", "may", "jun",
+			"jul", "ago", "sep", "oct", "nov", "dic",
+		},
+		Weekdays: []string{
+			"domingo", "lunes", "martes", "miércoles",
+			"jueves", "viernes", "sábado",
+		},
+		WeekdaysShort: []string{
+			"dom", "lun", "mar", "mié", "jue", "vie", "sáb",
+		},
+		AM:           "a. m.",
+		PM:           "p. m.",
+		FirstWeekday: time.Monday,
+		Order:        DateOrderDMY,
+		RangeWords:   []string{"a", "al", "hasta"},
+		Particles:    []string{"de", "del"},
+		Ordinal:      func(int) string { return "º" },
+	},
+	"fr-FR": {
+		Months: []string{
+			"janvier", "février", "mars", "avril", "mai", "juin", "juillet",
+			"août", "septembre", "octobre", "novembre", "décembre",
+		},
+		MonthsShort: []string{
+			"janv.", "févr.", "mars", "avr.", "mai", "juin",
+			"juil.", "août", "sept.", "oct.", "nov.", "déc.",
+		},
+		Weekdays: []string{
+			"dimanche", "lundi", "mardi", "mercredi",
+			"jeudi", "vendredi", "samedi",
+		},
+		WeekdaysShort: []string{
+			"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam.",
+		},
+		AM:           "AM",
+		PM:           "PM",
+		FirstWeekday: time.Monday,
+		Order:        DateOrderDMY,
+		RangeWords:   []string{"au"},
+		Ordinal: func(n int) string {
+			if n == 1 {
+				return "er"
+			}
+			return "e"
+		},
+	},
+	"it-IT": {
+		Months: []string{
+			"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre",
+		},
+		MonthsShort: []string{
+			"gen", "feb", "mar", "apr", "mag", "giu",
+			"lug", "ago", "set", "ott", "nov", "dic",
+		},
+		Weekdays: []string{
+			"domenica", "lunedì", "martedì", "mercoledì",
+			"giovedì", "venerdì", "sabato",
+		},
+		WeekdaysShort: []string{
+			"dom", "lun", "mar", "mer", "gio", "ven", "sab",
+		},
+		AM:           "AM",
+		PM:           "PM",
+		FirstWeekday: time.Monday,
+		Order:        DateOrderDMY,
+		RangeWords:   []string{"a", "al"},
+		Ordinal:      func(int) string { return "º" },
+	},
+	"pt-BR": {
+		Months: []string{
+			"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+		},
+		MonthsShort: []string{
+			"jan", "fev", "mar", "abr", "mai", "jun",
+			"jul", "ago", "set", "out", "nov", "dez",
+		},
+		Weekdays: []string{
+			"domingo", "segunda-feira", "terça-feira", "quarta-feira",
+			"quinta-feira", "sexta-feira", "sábado",
+		},
+		WeekdaysShort: []string{
+			"dom", "seg", "ter", "qua", "qui", "sex", "sáb",
+		},
+		AM:           "AM",
+		PM:           "PM",
+		FirstWeekday: time.Sunday,
+		Order:        DateOrderDMY,
+		RangeWords:   []string{"a", "até"},
+		Particles:    []string{"de"},
+		Ordinal:      func(int) string { return "º" },
+	},
+} //                                                                 DateLocales
+
+// DateLocaleOf returns the built-in date locale for the specified
+// language tag, e.g. "en-US", "de_DE" or "FR". If the tag only
+// specifies a language, or its region is not in DateLocales,
+// returns the first locale of the language in alphabetical
+// order of tags, e.g. "en-GB" for "en".
+// Returns false if no locale of the language is found.
+func DateLocaleOf(tag string) (DateLocale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	lang := tag
+	if i := strings.Index(tag, "-"); i != -1 {
+		lang = tag[:i]
+		tag = strings.ToLower(lang) + "-" + strings.ToUpper(tag[i+1:])
+	}
+	if ret, found := DateLocales[tag]; found {
+		return ret, true
+	}
+	tags := make([]string, 0, len(DateLocales))
+	for key := range DateLocales {
+		tags = append(tags, key)
+	}
+	sort.Strings(tags)
+	prefix := strings.ToLower(lang) + "-"
+	for _, key := range tags {
+		if strings.HasPrefix(key, prefix) {
+			return DateLocales[key], true
+		}
+	}
+	return DateLocale{}, false
+} //                                                                DateLocaleOf
+
+// -----------------------------------------------------------------------------
+// # Locale Functions
+
+// DateRangeOfLocale creates and returns a DateRange structure from
+// a string written in the specified locale, like DateRangeOf().
+// Reads month names and range words (e.g. "bis" in German) of the
+// locale, skips its particles, and reads numeric dates in the
+// locale's date order.
+//
+// For example, the following returns 7 to 31 March 2024:
+// DateRangeOfLocale("7 de marzo de 2024 al 31 de marzo de 2024", loc)
+func DateRangeOfLocale(s string, loc DateLocale) DateRange {
+	s = strings.TrimSpace(s)
+	for _, sep := range []string{" ", ".", "/", "\\", "_", ","} {
+		s = strings.ReplaceAll(s, sep, "-")
+	}
+	var (
+		ret  []string
+		side []string
+	)
+	// changes a numeric date to day-month-year or yyyy-mm-dd
+	// (which are read by DateRangeOf) and adds it to ret
+	addSide := func() {
+		isNumeric := len(side) == 3
+		for i := 0; isNumeric && i < 3; i++ {
+			isNumeric = IsNumber(side[i])
+		}
+		switch {
+		case isNumeric && len(side[0]) == 4:
+			for i := 1; i < 3; i++ {
+				if len(side[i]) == 1 {
+					side[i] = "0" + side[i]
+				}
+			}
+		case len(side) == 3 && loc.Order == DateOrderMDY &&
+			IsNumber(side[0]) && IsNumber(side[1]):
+			side[0], side[1] = side[1], side[0]
+		}
+		ret = append(ret, side...)
+		side = nil
+	}
+	for _, word := range strings.Split(s, "-") {
+		switch {
+		case word == "" || dateLocaleWord(word, loc.Particles):
+			continue
+		case dateLocaleWord(word, loc.RangeWords) ||
+			strings.EqualFold(word, "to"):
+			addSide()
+			ret = append(ret, "to")
+			continue
+		}
+		if month := MonthNumber(word, loc); month != 0 {
+			word = dateLocaleEN.MonthsShort[month-1]
+		}
+		side = append(side, word)
+	}
+	addSide()
+	return DateRangeOf(strings.Join(ret, "-"))
+} //                                                           DateRangeOfLocale
+
+// FormatDate formats date using the specified format and the
+// month and weekday names of the specified locale.
+// See DatePattern for the supported fields.
+//
+// If the format is not valid, logs an error and returns a blank string.
+func FormatDate(format string, date time.Time, loc DateLocale) string {
+	pat, err := DatePatternE(format)
+	if err != nil {
+		mod.Error(err)
+		return ""
+	}
+	return pat.FormatLocale(date, loc)
+} //                                                                  FormatDate
+
+// MonthNumber returns a month number from 1 to 12, given a full or
+// abbreviated month name of the specified locale, e.g. "März" or
+// "Mär" in German. The case and a trailing period are not important.
+// If the string is not a month name, returns zero.
+func MonthNumber(monthName string, loc DateLocale) int {
+	monthName = strings.TrimSuffix(strings.TrimSpace(monthName), ".")
+	if monthName == "" {
+		return 0
+	}
+	for _, names := range [][]string{loc.Months, loc.MonthsShort} {
+		for i, name := range names {
+			if i < 12 && strings.EqualFold(monthName,
+				strings.TrimSuffix(name, ".")) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+} //                                                                 MonthNumber
+
+// -----------------------------------------------------------------------------
+// # Internal Methods/Functions
+
+// monthName returns the full or abbreviated name of the month,
+// or the English name if the locale doesn't specify it.
+func (loc DateLocale) monthName(month time.Month, short bool) string {
+	names := loc.Months
+	if short {
+		names = loc.MonthsShort
+	}
+	if int(month) > len(names) {
+		return dateLocaleEN.monthName(month, short)
+	}
+	return names[month-1]
+} //                                                                   monthName
+
+// ordinal returns the ordinal suffix of n, e.g. "st" for 1 in English.
+func (loc DateLocale) ordinal(n int) string {
+	if loc.Ordinal == nil {
+		return "."
+	}
+	return loc.Ordinal(n)
+} //                                                                     ordinal
+
+// weekdayName returns the full or abbreviated name of the weekday,
+// or the English name if the locale doesn't specify it.
+func (loc DateLocale) weekdayName(day time.Weekday, short bool) string {
+	names := loc.Weekdays
+	if short {
+		names = loc.WeekdaysShort
+	}
+	if int(day) >= len(names) {
+		return dateLocaleEN.weekdayName(day, short)
+	}
+	return names[day]
+} //                                                                 weekdayName
+
+// dateLocaleWord returns true if word is one of words, ignoring case.
+func dateLocaleWord(word string, words []string) bool {
+	for _, w := range words {
+		if strings.EqualFold(word, w) {
+			return true
+		}
+	}
+	return false
+} //                                                              dateLocaleWord
+
+// end
@@ -0,0 +1,211 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                           zr/[date_locale_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Built-in Locales
+//   Test_dloc_DateLocaleOf_
+//   Test_dloc_DateLocales_
+//
+// # Locale Functions
+//   Test_dloc_DateRangeOfLocale_
+//   Test_dloc_FormatDate_
+//   Test_dloc_MonthNumber_
+
+//  to test all items in date_locale.go use:
+//      go test --run Test_dloc_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+	"time"
+)
+
+// dlocLocale returns the built-in date locale with the specified tag.
+func dlocLocale(t *testing.T, tag string) DateLocale {
+	ret, found := DateLocaleOf(tag)
+	if !found {
+		t.Fatalf("date locale %q not found", tag)
+	}
+	return ret
+} //                                                                  dlocLocale
+
+// -----------------------------------------------------------------------------
+// # Built-in Locales
+
+// go test --run Test_dloc_DateLocaleOf_
+func Test_dloc_DateLocaleOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateLocaleOf(tag string) (DateLocale, bool)
+	//
+	test := func(tag, expectMonth string, expectFirst time.Weekday) {
+		got, found := DateLocaleOf(tag)
+		if !found || got.Months[2] != expectMonth ||
+			got.FirstWeekday != expectFirst {
+			TFailf(t, `DateLocaleOf(%q) returned %v, %v`, tag, got, found)
+		}
+	}
+	test("en-US", "March", time.Sunday)
+	test("en_gb", "March", time.Monday)
+	test(" EN ", "March", time.Monday)
+	test("en-AU", "March", time.Monday)
+	test("de", "März", time.Monday)
+	test("DE-at", "März", time.Monday)
+	test("es", "marzo", time.Monday)
+	test("fr-CA", "mars", time.Monday)
+	test("it-IT", "marzo", time.Monday)
+	test("pt", "março", time.Sunday)
+	//
+	for _, tag := range []string{"", "xx", "xx-US", "-US"} {
+		_, found := DateLocaleOf(tag)
+		TFalse(t, found)
+	}
+} //                                                     Test_dloc_DateLocaleOf_
+
+// go test --run Test_dloc_DateLocales_
+func Test_dloc_DateLocales_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateLocales = map[string]DateLocale
+	//
+	for tag, loc := range DateLocales {
+		if len(loc.Months) != 12 || len(loc.MonthsShort) != 12 ||
+			len(loc.Weekdays) != 7 || len(loc.WeekdaysShort) != 7 {
+			TFailf(t, `date locale %q has missing names`, tag)
+		}
+		for i := range loc.Months {
+			if MonthNumber(loc.Months[i], loc) != i+1 ||
+				MonthNumber(loc.MonthsShort[i], loc) != i+1 {
+				TFailf(t, `date locale %q: month %d not found`, tag, i+1)
+			}
+		}
+	}
+} //                                                      Test_dloc_DateLocales_
+
+// -----------------------------------------------------------------------------
+// # Locale Functions
+
+// go test --run Test_dloc_DateRangeOfLocale_
+func Test_dloc_DateRangeOfLocale_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateRangeOfLocale(s string, loc DateLocale) DateRange
+	//
+	test := func(s, tag, expect string) {
+		got := DateRangeOfLocale(s, dlocLocale(t, tag))
+		if got.String() != expect {
+			TFailf(t, `DateRangeOfLocale(%q, %q) returned %q instead of %q`,
+				s, tag, got.String(), expect)
+		}
+	}
+	// English
+	test("7 Mar 2024", "en-GB", "2024-03-07 2024-03-07")
+	test("7/3/2024", "en-GB", "2024-03-07 2024-03-07")
+	test("7/3/2024", "en-US", "2024-07-03 2024-07-03")
+	test("March 7, 2024", "en-US", "2024-03-07 2024-03-07")
+	test("1/3/2024 to 2/3/2024", "en-US", "2024-01-03 2024-02-03")
+	test("2024/3/7", "en-US", "2024-03-07 2024-03-07")
+	//
+	// German
+	test("7. März 2024", "de", "2024-03-07 2024-03-07")
+	test("7.3.2024", "de", "2024-03-07 2024-03-07")
+	test("Okt 2024", "de", "2024-10-01 2024-10-31")
+	test("Januar 2024 bis März 2024", "de", "2024-01-01 2024-03-31")
+	//
+	// Spanish
+	test("7 de marzo de 2024", "es", "2024-03-07 2024-03-07")
+	test("enero de 2024 a marzo de 2024", "es", "2024-01-01 2024-03-31")
+	test("7 de marzo de 2024 al 31 de marzo de 2024", "es",
+		"2024-03-07 2024-03-31")
+	test("DICIEMBRE 2023", "es", "2023-12-01 2023-12-31")
+	//
+	// French, Italian and Portuguese
+	test("1 févr. 2024", "fr", "2024-02-01 2024-02-01")
+	test("août 2024", "fr", "2024-08-01 2024-08-31")
+	test("2024 dicembre", "it", "2024-12-01 2024-12-31")
+	test("15 de março de 2024", "pt", "2024-03-15 2024-03-15")
+	//
+	// not a date
+	TTrue(t, DateRangeOfLocale("xyz", dlocLocale(t, "de")).IsNull())
+} //                                                Test_dloc_DateRangeOfLocale_
+
+// go test --run Test_dloc_FormatDate_
+func Test_dloc_FormatDate_(t *testing.T) {
+	TBegin(t)
+	//
+	// FormatDate(format string, date time.Time, loc DateLocale) string
+	//
+	date := time.Date(2024, 3, 1, 15, 4, 0, 0, time.UTC)
+	test := func(format, tag, expect string) {
+		got := FormatDate(format, date, dlocLocale(t, tag))
+		if got != expect {
+			TFailf(t, `FormatDate(%q, %q) returned %q instead of %q`,
+				format, tag, got, expect)
+		}
+	}
+	test("Dddd, do Mmmm yyyy", "en-US", "Friday, 1st March 2024")
+	test("dddd, d. mmmm yyyy", "de", "freitag, 1. märz 2024")
+	test("Dddd, d. Mmmm yyyy", "de", "Freitag, 1. März 2024")
+	test("Ddd do MMM", "de", "Fr 1. MÄR")
+	test("dddd d 'de' mmmm 'de' yyyy", "es", "viernes 1 de marzo de 2024")
+	test("h:nn tt", "es", "3:04 p. m.")
+	test("h:nn t", "es", "3:04 p")
+	test("ddd do mmm", "fr", "ven. 1er mars")
+	test("dddd do mmmm", "it", "venerdì 1º marzo")
+	test("Dddd, d 'de' Mmmm", "pt", "Sexta-feira, 1 de Março")
+	test("DDDD", "pt", "SEXTA-FEIRA")
+	//
+	// the locale's names are used even if the format is English
+	TEqual(t, FormatDate("d mmm yyyy", date, dlocLocale(t, "en")),
+		FormatDateEN("d mmm yyyy", date))
+	//
+	// invalid formats are logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got := FormatDate("'d", date, dlocLocale(t, "de"))
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, got, "")
+	TEqual(t, ec2-ec1, 1)
+} //                                                       Test_dloc_FormatDate_
+
+// go test --run Test_dloc_MonthNumber_
+func Test_dloc_MonthNumber_(t *testing.T) {
+	TBegin(t)
+	//
+	// MonthNumber(monthName string, loc DateLocale) int
+	//
+	test := func(monthName, tag string, expect int) {
+		got := MonthNumber(monthName, dlocLocale(t, tag))
+		if got != expect {
+			TFailf(t, `MonthNumber(%q, %q) returned %d instead of %d`,
+				monthName, tag, got, expect)
+		}
+	}
+	test("March", "en", 3)
+	test(" dec ", "en", 12)
+	test("märz", "de", 3)
+	test("MÄRZ", "de", 3)
+	test("Mär.", "de", 3)
+	test("Okt", "de", 10)
+	test("octubre", "es", 10)
+	test("janv.", "fr", 1)
+	test("janv", "fr", 1)
+	test("Décembre", "fr", 12)
+	test("mag", "it", 5)
+	test("out", "pt", 10)
+	//
+	test("", "en", 0)
+	test("Oktober", "en", 0)
+	test("Octo", "en", 0)
+	test(".", "fr", 0)
+	TEqual(t, MonthNumber("May", DateLocale{}), 0)
+} //                                                      Test_dloc_MonthNumber_
+
+// end
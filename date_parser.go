@@ -0,0 +1,340 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                zr/[date_parser.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Error Type
+//   DateParseError struct
+//   (ob *DateParseError) Error() string
+//
+// # DateParser Type
+//   DateParser struct
+//   (ob DateParser) Parse(s string) (time.Time, error)
+//
+// # Functions
+//   DateE(value interface{}) (time.Time, error)
+//
+// # Internal Functions
+//   dateParseClock(clock string) (hour, min, sec, nsec int, ok bool)
+//   dateParseFields(ob DateParser, s, date string) (y, m, d int, err error)
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// dateFieldEx matches the numbers and words in a date,
+	// e.g. "4", "March" and "2024" in "4 March, 2024".
+	dateFieldEx = regexp.MustCompile(`\d+|[^\W\d_]+`)
+
+	// dateSeparatorEx matches the text allowed between
+	// the numbers and words of a date.
+	dateSeparatorEx = regexp.MustCompile(`^[\s,./\\-]*$`)
+)
+
+// -----------------------------------------------------------------------------
+// # Error Type
+
+// DateParseError describes why a string could not be parsed
+// by DateParser.Parse() or DateE(), and which field failed.
+type DateParseError struct {
+	Input  string // the string that was being parsed
+	Field  string // "year", "month", "day", "weekday", "time" or ""
+	Pos    int    // byte offset of the failing field in Input
+	Reason string // description of the problem
+} //                                                              DateParseError
+
+// Error returns a description of the parsing
+// error and implements the error interface.
+func (ob *DateParseError) Error() string {
+	if ob == nil {
+		return ENilReceiver
+	}
+	if ob.Field == "" {
+		return fmt.Sprintf("%s date %q at %d: %s",
+			EFailedParsing, ob.Input, ob.Pos, ob.Reason)
+	}
+	return fmt.Sprintf("%s date %q at %d (%s): %s",
+		EFailedParsing, ob.Input, ob.Pos, ob.Field, ob.Reason)
+} //                                                                       Error
+
+// -----------------------------------------------------------------------------
+// # DateParser Type
+
+// DateParser reads dates from strings and returns errors instead of
+// guessing, unlike DateOf() and ParseDate(). The zero value reads
+// numeric dates in day-month-year order and is ready to use.
+//
+// It accepts the layouts in Layouts and DateFormatsDMY, and dates made
+// of a day, a month and a year, which can be a number or an English
+// month name, separated by spaces, commas, periods, slashes or hyphens,
+// e.g. "2024-03-04", "04/03/2024", "4 March 2024" or "Mon, Mar 4th,
+// 2024". A date can be followed by a time like "15:04" or "15:04:05"
+// and a time zone like "Z", "+02:00" or "Europe/Paris".
+//
+// Numeric dates starting with a 4-digit year are always year-month-day.
+// Other numeric dates use Order. When the day is more than 12, the
+// day and month can't be confused, e.g. "31/12/2024" is read as 31
+// December even if Order is DateOrderMDY. If Strict is true, dates
+// where the day and month could be swapped, e.g. "03/04/2024", and
+// 2-digit years are rejected.
+type DateParser struct {
+	// Order is the order of the day, month and
+	// year in numeric dates like "03/04/2024".
+	Order DateOrder
+
+	// Layouts lists time.Parse layouts, like "02.01.2006 15:04",
+	// that are tried first. They don't make the parser strict.
+	Layouts []string
+
+	// Strict rejects ambiguous dates instead of using Order.
+	Strict bool
+
+	// Location is the time zone of dates without one. Nil means UTC.
+	Location *time.Location
+} //                                                                  DateParser
+
+// Parse reads a date, optionally with a time of day and a time zone.
+// Does not log errors.
+//
+// An empty string is not an error: returns a zero time.Time and nil.
+// If the string is not a valid date, returns a zero time.Time and
+// a *DateParseError describing the failing field.
+func (ob DateParser) Parse(s string) (time.Time, error) {
+	input := s
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	loc := ob.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	layouts := append([]string{}, ob.Layouts...)
+	for _, it := range DateFormatsDMY {
+		layouts = append(layouts, it.In)
+	}
+	for _, layout := range append(layouts, time.RFC3339Nano) {
+		if ret, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return ret, nil
+		}
+	}
+	date, clock, zone := dateSplitZone(s)
+	if zone != nil {
+		loc = zone
+	}
+	y, m, d, err := dateParseFields(ob, input, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, min, sec, nsec, ok := dateParseClock(clock)
+	if !ok {
+		return time.Time{}, &DateParseError{Input: input, Field: "time",
+			Pos: strings.Index(input, clock), Reason: EInvalid + " time"}
+	}
+	return time.Date(y, time.Month(m), d, hour, min, sec, nsec, loc), nil
+} //                                                                       Parse
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// DateE converts any string-like value or time.Time to a date (at
+// midnight UTC), like DateOf(), but returns an error instead of
+// logging it. Strings are read by a zero-value DateParser, so that
+// numeric dates are in day-month-year order. Does not log errors.
+//
+// If value is a zero-length string, returns a zero-value time.Time
+// and nil. Other strings that can not be read return a
+// *DateParseError describing the failing field.
+func DateE(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return DateOf(v), nil
+	case string:
+		ret, err := DateParser{}.Parse(v)
+		if err != nil || ret.IsZero() {
+			return time.Time{}, err
+		}
+		return DateOf(ret), nil
+	case *string:
+		if v != nil {
+			return DateE(*v)
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s %v to date: %v",
+		EInvalidType, reflect.TypeOf(value), value)
+} //                                                                       DateE
+
+// -----------------------------------------------------------------------------
+// # Internal Functions
+
+// dateParseClock reads a time of day like "15:04", "15:04:05" or
+// "15:04:05.999". An empty string is midnight. Returns false if
+// the time is not valid.
+func dateParseClock(clock string) (hour, min, sec, nsec int, ok bool) {
+	if clock == "" {
+		return 0, 0, 0, 0, true
+	}
+	layout := "15:04"
+	if strings.Count(clock, ":") == 2 {
+		layout = "15:04:05"
+	}
+	tm, err := time.Parse(layout, clock)
+	if err != nil {
+		return 0, 0, 0, 0, false
+	}
+	return tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), true
+} //                                                              dateParseClock
+
+// dateParseFields reads the year, month and day from 'date', which is
+// the part of 's' before the time of day. The positions of errors are
+// byte offsets in 's'.
+func dateParseFields(ob DateParser, s, date string) (y, m, d int, err error) {
+	fail := func(field string, pos int, reason string) (int, int, int, error) {
+		return 0, 0, 0, &DateParseError{
+			Input: s, Field: field, Pos: pos, Reason: reason,
+		}
+	}
+	type field struct {
+		text string
+		pos  int // byte offset in s
+	}
+	var (
+		nums    []field
+		month   *field
+		weekday *field
+		prevEnd = 0  // end of the previous field in 'date'
+		at      = -1 // position in s after the previous field
+	)
+	for _, loc := range dateFieldEx.FindAllStringIndex(date, -1) {
+		text := date[loc[0]:loc[1]]
+		// find the field in s, since date may be reformatted
+		if i := strings.Index(s[at+1:], text); i != -1 {
+			at += 1 + i
+		}
+		f := field{text: text, pos: at}
+		at += len(text) - 1
+		sep := date[prevEnd:loc[0]]
+		prevEnd = loc[1]
+		if !dateSeparatorEx.MatchString(sep) {
+			return fail("", strings.Index(s, sep), fmt.Sprintf(
+				"unexpected %q", strings.TrimSpace(sep)))
+		}
+		if text[0] >= '0' && text[0] <= '9' {
+			nums = append(nums, f)
+			continue
+		}
+		upper := strings.ToUpper(text)
+		switch {
+		case sep == "" && len(nums) > 0 && (upper == "ST" ||
+			upper == "ND" || upper == "RD" || upper == "TH"):
+			continue // ordinal suffix, e.g. "4th"
+		case MonthNumber(text, dateLocaleEN) != 0 && month == nil:
+			month = &f
+			continue
+		}
+		if _, ok := dateRelativeWeekday(upper); ok && weekday == nil {
+			weekday = &f
+			continue
+		}
+		return fail("month", f.pos, fmt.Sprintf("%s month %q",
+			EInvalid, text))
+	}
+	if !dateSeparatorEx.MatchString(date[prevEnd:]) {
+		return fail("", strings.LastIndex(s, date[prevEnd:]),
+			fmt.Sprintf("unexpected %q", strings.TrimSpace(date[prevEnd:])))
+	}
+	// assign the numbers to the year, month and day
+	var yf, mf, df field
+	switch {
+	case month != nil && len(nums) == 2:
+		mf = *month
+		if len(nums[0].text) > 2 {
+			yf, df = nums[0], nums[1]
+		} else {
+			df, yf = nums[0], nums[1]
+		}
+	case month == nil && len(nums) == 3:
+		a, b := nums[0], nums[1]
+		switch {
+		case len(a.text) > 2 || ob.Order == DateOrderYMD:
+			yf, mf, df = a, b, nums[2]
+		default:
+			yf = nums[2]
+			var (
+				an, _ = strconv.Atoi(a.text)
+				bn, _ = strconv.Atoi(b.text)
+			)
+			switch {
+			case an > 12 && bn <= 12:
+				df, mf = a, b
+			case bn > 12 && an <= 12:
+				mf, df = a, b
+			case an != bn && ob.Strict:
+				return fail("day", a.pos, fmt.Sprintf(
+					"ambiguous date: %s and %s can be the day or month",
+					a.text, b.text))
+			case ob.Order == DateOrderMDY:
+				mf, df = a, b
+			default:
+				df, mf = a, b
+			}
+		}
+	default:
+		return fail("", 0, "date must have a day, month and year")
+	}
+	// check the numbers
+	if len(yf.text) > 4 || len(yf.text) == 3 {
+		return fail("year", yf.pos, fmt.Sprintf("%s year %q", EInvalid,
+			yf.text))
+	}
+	y, _ = strconv.Atoi(yf.text)
+	if len(yf.text) <= 2 {
+		if ob.Strict {
+			return fail("year", yf.pos, fmt.Sprintf(
+				"ambiguous 2-digit year %q", yf.text))
+		}
+		if y >= 70 {
+			y += 1900
+		} else {
+			y += 2000
+		}
+	}
+	if y < 1 {
+		return fail("year", yf.pos, fmt.Sprintf("%s year %q", EInvalid,
+			yf.text))
+	}
+	if month != nil {
+		m = MonthNumber(month.text, dateLocaleEN)
+	} else {
+		m, _ = strconv.Atoi(mf.text)
+	}
+	if m < 1 || m > 12 || len(mf.text) > 2 && month == nil {
+		return fail("month", mf.pos, fmt.Sprintf("%s month %q", EInvalid,
+			mf.text))
+	}
+	d, _ = strconv.Atoi(df.text)
+	if d < 1 || d > DaysInMonth(y, time.Month(m)) || len(df.text) > 2 {
+		return fail("day", df.pos, fmt.Sprintf("%s day %q in %s %d",
+			EInvalid, df.text, time.Month(m), y))
+	}
+	if weekday != nil {
+		wd, _ := dateRelativeWeekday(strings.ToUpper(weekday.text))
+		actual := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC).
+			Weekday()
+		if wd != actual {
+			return fail("weekday", weekday.pos, fmt.Sprintf(
+				"%d-%02d-%02d is a %s, not %s", y, m, d, actual, wd))
+		}
+	}
+	return y, m, d, nil
+} //                                                             dateParseFields
+
+// end
@@ -0,0 +1,258 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                           zr/[date_parser_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+//   Test_dprs_DateParseError_
+//   Test_dprs_DateParser_Parse_
+//   Test_dprs_DateParser_Parse_errors_
+//   Test_dprs_DateParser_Parse_order_
+//   Test_dprs_DateE_
+
+//  to test all items in date_parser.go use:
+//      go test --run Test_dprs_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// go test --run Test_dprs_DateParseError_
+func Test_dprs_DateParseError_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *DateParseError) Error() string
+	//
+	var nilErr *DateParseError
+	TEqual(t, nilErr.Error(), ENilReceiver)
+	//
+	err := &DateParseError{Input: "31/02/2024", Field: "day", Pos: 0,
+		Reason: "invalid day"}
+	TEqual(t, err.Error(),
+		`failed parsing date "31/02/2024" at 0 (day): invalid day`)
+	//
+	err = &DateParseError{Input: "x", Reason: "no date"}
+	TEqual(t, err.Error(), `failed parsing date "x" at 0: no date`)
+} //                                                   Test_dprs_DateParseError_
+
+// go test --run Test_dprs_DateParser_Parse_
+func Test_dprs_DateParser_Parse_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateParser) Parse(s string) (time.Time, error)
+	//
+	paris, _ := time.LoadLocation("Europe/Paris")
+	test := func(p DateParser, s string, expect time.Time) {
+		got, err := p.Parse(s)
+		if err != nil || !got.Equal(expect) ||
+			got.Location().String() != expect.Location().String() {
+			TFailf(t, `Parse(%q) returned %v, %v instead of %v`,
+				s, got, err, expect)
+		}
+	}
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	var p DateParser
+	test(p, "", time.Time{})
+	test(p, "   ", time.Time{})
+	//
+	// ISO dates and layouts from DateFormatsDMY
+	test(p, "2024-03-04", date(2024, 3, 4))
+	test(p, " 2024-03-04 ", date(2024, 3, 4))
+	test(p, "2024-03-04 15:04:05",
+		time.Date(2024, 3, 4, 15, 4, 5, 0, time.UTC))
+	test(p, "2024/3/4", date(2024, 3, 4))
+	test(p, "2024.03.04", date(2024, 3, 4))
+	//
+	// numeric dates in day-month-year order
+	test(p, "04/03/2024", date(2024, 3, 4))
+	test(p, "4.3.2024", date(2024, 3, 4))
+	test(p, "04-03-2024", date(2024, 3, 4))
+	test(p, "04/03/24", date(2024, 3, 4))
+	test(p, "04/03/99", date(1999, 3, 4))
+	test(p, "29/02/2024", date(2024, 2, 29))
+	test(p, "3/3/2024", date(2024, 3, 3))
+	//
+	// month names, ordinals and weekdays
+	test(p, "4 March 2024", date(2024, 3, 4))
+	test(p, "4 mar 2024", date(2024, 3, 4))
+	test(p, "March 4, 2024", date(2024, 3, 4))
+	test(p, "Mar 4th, 2024", date(2024, 3, 4))
+	test(p, "Mon, Mar 4th, 2024", date(2024, 3, 4))
+	test(p, "Monday 4 March 2024", date(2024, 3, 4))
+	test(p, "2024 March 4", date(2024, 3, 4))
+	test(p, "1st Jan 2000", date(2000, 1, 1))
+	test(p, "22nd December 2023", date(2023, 12, 22))
+	test(p, "04-Mar-2024", date(2024, 3, 4))
+	//
+	// times and time zones
+	test(p, "04/03/2024 15:04",
+		time.Date(2024, 3, 4, 15, 4, 0, 0, time.UTC))
+	test(p, "04/03/2024 15:04:05.5",
+		time.Date(2024, 3, 4, 15, 4, 5, 5e8, time.UTC))
+	test(p, "2024-03-04T15:04:05Z",
+		time.Date(2024, 3, 4, 15, 4, 5, 0, time.UTC))
+	test(p, "4 March 2024 10:00 Europe/Paris",
+		time.Date(2024, 3, 4, 10, 0, 0, 0, paris))
+	test(p, "4 March 2024 Europe/Paris",
+		time.Date(2024, 3, 4, 0, 0, 0, 0, paris))
+	//
+	// Location is used for dates without a time zone
+	test(DateParser{Location: paris}, "04/03/2024",
+		time.Date(2024, 3, 4, 0, 0, 0, 0, paris))
+	test(DateParser{Location: paris}, "2024-03-04",
+		time.Date(2024, 3, 4, 0, 0, 0, 0, paris))
+	//
+	// extra layouts
+	custom := DateParser{Layouts: []string{"20060102", "02.01.2006 15h04"}}
+	test(custom, "20240304", date(2024, 3, 4))
+	test(custom, "04.03.2024 15h30",
+		time.Date(2024, 3, 4, 15, 30, 0, 0, time.UTC))
+	test(custom, "04.03.2024", date(2024, 3, 4))
+} //                                                 Test_dprs_DateParser_Parse_
+
+// go test --run Test_dprs_DateParser_Parse_errors_
+func Test_dprs_DateParser_Parse_errors_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateParser) Parse(s string) (time.Time, error)
+	//
+	test := func(p DateParser, s, field string, pos int) {
+		got, err := p.Parse(s)
+		var perr *DateParseError
+		if !errors.As(err, &perr) || !got.IsZero() ||
+			perr.Input != s || perr.Field != field || perr.Pos != pos {
+			TFailf(t, `Parse(%q) returned %v, %#v`, s, got, err)
+		}
+	}
+	var p DateParser
+	test(p, "2024", "", 0)
+	test(p, "04/03", "", 0)
+	test(p, "1/2/3/4", "", 0)
+	test(p, "March 2024", "", 0)
+	test(p, "04/03/2024?", "", 10)
+	test(p, "04_03_2024", "", 2)
+	test(p, "4 Smarch 2024", "month", 2)
+	test(p, "4 March June 2024", "month", 8)
+	test(p, "32/01/2024", "day", 0)
+	test(p, "00/01/2024", "day", 0)
+	test(p, "29/02/2023", "day", 0)
+	test(p, "31 April 2024", "day", 0)
+	test(p, "001/01/2024", "year", 0)
+	test(p, "01/00/2024", "month", 3)
+	test(p, "2024-13-01", "month", 5)
+	test(p, "01/01/20245", "year", 6)
+	test(p, "01/01/202", "year", 6)
+	test(p, "01/01/0000", "year", 6)
+	test(p, "Tue, 4 March 2024", "weekday", 0)
+	test(p, "4 March 2024 25:00", "time", 13)
+	//
+	// strict mode rejects ambiguous dates and 2-digit years
+	strict := DateParser{Strict: true}
+	test(strict, "03/04/2024", "day", 0)
+	test(strict, "4/3/2024", "day", 0)
+	test(strict, "13/04/24", "year", 6)
+	test(strict, "Mar 4, 24", "year", 7)
+	//
+	// the error describes the field
+	_, err := strict.Parse("03/04/2024")
+	TEqual(t, err.Error(), `failed parsing date "03/04/2024" at 0 (day):`+
+		` ambiguous date: 03 and 04 can be the day or month`)
+	_, err = p.Parse("29/02/2023")
+	TEqual(t, err.Error(), `failed parsing date "29/02/2023" at 0 (day):`+
+		` invalid day "29" in February 2023`)
+} //                                          Test_dprs_DateParser_Parse_errors_
+
+// go test --run Test_dprs_DateParser_Parse_order_
+func Test_dprs_DateParser_Parse_order_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateParser) Parse(s string) (time.Time, error)
+	//
+	test := func(p DateParser, s string, y int, m time.Month, d int) {
+		got, err := p.Parse(s)
+		expect := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		if err != nil || !got.Equal(expect) {
+			TFailf(t, `Parse(%q) with order %d, strict %v returned %v, %v`+
+				` instead of %v`, s, p.Order, p.Strict, got, err, expect)
+		}
+	}
+	var (
+		dmy    = DateParser{Order: DateOrderDMY}
+		mdy    = DateParser{Order: DateOrderMDY}
+		ymd    = DateParser{Order: DateOrderYMD}
+		strict = DateParser{Order: DateOrderMDY, Strict: true}
+	)
+	// ambiguous dates use the order
+	test(dmy, "03/04/2024", 2024, time.April, 3)
+	test(mdy, "03/04/2024", 2024, time.March, 4)
+	test(ymd, "24/03/04", 2024, time.March, 4)
+	//
+	// dates that are not ambiguous ignore the order
+	test(dmy, "12/31/2024", 2024, time.December, 31)
+	test(mdy, "31/12/2024", 2024, time.December, 31)
+	test(dmy, "2024-03-04", 2024, time.March, 4)
+	test(mdy, "2024/03/04", 2024, time.March, 4)
+	test(mdy, "4 March 2024", 2024, time.March, 4)
+	//
+	// strict mode accepts dates that are not ambiguous
+	test(strict, "31/12/2024", 2024, time.December, 31)
+	test(strict, "12/31/2024", 2024, time.December, 31)
+	test(strict, "05/05/2024", 2024, time.May, 5)
+	test(strict, "2024-03-04", 2024, time.March, 4)
+	test(strict, "March 4, 2024", 2024, time.March, 4)
+	test(strict, "4th March 2024", 2024, time.March, 4)
+} //                                           Test_dprs_DateParser_Parse_order_
+
+// go test --run Test_dprs_DateE_
+func Test_dprs_DateE_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateE(value interface{}) (time.Time, error)
+	//
+	expect := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	test := func(value interface{}) {
+		got, err := DateE(value)
+		if err != nil || !got.Equal(expect) {
+			TFailf(t, `DateE(%v) returned %v, %v`, value, got, err)
+		}
+	}
+	s := "4 March 2024"
+	test("2024-03-04")
+	test("04/03/2024 15:04")
+	test(&s)
+	test(time.Date(2024, 3, 4, 15, 4, 5, 0, time.UTC))
+	//
+	got, err := DateE("")
+	TTrue(t, got.IsZero())
+	TEqual(t, err, nil)
+	//
+	// errors are returned, not logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got, err = DateE("31/02/2024")
+	TTrue(t, got.IsZero())
+	var perr *DateParseError
+	TTrue(t, errors.As(err, &perr))
+	TEqual(t, perr.Field, "day")
+	//
+	var nilStr *string
+	for _, value := range []interface{}{nil, 123, nilStr} {
+		got, err = DateE(value)
+		TTrue(t, got.IsZero())
+		TTrue(t, err != nil)
+	}
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 0)
+} //                                                            Test_dprs_DateE_
+
+// end
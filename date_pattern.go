@@ -0,0 +1,419 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                               zr/[date_pattern.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # DatePattern Type:
+//   DatePattern struct
+//
+// # DatePattern Factories:
+//   DatePatternE(pattern string) (*DatePattern, error)
+//   DatePatternOf(pattern string) *DatePattern
+//
+// # Methods (ob *DatePattern)
+//   ) Format(date time.Time) string
+//   ) FormatLocale(date time.Time, loc DateLocale) string
+//   ) String() string
+//
+// # Helper Functions
+//   datePatternCase(letters, s string) string
+//   datePatternLetter(c rune) rune
+//   datePatternOffset(offset int, withColon bool) string
+//   datePatternOrdinal(n int) string
+//   datePatternPad(n, width int) string
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------------------------------------------
+// # DatePattern Type:
+
+// DatePattern is a compiled date-time format pattern. Compile a pattern
+// once with DatePatternE() or DatePatternOf() and use its Format()
+// method to format any number of dates. A DatePattern is immutable,
+// so it can be used by multiple goroutines at the same time.
+//
+// A pattern consists of fields, which are runs of the same letter,
+// and literal text. The following fields are supported. Letters
+// marked with * are case-insensitive, except that the case of the
+// letters of a name field sets the case of the name, e.g. "mmm"
+// gives "jan", "Mmm" gives "Jan" and "MMM" gives "JAN".
+//
+//	Field        Meaning                                  Example
+//	d     *      day of month                             7
+//	dd    *      day of month, 2 digits                   07
+//	ddd   *      weekday, 3 letters                       Wed
+//	dddd  *      weekday                                  Wednesday
+//	m     *      month                                    3
+//	mm    *      month, 2 digits                          03
+//	mmm   *      month name, 3 letters                    Mar
+//	mmmm  *      month name                               March
+//	y     *      year                                     2024
+//	yy    *      year, 2 digits                           24
+//	yyyy  *      year, at least 4 digits                  2024
+//	q     *      quarter                                  1
+//	j     *      day of year                              67
+//	jjj   *      day of year, 3 digits                    067
+//	w     *      ISO 8601 week number                     9
+//	ww    *      ISO 8601 week number, 2 digits           09
+//	gggg  *      ISO 8601 week-numbering year             2024
+//	h            hour, 1 to 12                            2
+//	hh           hour, 1 to 12, 2 digits                  02
+//	H            hour, 0 to 23                            14
+//	HH           hour, 0 to 23, 2 digits                  14
+//	n     *      minute                                   5
+//	nn    *      minute, 2 digits                         05
+//	s     *      second                                   9
+//	ss    *      second, 2 digits                         09
+//	f     *      fraction of a second, 1 to 9 digits      1, 12, 123...
+//	t     *      first letter of AM or PM                 p
+//	tt    *      AM or PM                                 pm
+//	z            zone offset                              +0100
+//	zz           zone offset with colon                   +01:00
+//	zzz          zone abbreviation                        CET
+//	Z            "Z" for UTC, otherwise zone offset       +01:00
+//	o     *      ordinal suffix of the preceding number   th
+//
+// Minutes use "n" because "m" is the month. Fractions of a second are
+// truncated, not rounded. The ordinal suffix follows a number field,
+// so "do" gives "7th".
+//
+// Text in single quotes is copied as it is, and two single quotes
+// give one quote. A backslash copies the next character:
+//
+//	h 'o''clock'    2 o'clock
+//	\d Mmmm         d March
+//
+// Characters other than the field letters are also copied as they
+// are, but letters should be quoted so that they're not mistaken
+// for fields.
+type DatePattern struct {
+	pattern string
+	fields  []datePatternField
+} //                                                                 DatePattern
+
+// datePatternField is a field or literal text in a DatePattern.
+type datePatternField struct {
+	letter rune   // field letter (see datePatternLetter), 0 for text
+	text   string // the field letters as written, or the literal text
+} //                                                            datePatternField
+
+// datePatternLetters maps each field letter to its maximum length.
+// Uppercase letters are listed only when they differ from lowercase.
+var datePatternLetters = map[rune]int{
+	'd': 4, 'm': 4, 'y': 4, 'q': 1, 'j': 3, 'w': 2, 'g': 4, 'h': 2, 'H': 2,
+	'n': 2, 's': 2, 'f': 9, 't': 2, 'z': 3, 'Z': 1, 'o': 1,
+} //                                                          datePatternLetters
+
+// isNumber returns true if the field is formatted as a number.
+func (field datePatternField) isNumber() bool {
+	switch field.letter {
+	case 'd', 'm':
+		return len(field.text) <= 2
+	case 'y', 'q', 'j', 'w', 'g', 'h', 'H', 'n', 's':
+		return true
+	}
+	return false
+} //                                                                    isNumber
+
+// -----------------------------------------------------------------------------
+// # DatePattern Factories:
+
+// DatePatternE compiles a date-time format pattern. See DatePattern
+// for the pattern syntax. Returns an error if a quote is not closed,
+// a field is too long, or an ordinal suffix doesn't follow a number.
+func DatePatternE(pattern string) (*DatePattern, error) {
+	var (
+		ret = &DatePattern{pattern: pattern}
+		rs  = []rune(pattern)
+		lit strings.Builder
+	)
+	flush := func() {
+		if lit.Len() > 0 {
+			ret.fields = append(ret.fields, datePatternField{text: lit.String()})
+			lit.Reset()
+		}
+	}
+	fail := func(pos int, msg string) (*DatePattern, error) {
+		return nil, fmt.Errorf("%s date pattern %q: %s at position %d",
+			EInvalid, pattern, msg, pos+1)
+	}
+	isNumber := false // true if the last field is a number
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		letter := datePatternLetter(c)
+		switch {
+		case c == '\'':
+			if i+1 < len(rs) && rs[i+1] == '\'' {
+				lit.WriteRune('\'')
+				i += 2
+				break
+			}
+			j := i + 1
+			for ; j < len(rs); j++ {
+				if rs[j] != '\'' {
+					lit.WriteRune(rs[j])
+					continue
+				}
+				if j+1 < len(rs) && rs[j+1] == '\'' {
+					lit.WriteRune('\'')
+					j++
+					continue
+				}
+				break
+			}
+			if j >= len(rs) {
+				return fail(i, "unclosed quote")
+			}
+			i = j + 1
+		case c == '\\':
+			if i+1 >= len(rs) {
+				return fail(i, "backslash at end")
+			}
+			lit.WriteRune(rs[i+1])
+			i += 2
+		case letter != 0:
+			j := i + 1
+			for j < len(rs) && datePatternLetter(rs[j]) == letter {
+				j++
+			}
+			field := datePatternField{letter: letter, text: string(rs[i:j])}
+			if j-i > datePatternLetters[letter] {
+				return fail(i, "too many letters in "+field.text)
+			}
+			if letter == 'o' && !isNumber {
+				return fail(i, "ordinal suffix without a number")
+			}
+			flush()
+			ret.fields = append(ret.fields, field)
+			isNumber = field.isNumber()
+			i = j
+			continue
+		default:
+			lit.WriteRune(c)
+			i++
+		}
+		isNumber = false
+	}
+	flush()
+	return ret, nil
+} //                                                                DatePatternE
+
+// DatePatternOf compiles a date-time format pattern like DatePatternE(),
+// but logs an error instead of returning it. If the pattern is not valid,
+// returns a pattern that formats dates as blank strings.
+func DatePatternOf(pattern string) *DatePattern {
+	ret, err := DatePatternE(pattern)
+	if err != nil {
+		mod.Error(err)
+		return &DatePattern{pattern: pattern}
+	}
+	return ret
+} //                                                               DatePatternOf
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *DatePattern)
+
+// Format returns the date formatted using the pattern. Names of months
+// and weekdays are in English. Returns a blank string if the receiver
+// is nil.
+func (ob *DatePattern) Format(date time.Time) string {
+	return ob.FormatLocale(date, dateLocaleEN)
+} //                                                                      Format
+
+// FormatLocale returns the date formatted using the pattern and the
+// names of months and weekdays, AM and PM, and the ordinal suffixes
+// of the specified locale. Returns a blank string if the receiver
+// is nil.
+func (ob *DatePattern) FormatLocale(date time.Time, loc DateLocale) string {
+	if ob == nil {
+		return ""
+	}
+	var (
+		sb   strings.Builder
+		prev = 0 // the value of the last number field
+	)
+	for _, field := range ob.fields {
+		var (
+			width = len([]rune(field.text))
+			num   = -1
+			s     = ""
+		)
+		switch field.letter {
+		case 0:
+			s = field.text
+		case 'd':
+			if width > 2 {
+				s = datePatternCase(field.text,
+					loc.weekdayName(date.Weekday(), width == 3))
+			} else {
+				num = date.Day()
+			}
+		case 'm':
+			if width > 2 {
+				s = datePatternCase(field.text,
+					loc.monthName(date.Month(), width == 3))
+			} else {
+				num = int(date.Month())
+			}
+		case 'y', 'g':
+			year := date.Year()
+			if field.letter == 'g' {
+				year, _ = date.ISOWeek()
+			}
+			num = year
+			if width == 2 {
+				num = year % 100
+				if num < 0 {
+					num = -num
+				}
+			}
+		case 'q':
+			num = (int(date.Month())-1)/3 + 1
+		case 'j':
+			num = date.YearDay()
+		case 'w':
+			_, num = date.ISOWeek()
+		case 'h':
+			num = date.Hour() % 12
+			if num == 0 {
+				num = 12
+			}
+		case 'H':
+			num = date.Hour()
+		case 'n':
+			num = date.Minute()
+		case 's':
+			num = date.Second()
+		case 'f':
+			s = fmt.Sprintf("%09d", date.Nanosecond())[:width]
+		case 't':
+			s = loc.AM
+			if date.Hour() >= 12 {
+				s = loc.PM
+			}
+			if rs := []rune(s); width == 1 && len(rs) > 1 {
+				s = string(rs[:1])
+			}
+			s = datePatternCase(field.text, s)
+		case 'z', 'Z':
+			name, offset := date.Zone()
+			switch {
+			case width == 3:
+				s = name
+			case field.letter == 'Z' && offset == 0:
+				s = "Z"
+			default:
+				s = datePatternOffset(offset, width == 2 || field.letter == 'Z')
+			}
+		case 'o':
+			s = datePatternCase(field.text, loc.ordinal(prev))
+		}
+		if num != -1 {
+			prev = num
+			s = datePatternPad(num, width)
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+} //                                                                FormatLocale
+
+// String returns the source pattern and implements the fmt.Stringer
+// interface.
+func (ob *DatePattern) String() string {
+	if ob == nil {
+		return ""
+	}
+	return ob.pattern
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// datePatternCase changes the case of s to match the case of the
+// field letters: all uppercase, all lowercase, or a capital first
+// letter followed by lowercase.
+func datePatternCase(letters, s string) string {
+	switch {
+	case letters == strings.ToLower(letters):
+		return strings.ToLower(s)
+	case letters == strings.ToUpper(letters):
+		return strings.ToUpper(s)
+	case s == "":
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + strings.ToLower(s[size:])
+} //                                                             datePatternCase
+
+// datePatternLetter returns the field letter of c: c itself if it is
+// a field letter, the lowercase of c if only that is a field letter,
+// or 0 if c is not a field letter.
+func datePatternLetter(c rune) rune {
+	if _, found := datePatternLetters[c]; found {
+		return c
+	}
+	if c >= 'A' && c <= 'Z' {
+		if _, found := datePatternLetters[c-'A'+'a']; found {
+			return c - 'A' + 'a'
+		}
+	}
+	return 0
+} //                                                           datePatternLetter
+
+// datePatternOffset returns a zone offset in seconds formatted as
+// "+hhmm", or as "+hh:mm" if withColon is true.
+func datePatternOffset(offset int, withColon bool) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	sep := ""
+	if withColon {
+		sep = ":"
+	}
+	return fmt.Sprintf("%c%02d%s%02d", sign, offset/3600, sep, offset%3600/60)
+} //                                                           datePatternOffset
+
+// datePatternOrdinal returns the English ordinal suffix of n,
+// e.g. "st" for 1, "nd" for 2, "rd" for 3 and "th" for 11.
+func datePatternOrdinal(n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+} //                                                          datePatternOrdinal
+
+// datePatternPad returns n in decimal, padded with leading zeros to
+// the specified width. A single letter field is never padded.
+func datePatternPad(n, width int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + datePatternPad(-n, width)
+	}
+	for len(s) < width && width > 1 {
+		s = "0" + s
+	}
+	return s
+} //                                                              datePatternPad
+
+// end
@@ -0,0 +1,230 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                          zr/[date_pattern_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # DatePattern Factories:
+//   Test_dpat_DatePatternE_
+//   Test_dpat_DatePatternOf_
+//
+// # Methods (ob *DatePattern)
+//   Test_dpat_DatePattern_Format_
+//   Test_dpat_DatePattern_Format_zones_
+//   Test_dpat_DatePattern_String_
+
+//  to test all items in date_pattern.go use:
+//      go test --run Test_dpat_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// # DatePattern Factories:
+
+// go test --run Test_dpat_DatePatternE_
+func Test_dpat_DatePatternE_(t *testing.T) {
+	TBegin(t)
+	//
+	// DatePatternE(pattern string) (*DatePattern, error)
+	//
+	test := func(pattern, expectErr string) {
+		got, err := DatePatternE(pattern)
+		if expectErr == "" {
+			if err != nil || got == nil {
+				TFailf(t, `DatePatternE(%q) failed: %v`, pattern, err)
+			}
+			return
+		}
+		if got != nil || err == nil ||
+			!strings.Contains(err.Error(), expectErr) {
+			TFailf(t, `DatePatternE(%q) returned %v, %v`, pattern, got, err)
+		}
+	}
+	test("", "")
+	test("yyyy-mm-dd HH:nn:ss.fffffffff zz", "")
+	test("'it''s' d", "")
+	test("''", "")
+	test("'unclosed", "unclosed quote at position 1")
+	test("d 'it''s", "unclosed quote at position 3")
+	test(`yyyy\`, "backslash at end at position 5")
+	test("ddddd", "too many letters in ddddd at position 1")
+	test("mm/yyyyy", "too many letters in yyyyy at position 4")
+	test("hhh", "too many letters")
+	test("ffffffffff", "too many letters")
+	test("zzzz", "too many letters")
+	test("ZZ", "too many letters")
+	test("qq", "too many letters")
+	test("o", "ordinal suffix without a number at position 1")
+	test("d o", "ordinal suffix without a number at position 3")
+	test("mmmo", "ordinal suffix without a number")
+	test("tto", "ordinal suffix without a number")
+	test("'d'o", "ordinal suffix without a number")
+} //                                                     Test_dpat_DatePatternE_
+
+// go test --run Test_dpat_DatePatternOf_
+func Test_dpat_DatePatternOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// DatePatternOf(pattern string) *DatePattern
+	//
+	date := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+	TEqual(t, DatePatternOf("d/m/yyyy").Format(date), "7/3/2024")
+	//
+	DisableErrors()
+	ec1 := GetErrorCount()
+	pat := DatePatternOf("'d/m/yyyy")
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 1)
+	TEqual(t, pat.Format(date), "")
+	TEqual(t, pat.String(), "'d/m/yyyy")
+} //                                                    Test_dpat_DatePatternOf_
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *DatePattern)
+
+// go test --run Test_dpat_DatePattern_Format_
+func Test_dpat_DatePattern_Format_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *DatePattern) Format(date time.Time) string
+	//
+	var (
+		date1 = time.Date(2024, 3, 7, 14, 5, 9, 123456789, time.UTC)
+		date2 = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		date3 = time.Date(987, 12, 31, 12, 30, 0, 0, time.UTC)
+	)
+	test := func(date time.Time, pattern, expect string) {
+		got := DatePatternOf(pattern).Format(date)
+		if got != expect {
+			TFailf(t, `Format(%q) returned %q instead of %q`,
+				pattern, got, expect)
+		}
+	}
+	// day, month and year
+	test(date1, "d dd D DD", "7 07 7 07")
+	test(date1, "m mm M MM", "3 03 3 03")
+	test(date1, "mmm Mmm MMM mmmm Mmmm MMMM",
+		"mar Mar MAR march March MARCH")
+	test(date1, "ddd Ddd DDD dddd Dddd DDDD",
+		"thu Thu THU thursday Thursday THURSDAY")
+	test(date1, "y yy yyy yyyy YYYY", "2024 24 2024 2024 2024")
+	test(date3, "y yy yyy yyyy", "987 87 987 0987")
+	test(date1, "yyyymmdd", "20240307")
+	test(date1, "Dddd, d Mmmm yyyy", "Thursday, 7 March 2024")
+	//
+	// quarter, day of year and ISO week
+	test(date1, "q Q", "1 1")
+	test(date3, "'Q'q yyyy", "Q4 0987")
+	test(date1, "j jj jjj", "67 67 067")
+	test(date2, "jjj", "001")
+	test(date3, "jjj", "365")
+	test(date1, "w ww gggg", "10 10 2024")
+	test(date2, "gggg-'W'ww", "2020-W53")
+	test(date3, "gg-'W'ww", "88-W01")
+	//
+	// time
+	test(date1, "h hh H HH", "2 02 14 14")
+	test(date2, "h hh H HH", "12 12 0 00")
+	test(date3, "h:nn tt", "12:30 pm")
+	test(date1, "n nn N NN s ss S SS", "5 05 5 05 9 09 9 09")
+	test(date1, "h:nn:ss t T tt Tt TT", "2:05:09 p P pm Pm PM")
+	test(date2, "h:nn tt", "12:00 am")
+	test(date1, "ss.f ss.ff ss.fff ss.ffffff ss.fffffffff",
+		"09.1 09.12 09.123 09.123456 09.123456789")
+	test(date2, "ss.fff", "00.000")
+	//
+	// ordinals
+	test(date1, "do Mmmm", "7th March")
+	test(date2, "do DO", "1st 1ST")
+	test(date3, "do 'of' Mmmm", "31st of December")
+	test(date1, "wo 'week', jo 'day', qo 'quarter'",
+		"10th week, 67th day, 1st quarter")
+	for day, expect := range map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th",
+		13: "13th", 21: "21st", 22: "22nd", 23: "23rd", 30: "30th",
+	} {
+		test(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), "do", expect)
+	}
+	test(time.Date(2111, 1, 1, 0, 0, 0, 0, time.UTC), "yo", "2111th")
+	test(time.Date(2102, 1, 1, 0, 0, 0, 0, time.UTC), "yo", "2102nd")
+	//
+	// literal text
+	test(date1, "'Today is' dddd", "Today is thursday")
+	test(date1, "h 'o''clock'", "2 o'clock")
+	test(date1, "''h''", "'2'")
+	test(date1, `\d\a\y d`, "day 7")
+	test(date1, "yyyy年m月d日", "2024年3月7日")
+	test(date1, "[d] #i", "[7] #i")
+	test(date1, "", "")
+	//
+	// nil receiver
+	var pat *DatePattern
+	TEqual(t, pat.Format(date1), "")
+	//
+	// concurrent use
+	pat = DatePatternOf("yyyy-mm-dd HH:nn")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			date := time.Date(2024, 1, 1+i, i, i, 0, 0, time.UTC)
+			got := pat.Format(date)
+			expect := date.Format("2006-01-02 15:04")
+			if got != expect {
+				TFailf(t, `Format() returned %q instead of %q`, got, expect)
+			}
+		}(i)
+	}
+	wg.Wait()
+} //                                               Test_dpat_DatePattern_Format_
+
+// go test --run Test_dpat_DatePattern_Format_zones_
+func Test_dpat_DatePattern_Format_zones_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *DatePattern) Format(date time.Time) string
+	//
+	test := func(date time.Time, pattern, expect string) {
+		got := DatePatternOf(pattern).Format(date)
+		if got != expect {
+			TFailf(t, `Format(%q) returned %q instead of %q`,
+				pattern, got, expect)
+		}
+	}
+	var (
+		cet  = time.FixedZone("CET", 60*60)
+		nst  = time.FixedZone("NST", -(3*60*60 + 30*60))
+		utc  = time.Date(2024, 3, 7, 14, 5, 9, 0, time.UTC)
+		west = time.Date(2024, 3, 7, 14, 5, 9, 0, nst)
+	)
+	test(utc, "z zz zzz Z", "+0000 +00:00 UTC Z")
+	test(utc.In(cet), "z zz zzz Z", "+0100 +01:00 CET +01:00")
+	test(west, "z zz zzz Z", "-0330 -03:30 NST -03:30")
+	test(utc.In(cet), "yyyy-mm-dd'T'HH:nn:ssZ", "2024-03-07T15:05:09+01:00")
+	test(utc, "yyyy-mm-dd'T'HH:nn:ssZ", "2024-03-07T14:05:09Z")
+} //                                         Test_dpat_DatePattern_Format_zones_
+
+// go test --run Test_dpat_DatePattern_String_
+func Test_dpat_DatePattern_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *DatePattern) String() string
+	//
+	TEqual(t, DatePatternOf("d 'of' Mmmm").String(), "d 'of' Mmmm")
+	var pat *DatePattern
+	TEqual(t, pat.String(), "")
+} //                                               Test_dpat_DatePattern_String_
+
+// end
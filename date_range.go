@@ -0,0 +1,364 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                 zr/[date_range.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Date Units
+//   DateUnit int
+//   DateUnitDay
+//   DateUnitWeek
+//   DateUnitMonth
+//   DateUnitQuarter
+//   DateUnitYear
+//   (unit DateUnit) String() string
+//
+// # Methods (ob DateRange)
+//   ) Contains(t time.Time) bool
+//   ) Days() int
+//   ) Duration() time.Duration
+//   ) Each(unit DateUnit, fn func(period DateRange) bool)
+//   ) Intersect(other DateRange) DateRange
+//   ) Overlaps(other DateRange) bool
+//   ) Split(unit DateUnit) []DateRange
+//   ) Subtract(other DateRange) []DateRange
+//   ) Union(other DateRange) []DateRange
+//
+// # DateRangeSet
+//   DateRangeSet struct
+//   DateRangeSetOf(ranges ...DateRange) DateRangeSet
+//   (ob *DateRangeSet) Add(ranges ...DateRange)
+//   (ob DateRangeSet) Contains(t time.Time) bool
+//   (ob DateRangeSet) Duration() time.Duration
+//   (ob DateRangeSet) Intersect(other DateRange) DateRangeSet
+//   (ob DateRangeSet) Len() int
+//   (ob DateRangeSet) Ranges() []DateRange
+//   (ob DateRangeSet) String() string
+//   (ob *DateRangeSet) Subtract(ranges ...DateRange)
+//
+// # Private Functions
+//   (ob DateRange) isEmpty() bool
+//   dateRangeJoins(a, b DateRange) bool
+//   dateRangeMerge(ranges []DateRange) []DateRange
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// # Date Units
+
+// DateUnit specifies a calendar period, such as a week or a month,
+// used to step through a DateRange.
+type DateUnit int
+
+const (
+	// DateUnitDay is a calendar day, starting at midnight.
+	DateUnitDay DateUnit = iota
+
+	// DateUnitWeek is a calendar week, starting on Monday.
+	DateUnitWeek
+
+	// DateUnitMonth is a calendar month.
+	DateUnitMonth
+
+	// DateUnitQuarter is a calendar quarter:
+	// January to March, April to June, etc.
+	DateUnitQuarter
+
+	// DateUnitYear is a calendar year.
+	DateUnitYear
+)
+
+// String returns the name of the date unit, e.g. "month"
+// and implements the fmt.Stringer interface.
+func (unit DateUnit) String() string {
+	switch unit {
+	case DateUnitDay:
+		return "day"
+	case DateUnitWeek:
+		return "week"
+	case DateUnitMonth:
+		return "month"
+	case DateUnitQuarter:
+		return "quarter"
+	case DateUnitYear:
+		return "year"
+	}
+	return "DateUnit(" + String(int(unit)) + ")"
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Methods (ob DateRange)
+
+// Contains returns true if the time 't' falls within the date range.
+func (ob DateRange) Contains(t time.Time) bool {
+	return !ob.isEmpty() && !t.Before(ob.From) && !t.After(ob.To)
+} //                                                                    Contains
+
+// Days returns the number of calendar days that the date range touches,
+// counting both the first and the last day, or zero if it is empty.
+func (ob DateRange) Days() int {
+	if ob.isEmpty() {
+		return 0
+	}
+	var (
+		y1, m1, d1 = ob.From.Date()
+		y2, m2, d2 = ob.To.Date()
+		from       = time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC).Unix()
+		to         = time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Unix()
+	)
+	return int((to-from)/(24*60*60)) + 1
+} //                                                                        Days
+
+// Duration returns the length of the date range, including its last
+// nanosecond, so that a range that covers a whole day returns 24 hours.
+// Returns zero if the range is empty. Ranges longer than about 292 years
+// return the maximum time.Duration.
+func (ob DateRange) Duration() time.Duration {
+	if ob.isEmpty() {
+		return 0
+	}
+	ret := ob.To.Sub(ob.From)
+	if ret < math.MaxInt64 {
+		ret++
+	}
+	return ret
+} //                                                                    Duration
+
+// Each calls 'fn' for every calendar day, week, month, quarter or year
+// (depending on 'unit') that overlaps the date range, in chronological
+// order. The first and last periods are clipped to the date range.
+// Periods start at midnight in the time zone of ob.From.
+// Iteration stops early if 'fn' returns false.
+func (ob DateRange) Each(unit DateUnit, fn func(period DateRange) bool) {
+	if unit < DateUnitDay || unit > DateUnitYear {
+		mod.Error(EInvalidArg, "^unit", ":", unit)
+		return
+	}
+	if ob.isEmpty() || fn == nil {
+		return
+	}
+	for start := dateUnitStart(unit, ob.From); !start.After(ob.To); {
+		next := dateUnitAdd(unit, start, 1)
+		period := DateRange{From: start, To: next.Add(-1)}
+		if period.From.Before(ob.From) {
+			period.From = ob.From
+		}
+		if period.To.After(ob.To) {
+			period.To = ob.To
+		}
+		if !fn(period) {
+			break
+		}
+		start = next
+	}
+} //                                                                        Each
+
+// Intersect returns the part of the date range that is also
+// within 'other', or a null DateRange if they don't overlap.
+func (ob DateRange) Intersect(other DateRange) DateRange {
+	if !ob.Overlaps(other) {
+		return DateRange{}
+	}
+	ret := ob
+	if other.From.After(ret.From) {
+		ret.From = other.From
+	}
+	if other.To.Before(ret.To) {
+		ret.To = other.To
+	}
+	return ret
+} //                                                                   Intersect
+
+// Overlaps returns true if the date range and 'other'
+// have at least one moment in time in common.
+func (ob DateRange) Overlaps(other DateRange) bool {
+	return !ob.isEmpty() && !other.isEmpty() &&
+		!ob.To.Before(other.From) && !other.To.Before(ob.From)
+} //                                                                    Overlaps
+
+// Split returns the calendar days, weeks, months, quarters or years
+// (depending on 'unit') that overlap the date range, clipped to the
+// range. See Each for details.
+func (ob DateRange) Split(unit DateUnit) []DateRange {
+	var ret []DateRange
+	ob.Each(unit, func(period DateRange) bool {
+		ret = append(ret, period)
+		return true
+	})
+	return ret
+} //                                                                       Split
+
+// Subtract returns the parts of the date range that are not within
+// 'other': none if 'other' covers the whole range, two if 'other'
+// falls in the middle of it, otherwise one.
+func (ob DateRange) Subtract(other DateRange) []DateRange {
+	if ob.isEmpty() {
+		return nil
+	}
+	if !ob.Overlaps(other) {
+		return []DateRange{ob}
+	}
+	var ret []DateRange
+	if other.From.After(ob.From) {
+		ret = append(ret, DateRange{From: ob.From, To: other.From.Add(-1)})
+	}
+	if other.To.Before(ob.To) {
+		ret = append(ret, DateRange{From: other.To.Add(1), To: ob.To})
+	}
+	return ret
+} //                                                                    Subtract
+
+// Union returns a single date range that covers both the date range and
+// 'other' if they overlap or adjoin each other, i.e. when one starts on
+// the nanosecond after the other ends. Otherwise returns both ranges in
+// chronological order. Empty ranges are omitted.
+func (ob DateRange) Union(other DateRange) []DateRange {
+	return dateRangeMerge([]DateRange{ob, other})
+} //                                                                       Union
+
+// -----------------------------------------------------------------------------
+// # DateRangeSet
+
+// DateRangeSet holds a set of moments in time as a list of date
+// ranges that are kept sorted, with no empty, overlapping or
+// adjoining ranges. The zero value is an empty set.
+type DateRangeSet struct {
+	ranges []DateRange
+} //                                                                DateRangeSet
+
+// DateRangeSetOf creates a DateRangeSet from the specified date ranges,
+// merging ranges that overlap or adjoin and omitting empty ranges.
+func DateRangeSetOf(ranges ...DateRange) DateRangeSet {
+	var ret DateRangeSet
+	ret.Add(ranges...)
+	return ret
+} //                                                              DateRangeSetOf
+
+// Add adds the specified date ranges to the set.
+func (ob *DateRangeSet) Add(ranges ...DateRange) {
+	all := make([]DateRange, 0, len(ob.ranges)+len(ranges))
+	all = append(all, ob.ranges...)
+	all = append(all, ranges...)
+	ob.ranges = dateRangeMerge(all)
+} //                                                                         Add
+
+// Contains returns true if the time 't' falls within any of the ranges.
+func (ob DateRangeSet) Contains(t time.Time) bool {
+	i := sort.Search(len(ob.ranges), func(i int) bool {
+		return !ob.ranges[i].To.Before(t)
+	})
+	return i < len(ob.ranges) && ob.ranges[i].Contains(t)
+} //                                                                    Contains
+
+// Duration returns the total length of all the ranges in the set.
+func (ob DateRangeSet) Duration() time.Duration {
+	var ret time.Duration
+	for _, rng := range ob.ranges {
+		d := rng.Duration()
+		if ret > math.MaxInt64-d {
+			return math.MaxInt64
+		}
+		ret += d
+	}
+	return ret
+} //                                                                    Duration
+
+// Intersect returns a new set with the parts of
+// the set's ranges that are within 'other'.
+func (ob DateRangeSet) Intersect(other DateRange) DateRangeSet {
+	var ret DateRangeSet
+	for _, rng := range ob.ranges {
+		if part := rng.Intersect(other); !part.IsNull() {
+			ret.ranges = append(ret.ranges, part)
+		}
+	}
+	return ret
+} //                                                                   Intersect
+
+// Len returns the number of separate ranges in the set.
+func (ob DateRangeSet) Len() int {
+	return len(ob.ranges)
+} //                                                                         Len
+
+// Ranges returns a copy of the set's ranges in chronological order.
+func (ob DateRangeSet) Ranges() []DateRange {
+	if len(ob.ranges) == 0 {
+		return nil
+	}
+	return append([]DateRange(nil), ob.ranges...)
+} //                                                                      Ranges
+
+// String returns the set's ranges separated by commas,
+// and implements the fmt.Stringer interface.
+func (ob DateRangeSet) String() string {
+	a := make([]string, len(ob.ranges))
+	for i, rng := range ob.ranges {
+		a[i] = rng.String()
+	}
+	return strings.Join(a, ", ")
+} //                                                                      String
+
+// Subtract removes the specified date ranges from the set.
+func (ob *DateRangeSet) Subtract(ranges ...DateRange) {
+	ret := ob.ranges
+	for _, other := range ranges {
+		var parts []DateRange
+		for _, rng := range ret {
+			parts = append(parts, rng.Subtract(other)...)
+		}
+		ret = parts
+	}
+	ob.ranges = ret
+} //                                                                    Subtract
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// isEmpty returns true if the date range is null or ends before it starts.
+func (ob DateRange) isEmpty() bool {
+	return ob.IsNull() || ob.To.Before(ob.From)
+} //                                                                     isEmpty
+
+// dateRangeJoins returns true if 'b' starts no later than
+// the nanosecond after 'a' ends. Both must not be empty.
+func dateRangeJoins(a, b DateRange) bool {
+	return !b.From.After(a.To.Add(1))
+} //                                                              dateRangeJoins
+
+// dateRangeMerge sorts the specified date ranges and merges the ranges
+// that overlap or adjoin each other, omitting empty ranges. It returns
+// a new slice and leaves 'ranges' unchanged.
+func dateRangeMerge(ranges []DateRange) []DateRange {
+	var ret []DateRange
+	for _, rng := range ranges {
+		if !rng.isEmpty() {
+			ret = append(ret, rng)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].From.Before(ret[j].From)
+	})
+	n := 0
+	for _, rng := range ret {
+		if n > 0 && dateRangeJoins(ret[n-1], rng) {
+			if rng.To.After(ret[n-1].To) {
+				ret[n-1].To = rng.To
+			}
+			continue
+		}
+		ret[n] = rng
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return ret[:n]
+} //                                                              dateRangeMerge
+
+// end
@@ -0,0 +1,417 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                            zr/[date_range_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Date Units
+//   Test_drng_DateUnit_String_
+//
+// # Methods (ob DateRange)
+//   Test_drng_DateRange_Contains_
+//   Test_drng_DateRange_Days_
+//   Test_drng_DateRange_Duration_
+//   Test_drng_DateRange_Each_
+//   Test_drng_DateRange_Intersect_
+//   Test_drng_DateRange_Overlaps_
+//   Test_drng_DateRange_Split_
+//   Test_drng_DateRange_Subtract_
+//   Test_drng_DateRange_Union_
+//
+// # DateRangeSet
+//   Test_drng_DateRangeSet_
+//   Test_drng_DateRangeSet_Subtract_
+
+//  to test all items in date_range.go use:
+//      go test --run Test_drng_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// drngRange returns the DateRange of a string like "2024-01-01 2024-01-31",
+// or of a single date, using DateRangeOf.
+func drngRange(s string) DateRange {
+	return DateRangeOf(strings.Replace(s, " ", " to ", 1))
+} //                                                                   drngRange
+
+// drngString returns the specified date ranges as a string.
+func drngString(ranges []DateRange) string {
+	return DateRangeSet{ranges: ranges}.String()
+} //                                                                  drngString
+
+// -----------------------------------------------------------------------------
+// # Date Units
+
+// go test --run Test_drng_DateUnit_String_
+func Test_drng_DateUnit_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (unit DateUnit) String() string
+	//
+	TEqual(t, DateUnitDay.String(), "day")
+	TEqual(t, DateUnitWeek.String(), "week")
+	TEqual(t, DateUnitMonth.String(), "month")
+	TEqual(t, DateUnitQuarter.String(), "quarter")
+	TEqual(t, DateUnitYear.String(), "year")
+	TEqual(t, DateUnit(9).String(), "DateUnit(9)")
+} //                                                  Test_drng_DateUnit_String_
+
+// -----------------------------------------------------------------------------
+// # Methods (ob DateRange)
+
+// go test --run Test_drng_DateRange_Contains_
+func Test_drng_DateRange_Contains_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Contains(t time.Time) bool
+	//
+	rng := drngRange("2024-03-01 2024-03-31")
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	TTrue(t, rng.Contains(at(1, 0)))
+	TTrue(t, rng.Contains(at(15, 12)))
+	TTrue(t, rng.Contains(rng.To))
+	TTrue(t, rng.Contains(at(31, 12).In(time.FixedZone("", 2*60*60))))
+	TFalse(t, rng.Contains(rng.From.Add(-1)))
+	TFalse(t, rng.Contains(rng.To.Add(1)))
+	TFalse(t, rng.Contains(at(32, 0)))
+	//
+	// empty ranges contain nothing
+	TFalse(t, DateRange{}.Contains(time.Time{}))
+	TFalse(t, DateRange{From: rng.To, To: rng.From}.Contains(at(15, 0)))
+} //                                               Test_drng_DateRange_Contains_
+
+// go test --run Test_drng_DateRange_Days_
+func Test_drng_DateRange_Days_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Days() int
+	//
+	TEqual(t, drngRange("2024-03-07").Days(), 1)
+	TEqual(t, drngRange("2024-02-01 2024-02-29").Days(), 29)
+	TEqual(t, drngRange("2024").Days(), 366)
+	TEqual(t, drngRange("1799 2005").Days(), 75605)
+	TEqual(t, DateRange{}.Days(), 0)
+	//
+	// partial days count as whole days
+	TEqual(t, DateRange{
+		From: time.Date(2024, 3, 7, 23, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 8, 1, 0, 0, 0, time.UTC),
+	}.Days(), 2)
+} //                                                   Test_drng_DateRange_Days_
+
+// go test --run Test_drng_DateRange_Duration_
+func Test_drng_DateRange_Duration_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Duration() time.Duration
+	//
+	TEqual(t, drngRange("2024-03-07").Duration(), 24*time.Hour)
+	TEqual(t, drngRange("2024-03-01 2024-03-31").Duration(), 31*24*time.Hour)
+	TEqual(t, drngRange("1600 2000").Duration(),
+		time.Duration(math.MaxInt64))
+	TEqual(t, DateRange{}.Duration(), time.Duration(0))
+	//
+	from := time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)
+	TEqual(t, DateRange{From: from, To: from}.Duration(), time.Duration(1))
+	TEqual(t, DateRange{From: from, To: from.Add(-1)}.Duration(),
+		time.Duration(0))
+} //                                               Test_drng_DateRange_Duration_
+
+// go test --run Test_drng_DateRange_Each_
+func Test_drng_DateRange_Each_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Each(unit DateUnit, fn func(period DateRange) bool)
+	//
+	// stops when fn returns false
+	var got []string
+	drngRange("2024-01-01 2024-12-31").Each(DateUnitMonth,
+		func(period DateRange) bool {
+			got = append(got, period.String())
+			return len(got) < 3
+		})
+	TEqual(t, strings.Join(got, ", "), "2024-01-01 2024-01-31, "+
+		"2024-02-01 2024-02-29, 2024-03-01 2024-03-31")
+	//
+	// periods are contiguous and cover the whole range
+	rng := DateRange{
+		From: time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC),
+		To:   time.Date(2024, 2, 2, 17, 0, 0, 0, time.UTC),
+	}
+	var prev DateRange
+	rng.Each(DateUnitWeek, func(period DateRange) bool {
+		if prev.IsNull() {
+			TEqual(t, period.From, rng.From)
+		} else {
+			TEqual(t, period.From, prev.To.Add(1))
+		}
+		prev = period
+		return true
+	})
+	TEqual(t, prev.To, rng.To)
+	//
+	// invalid units are logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	rng.Each(DateUnit(-1), func(DateRange) bool { return true })
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 1)
+	//
+	// nothing happens on empty ranges or a nil fn
+	DateRange{}.Each(DateUnitDay, func(DateRange) bool {
+		TFailf(t, `Each() called fn on an empty range`)
+		return true
+	})
+	rng.Each(DateUnitDay, nil)
+} //                                                   Test_drng_DateRange_Each_
+
+// go test --run Test_drng_DateRange_Intersect_
+func Test_drng_DateRange_Intersect_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Intersect(other DateRange) DateRange
+	//
+	test := func(a, b, expect string) {
+		got := drngRange(a).Intersect(drngRange(b))
+		if got.String() != expect {
+			TFailf(t, `%q.Intersect(%q) returned %q instead of %q`,
+				a, b, got.String(), expect)
+		}
+	}
+	test("2024-01-01 2024-01-31", "2024-01-15 2024-02-15",
+		"2024-01-15 2024-01-31")
+	test("2024-01-15 2024-02-15", "2024-01-01 2024-01-31",
+		"2024-01-15 2024-01-31")
+	test("2024", "2024-03-07", "2024-03-07 2024-03-07")
+	test("2024-01-31", "2024-01-01 2024-01-31", "2024-01-31 2024-01-31")
+	//
+	TTrue(t, drngRange("2024-01").Intersect(drngRange("2024-02")).IsNull())
+	TTrue(t, drngRange("2024").Intersect(DateRange{}).IsNull())
+} //                                              Test_drng_DateRange_Intersect_
+
+// go test --run Test_drng_DateRange_Overlaps_
+func Test_drng_DateRange_Overlaps_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Overlaps(other DateRange) bool
+	//
+	test := func(a, b string, expect bool) {
+		got := drngRange(a).Overlaps(drngRange(b))
+		if got != expect {
+			TFailf(t, `%q.Overlaps(%q) returned %v`, a, b, got)
+		}
+	}
+	test("2024-01-01 2024-01-31", "2024-01-31 2024-02-15", true)
+	test("2024-01-01 2024-01-31", "2023-12-01 2024-01-01", true)
+	test("2024", "2024-06-15", true)
+	test("2024-06-15", "2024", true)
+	test("2024-01", "2024-02", false)
+	test("2024-02", "2024-01", false)
+	TFalse(t, drngRange("2024").Overlaps(DateRange{}))
+	TFalse(t, DateRange{}.Overlaps(DateRange{}))
+} //                                               Test_drng_DateRange_Overlaps_
+
+// go test --run Test_drng_DateRange_Split_
+func Test_drng_DateRange_Split_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Split(unit DateUnit) []DateRange
+	//
+	test := func(rng string, unit DateUnit, expect string) {
+		got := drngString(drngRange(rng).Split(unit))
+		if got != expect {
+			TFailf(t, `%q.Split(%v) returned %q instead of %q`,
+				rng, unit, got, expect)
+		}
+	}
+	test("2024-02-27 2024-03-02", DateUnitDay, "2024-02-27 2024-02-27, "+
+		"2024-02-28 2024-02-28, 2024-02-29 2024-02-29, "+
+		"2024-03-01 2024-03-01, 2024-03-02 2024-03-02")
+	// 2024-08-14 is a Wednesday
+	test("2024-08-14 2024-08-31", DateUnitWeek, "2024-08-14 2024-08-18, "+
+		"2024-08-19 2024-08-25, 2024-08-26 2024-08-31")
+	test("2024-01-15 2024-03-10", DateUnitMonth, "2024-01-15 2024-01-31, "+
+		"2024-02-01 2024-02-29, 2024-03-01 2024-03-10")
+	test("2023-11-20 2024-05-01", DateUnitQuarter, "2023-11-20 2023-12-31, "+
+		"2024-01-01 2024-03-31, 2024-04-01 2024-05-01")
+	test("2023 2024", DateUnitYear, "2023-01-01 2023-12-31, "+
+		"2024-01-01 2024-12-31")
+	test("2024-03-07", DateUnitYear, "2024-03-07 2024-03-07")
+	//
+	TEqual(t, len(drngRange("2024").Split(DateUnitDay)), 366)
+	TEqual(t, len(drngRange("2024").Split(DateUnitWeek)), 53)
+	TEqual(t, len(DateRange{}.Split(DateUnitDay)), 0)
+	//
+	// periods start at midnight in the range's time zone,
+	// even when days are shorter due to daylight saving time
+	loc, err := time.LoadLocation("Europe/London")
+	if err == nil {
+		rng := DateRange{
+			From: time.Date(2024, 3, 30, 12, 0, 0, 0, loc),
+			To:   time.Date(2024, 4, 1, 12, 0, 0, 0, loc),
+		}
+		a := rng.Split(DateUnitDay)
+		TEqual(t, len(a), 3)
+		if len(a) == 3 {
+			TEqual(t, a[1].From, time.Date(2024, 3, 31, 0, 0, 0, 0, loc))
+			TEqual(t, a[1].Duration(), 23*time.Hour)
+		}
+	}
+} //                                                  Test_drng_DateRange_Split_
+
+// go test --run Test_drng_DateRange_Subtract_
+func Test_drng_DateRange_Subtract_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Subtract(other DateRange) []DateRange
+	//
+	test := func(a, b, expect string) {
+		got := drngString(drngRange(a).Subtract(drngRange(b)))
+		if got != expect {
+			TFailf(t, `%q.Subtract(%q) returned %q instead of %q`,
+				a, b, got, expect)
+		}
+	}
+	// in the middle
+	test("2024-01", "2024-01-10 2024-01-20",
+		"2024-01-01 2024-01-09, 2024-01-21 2024-01-31")
+	// at the start or end
+	test("2024-01", "2023-12-15 2024-01-10", "2024-01-11 2024-01-31")
+	test("2024-01", "2024-01-31 2024-02-10", "2024-01-01 2024-01-30")
+	// everything or nothing
+	test("2024-01", "2024", "")
+	test("2024-01", "2024-01", "")
+	test("2024-01", "2024-02", "2024-01-01 2024-01-31")
+	test("2024-01", "xyz", "2024-01-01 2024-01-31")
+	TEqual(t, len(DateRange{}.Subtract(drngRange("2024"))), 0)
+	//
+	// the remaining parts end and start next to the subtracted range
+	var (
+		rng   = drngRange("2024-01")
+		other = drngRange("2024-01-10 2024-01-20")
+		parts = rng.Subtract(other)
+	)
+	TEqual(t, parts[0].To.Add(1), other.From)
+	TEqual(t, parts[1].From, other.To.Add(1))
+} //                                               Test_drng_DateRange_Subtract_
+
+// go test --run Test_drng_DateRange_Union_
+func Test_drng_DateRange_Union_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) Union(other DateRange) []DateRange
+	//
+	test := func(a, b, expect string) {
+		got := drngString(drngRange(a).Union(drngRange(b)))
+		if got != expect {
+			TFailf(t, `%q.Union(%q) returned %q instead of %q`,
+				a, b, got, expect)
+		}
+	}
+	test("2024-01-01 2024-01-20", "2024-01-10 2024-02-10",
+		"2024-01-01 2024-02-10")
+	test("2024", "2024-03-07", "2024-01-01 2024-12-31")
+	// adjoining ranges are merged
+	test("2024-01", "2024-02", "2024-01-01 2024-02-29")
+	test("2024-02", "2024-01", "2024-01-01 2024-02-29")
+	// separate ranges are sorted
+	test("2024-03", "2024-01", "2024-01-01 2024-01-31, 2024-03-01 2024-03-31")
+	// empty ranges are omitted
+	test("2024-03", "xyz", "2024-03-01 2024-03-31")
+	test("xyz", "xyz", "")
+} //                                                  Test_drng_DateRange_Union_
+
+// -----------------------------------------------------------------------------
+// # DateRangeSet
+
+// go test --run Test_drng_DateRangeSet_
+func Test_drng_DateRangeSet_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateRangeSetOf(ranges ...DateRange) DateRangeSet
+	// (ob *DateRangeSet) Add(ranges ...DateRange)
+	// (ob DateRangeSet) Contains(t time.Time) bool
+	// (ob DateRangeSet) Duration() time.Duration
+	// (ob DateRangeSet) Intersect(other DateRange) DateRangeSet
+	// (ob DateRangeSet) Len() int
+	// (ob DateRangeSet) Ranges() []DateRange
+	// (ob DateRangeSet) String() string
+	//
+	set := DateRangeSetOf(
+		drngRange("2024-03-10 2024-03-20"),
+		drngRange("2024-01"),
+		DateRange{},
+		drngRange("2024-03-15 2024-03-25"),
+		drngRange("2024-02"),
+		drngRange("2024-06-01"),
+	)
+	TEqual(t, set.String(), "2024-01-01 2024-02-29, "+
+		"2024-03-10 2024-03-25, 2024-06-01 2024-06-01")
+	TEqual(t, set.Len(), 3)
+	TEqual(t, set.Duration(), (60+16+1)*24*time.Hour)
+	//
+	TTrue(t, set.Contains(DateOf("2024-01-01")))
+	TTrue(t, set.Contains(DateOf("2024-03-25").Add(23*time.Hour)))
+	TTrue(t, set.Contains(DateOf("2024-06-01")))
+	TFalse(t, set.Contains(DateOf("2024-03-09")))
+	TFalse(t, set.Contains(DateOf("2024-03-26")))
+	TFalse(t, set.Contains(DateOf("2024-06-02")))
+	TFalse(t, set.Contains(DateOf("2023-12-31")))
+	//
+	// adding a range can merge several ranges
+	set2 := set
+	set2.Add(drngRange("2024-02-15 2024-06-01"))
+	TEqual(t, set2.String(), "2024-01-01 2024-06-01")
+	TEqual(t, set.Len(), 3) // the original set is unchanged
+	//
+	TEqual(t, set.Intersect(drngRange("2024-02-20 2024-03-12")).String(),
+		"2024-02-20 2024-02-29, 2024-03-10 2024-03-12")
+	TEqual(t, set.Intersect(drngRange("2025")).Len(), 0)
+	//
+	// Ranges returns a copy
+	a := set.Ranges()
+	a[0] = DateRange{}
+	TEqual(t, set.Ranges()[0].String(), "2024-01-01 2024-02-29")
+	//
+	// the zero value is an empty set
+	var empty DateRangeSet
+	TEqual(t, empty.Len(), 0)
+	TEqual(t, empty.String(), "")
+	TEqual(t, len(empty.Ranges()), 0)
+	TFalse(t, empty.Contains(DateOf("2024-01-01")))
+	TEqual(t, empty.Duration(), time.Duration(0))
+} //                                                     Test_drng_DateRangeSet_
+
+// go test --run Test_drng_DateRangeSet_Subtract_
+func Test_drng_DateRangeSet_Subtract_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *DateRangeSet) Subtract(ranges ...DateRange)
+	//
+	set := DateRangeSetOf(drngRange("2024-01"), drngRange("2024-03"))
+	set.Subtract(
+		drngRange("2024-01-10 2024-01-12"),
+		drngRange("2024-01-31 2024-03-05"),
+		DateRange{},
+	)
+	TEqual(t, set.String(), "2024-01-01 2024-01-09, "+
+		"2024-01-13 2024-01-30, 2024-03-06 2024-03-31")
+	//
+	set.Subtract(drngRange("2024"))
+	TEqual(t, set.Len(), 0)
+	TEqual(t, set.String(), "")
+} //                                            Test_drng_DateRangeSet_Subtract_
+
+// end
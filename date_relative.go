@@ -0,0 +1,245 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                              zr/[date_relative.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Clock
+//   DateClock = func() time.Time
+//
+// # Private Functions
+//   dateRangeDays(from, to time.Time) DateRange
+//   dateRangeRelative(
+//       s string, now time.Time, loc *time.Location,
+//   ) (DateRange, bool)
+//   dateRelativeShift(word string) (shift int, ok bool)
+//   dateRelativeUnit(word string) (DateUnit, bool)
+//   dateRelativeWeekday(word string) (time.Weekday, bool)
+//   dateUnitAdd(unit DateUnit, date time.Time, n int) time.Time
+//   dateUnitStart(unit DateUnit, date time.Time) time.Time
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// # Clock
+
+// DateClock returns the current time used by DateRangeOf to resolve
+// relative expressions like "today", "last week" or "YTD", and dates
+// that don't specify a year. It defaults to time.Now, but can be
+// replaced to fix the current date, for example in tests.
+var DateClock = time.Now
+
+// dateRelativeQuarterEx matches quarters like "Q3", "Q3-2024" or "2024-Q3"
+// after DateRangeAt has converted separators to hyphens.
+var dateRelativeQuarterEx = regexp.MustCompile(
+	`^(?:Q([1-4])(?:-?(\d{4}))?|(\d{4})-?Q([1-4]))$`)
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// dateRangeDays returns a DateRange that spans whole days, from
+// the start of 'from' to the end of 'to', in the time zone of 'from'.
+func dateRangeDays(from, to time.Time) DateRange {
+	return DateRange{
+		From: time.Date(from.Year(), from.Month(), from.Day(),
+			0, 0, 0, 0, from.Location()),
+		To: time.Date(to.Year(), to.Month(), to.Day(),
+			23, 59, 59, 999999999, from.Location()),
+	}
+} //                                                               dateRangeDays
+
+// dateRangeRelative returns the DateRange of a relative date or period
+// like "yesterday", "last week", "last 30 days", "Q3 2024" or "YTD",
+// resolved using the date of 'now', with the returned range in time
+// zone 'loc'. The string must already be upper case with hyphens as
+// separators. Returns false if 's' is not a relative date.
+func dateRangeRelative(
+	s string, now time.Time, loc *time.Location,
+) (DateRange, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	//
+	// single words
+	switch s {
+	case "TODAY":
+		return dateRangeDays(today, today), true
+	case "YESTERDAY":
+		day := today.AddDate(0, 0, -1)
+		return dateRangeDays(day, day), true
+	case "TOMORROW":
+		day := today.AddDate(0, 0, 1)
+		return dateRangeDays(day, day), true
+	}
+	// period to date: "YTD" or "year to date" (which is "YEAR~DATE" here)
+	for _, word := range []string{"YEAR", "QUARTER", "MONTH", "WEEK"} {
+		if s == word[:1]+"TD" || s == word+"~DATE" {
+			unit, _ := dateRelativeUnit(word)
+			return dateRangeDays(dateUnitStart(unit, today), today), true
+		}
+	}
+	// quarter with optional year: "Q3", "Q3-2024" or "2024-Q3"
+	if m := dateRelativeQuarterEx.FindStringSubmatch(s); m != nil {
+		quarter, year := m[1], m[2]
+		if quarter == "" {
+			quarter, year = m[4], m[3]
+		}
+		q, _ := strconv.Atoi(quarter)
+		y := today.Year()
+		if year != "" {
+			y, _ = strconv.Atoi(year)
+		}
+		from := time.Date(y, time.Month(q*3-2), 1, 0, 0, 0, 0, loc)
+		return dateRangeDays(from, from.AddDate(0, 3, -1)), true
+	}
+	words := strings.Split(s, "-")
+	if len(words) < 2 || len(words) > 3 {
+		return DateRange{}, false
+	}
+	shift, ok := dateRelativeShift(words[0])
+	if !ok {
+		return DateRange{}, false
+	}
+	// "this week", "last month", "next year", etc.
+	if len(words) == 2 {
+		if unit, ok := dateRelativeUnit(words[1]); ok {
+			from := dateUnitAdd(unit, dateUnitStart(unit, today), shift)
+			to := dateUnitAdd(unit, from, 1).AddDate(0, 0, -1)
+			return dateRangeDays(from, to), true
+		}
+		wd, ok := dateRelativeWeekday(words[1])
+		if !ok {
+			return DateRange{}, false
+		}
+		var (
+			days  = int(wd) - int(today.Weekday())
+			count = 0
+		)
+		switch shift {
+		case 0: // this Monday: the Monday of the current week
+			count = (int(wd)+6)%7 - (int(today.Weekday())+6)%7
+		case 1: // next Monday: the first Monday after today
+			count = (days + 7) % 7
+			if count == 0 {
+				count = 7
+			}
+		case -1: // last Monday: the latest Monday before today
+			count = (days - 7) % 7
+			if count == 0 {
+				count = -7
+			}
+		}
+		day := today.AddDate(0, 0, count)
+		return dateRangeDays(day, day), true
+	}
+	// "last 30 days", "next 2 weeks", etc. (but not "this 2 weeks")
+	n, err := strconv.Atoi(words[1])
+	unit, ok := dateRelativeUnit(words[2])
+	if shift == 0 || err != nil || n < 1 || !ok {
+		return DateRange{}, false
+	}
+	if shift < 0 {
+		from := dateUnitAdd(unit, today, -n).AddDate(0, 0, 1)
+		return dateRangeDays(from, today), true
+	}
+	to := dateUnitAdd(unit, today, n).AddDate(0, 0, -1)
+	return dateRangeDays(today, to), true
+} //                                                           dateRangeRelative
+
+// dateRelativeShift returns the number of periods that a word like
+// "THIS", "LAST" or "NEXT" moves from the current period.
+func dateRelativeShift(word string) (shift int, ok bool) {
+	switch word {
+	case "THIS", "CURRENT":
+		return 0, true
+	case "LAST", "PAST", "PREVIOUS", "PREV":
+		return -1, true
+	case "NEXT", "COMING":
+		return 1, true
+	}
+	return 0, false
+} //                                                           dateRelativeShift
+
+// dateRelativeUnit returns the period unit given a singular or plural
+// word like "DAY" or "WEEKS", or false if 'word' is not a period unit.
+func dateRelativeUnit(word string) (DateUnit, bool) {
+	switch strings.TrimSuffix(word, "S") {
+	case "DAY":
+		return DateUnitDay, true
+	case "WEEK":
+		return DateUnitWeek, true
+	case "MONTH":
+		return DateUnitMonth, true
+	case "QUARTER":
+		return DateUnitQuarter, true
+	case "YEAR":
+		return DateUnitYear, true
+	}
+	return DateUnitDay, false
+} //                                                            dateRelativeUnit
+
+// dateRelativeWeekday returns the day of the week given its
+// English name or 3-letter abbreviation in upper case.
+func dateRelativeWeekday(word string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToUpper(wd.String())
+		if word == name || word == name[:3] {
+			return wd, true
+		}
+	}
+	return time.Sunday, false
+} //                                                         dateRelativeWeekday
+
+// dateUnitAdd adds 'n' periods of the specified unit to 'date'.
+// When adding months, quarters or years, the day is limited to
+// the last day of the resulting month, so that for example one
+// month after January 31 is the end of February.
+func dateUnitAdd(unit DateUnit, date time.Time, n int) time.Time {
+	months := 0
+	switch unit {
+	case DateUnitDay:
+		return date.AddDate(0, 0, n)
+	case DateUnitWeek:
+		return date.AddDate(0, 0, n*7)
+	case DateUnitMonth:
+		months = n
+	case DateUnitQuarter:
+		months = n * 3
+	case DateUnitYear:
+		months = n * 12
+	}
+	var (
+		first = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0,
+			time.UTC).AddDate(0, months, 0)
+		day  = date.Day()
+		last = DaysInMonth(first.Year(), first.Month())
+	)
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, date.Hour(),
+		date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+} //                                                                 dateUnitAdd
+
+// dateUnitStart returns the start (midnight) of the first day
+// of the period of the specified unit that contains 'date'.
+func dateUnitStart(unit DateUnit, date time.Time) time.Time {
+	y, m, d := date.Date()
+	switch unit {
+	case DateUnitWeek: // weeks start on Monday
+		d -= (int(date.Weekday()) + 6) % 7
+	case DateUnitMonth:
+		d = 1
+	case DateUnitQuarter:
+		m, d = (m-1)/3*3+1, 1
+	case DateUnitYear:
+		m, d = time.January, 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+} //                                                               dateUnitStart
+
+// end
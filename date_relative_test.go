@@ -0,0 +1,209 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                         zr/[date_relative_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Clock
+//   Test_drel_DateClock_
+//
+// # Private Functions
+//   Test_drel_dateRangeRelative_
+//   Test_drel_dateRangeRelative_weekdays_
+//   Test_drel_dateUnitAdd_
+
+//  to test all items in date_relative.go use:
+//      go test --run Test_drel_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// # Clock
+
+// go test --run Test_drel_DateClock_
+func Test_drel_DateClock_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateClock = func() time.Time
+	//
+	prev := DateClock
+	defer func() { DateClock = prev }()
+	DateClock = func() time.Time {
+		return time.Date(2024, 2, 29, 10, 30, 0, 0, time.UTC)
+	}
+	TEqual(t, DateRangeOf("today").String(), "2024-02-29 2024-02-29")
+	TEqual(t, DateRangeOf("last month").String(), "2024-01-01 2024-01-31")
+	TEqual(t, DateRangeOf("YTD").String(), "2024-01-01 2024-02-29")
+	TEqual(t, DateRangeOf("Q3").String(), "2024-07-01 2024-09-30")
+	//
+	// dates without a year also use the clock
+	TEqual(t, DateRangeOf("May").String(), "2024-05-01 2024-05-31")
+	TEqual(t, DateRangeOf("14-Jan").String(), "2024-01-14 2024-01-14")
+	//
+	// the time zone of the clock decides which day it is
+	DateClock = func() time.Time {
+		return time.Date(2024, 12, 31, 23, 0, 0, 0,
+			time.FixedZone("", -5*60*60))
+	}
+	TEqual(t, DateRangeOf("today").String(), "2024-12-31 2024-12-31")
+	TEqual(t, DateRangeOf("next year").String(), "2025-01-01 2025-12-31")
+} //                                                        Test_drel_DateClock_
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// go test --run Test_drel_dateRangeRelative_
+func Test_drel_dateRangeRelative_(t *testing.T) {
+	TBegin(t)
+	//
+	// dateRangeRelative(
+	//     s string, now time.Time, loc *time.Location,
+	// ) (DateRange, bool)
+	//
+	// Wednesday, 14 August 2024
+	now := time.Date(2024, 8, 14, 15, 4, 5, 0, time.UTC)
+	test := func(s, expect string) {
+		got := DateRangeAt(s, now)
+		if got.String() != expect {
+			TFailf(t, `DateRangeAt(%q) returned %q instead of %q`,
+				s, got.String(), expect)
+		}
+	}
+	// days
+	test("today", "2024-08-14 2024-08-14")
+	test(" Yesterday ", "2024-08-13 2024-08-13")
+	test("TOMORROW", "2024-08-15 2024-08-15")
+	test("this day", "2024-08-14 2024-08-14")
+	test("last day", "2024-08-13 2024-08-13")
+	//
+	// calendar periods
+	test("this week", "2024-08-12 2024-08-18")
+	test("last week", "2024-08-05 2024-08-11")
+	test("previous week", "2024-08-05 2024-08-11")
+	test("next week", "2024-08-19 2024-08-25")
+	test("this month", "2024-08-01 2024-08-31")
+	test("current month", "2024-08-01 2024-08-31")
+	test("last month", "2024-07-01 2024-07-31")
+	test("next month", "2024-09-01 2024-09-30")
+	test("this quarter", "2024-07-01 2024-09-30")
+	test("last quarter", "2024-04-01 2024-06-30")
+	test("next quarter", "2024-10-01 2024-12-31")
+	test("this year", "2024-01-01 2024-12-31")
+	test("last year", "2023-01-01 2023-12-31")
+	test("next_year", "2025-01-01 2025-12-31")
+	//
+	// rolling periods
+	test("last 30 days", "2024-07-16 2024-08-14")
+	test("past 7 days", "2024-08-08 2024-08-14")
+	test("next 7 days", "2024-08-14 2024-08-20")
+	test("last 1 day", "2024-08-14 2024-08-14")
+	test("last 2 weeks", "2024-08-01 2024-08-14")
+	test("last 3 months", "2024-05-15 2024-08-14")
+	test("next 6 months", "2024-08-14 2025-02-13")
+	test("last 2 quarters", "2024-02-15 2024-08-14")
+	test("last 1 year", "2023-08-15 2024-08-14")
+	//
+	// period to date
+	test("YTD", "2024-01-01 2024-08-14")
+	test("qtd", "2024-07-01 2024-08-14")
+	test("MTD", "2024-08-01 2024-08-14")
+	test("WTD", "2024-08-12 2024-08-14")
+	test("year to date", "2024-01-01 2024-08-14")
+	test("month to date", "2024-08-01 2024-08-14")
+	//
+	// quarters
+	test("Q1", "2024-01-01 2024-03-31")
+	test("Q3 2024", "2024-07-01 2024-09-30")
+	test("q4/2023", "2023-10-01 2023-12-31")
+	test("2023-Q2", "2023-04-01 2023-06-30")
+	test("2023Q2", "2023-04-01 2023-06-30")
+	//
+	// ranges that combine relative and explicit dates
+	test("last week to today", "2024-08-05 2024-08-14")
+	test("Q1 to Q2", "2024-01-01 2024-06-30")
+	test("1 Jan 2024 to yesterday", "2024-01-01 2024-08-13")
+	//
+	// not relative dates
+	for _, s := range []string{
+		"", "last", "this 2 weeks", "last 0 days", "last x days",
+		"next fortnight", "Q5", "Q0 2024", "lastweek", "last week ago",
+	} {
+		TTrue(t, DateRangeAt(s, now).IsNull())
+	}
+} //                                                Test_drel_dateRangeRelative_
+
+// go test --run Test_drel_dateRangeRelative_weekdays_
+func Test_drel_dateRangeRelative_weekdays_(t *testing.T) {
+	TBegin(t)
+	//
+	// dateRangeRelative(
+	//     s string, now time.Time, loc *time.Location,
+	// ) (DateRange, bool)
+	//
+	// Wednesday, 14 August 2024
+	now := time.Date(2024, 8, 14, 0, 0, 0, 0, time.UTC)
+	test := func(s, expect string) {
+		got := DateRangeAt(s, now)
+		if got.String() != expect+" "+expect {
+			TFailf(t, `DateRangeAt(%q) returned %q instead of %q`,
+				s, got.String(), expect)
+		}
+	}
+	test("next Monday", "2024-08-19")
+	test("next Tuesday", "2024-08-20")
+	test("next Wednesday", "2024-08-21")
+	test("next Thursday", "2024-08-15")
+	test("next sun", "2024-08-18")
+	test("last Monday", "2024-08-12")
+	test("last Wednesday", "2024-08-07")
+	test("last Thursday", "2024-08-08")
+	test("last Sunday", "2024-08-11")
+	test("this Monday", "2024-08-12")
+	test("this Wednesday", "2024-08-14")
+	test("this Sunday", "2024-08-18")
+	//
+	// on a Sunday, the current week started on Monday
+	now = time.Date(2024, 8, 18, 0, 0, 0, 0, time.UTC)
+	test("this Monday", "2024-08-12")
+	test("next Monday", "2024-08-19")
+	test("last Saturday", "2024-08-17")
+} //                                       Test_drel_dateRangeRelative_weekdays_
+
+// go test --run Test_drel_dateUnitAdd_
+func Test_drel_dateUnitAdd_(t *testing.T) {
+	TBegin(t)
+	//
+	// dateUnitAdd(unit DateUnit, date time.Time, n int) time.Time
+	//
+	test := func(unit DateUnit, date string, n int, expect string) {
+		got := dateUnitAdd(unit, DateOf(date), n).Format("2006-01-02")
+		if got != expect {
+			TFailf(t, `dateUnitAdd(%v, %q, %d) returned %q instead of %q`,
+				unit, date, n, got, expect)
+		}
+	}
+	test(DateUnitDay, "2024-02-28", 2, "2024-03-01")
+	test(DateUnitWeek, "2024-12-30", 1, "2025-01-06")
+	test(DateUnitMonth, "2024-01-31", 1, "2024-02-29")
+	test(DateUnitMonth, "2024-03-31", -1, "2024-02-29")
+	test(DateUnitMonth, "2024-01-15", -13, "2022-12-15")
+	test(DateUnitQuarter, "2024-11-30", 1, "2025-02-28")
+	test(DateUnitYear, "2024-02-29", 1, "2025-02-28")
+	test(DateUnitYear, "2024-02-29", -4, "2020-02-29")
+	//
+	// the time of day and time zone are kept
+	cet := time.FixedZone("CET", 60*60)
+	TEqual(t, dateUnitAdd(DateUnitMonth,
+		time.Date(2024, 1, 31, 9, 30, 0, 0, cet), 1),
+		time.Date(2024, 2, 29, 9, 30, 0, 0, cet))
+} //                                                      Test_drel_dateUnitAdd_
+
+// end
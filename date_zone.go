@@ -0,0 +1,205 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                  zr/[date_zone.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Functions
+//   DateOfIn(value interface{}, loc *time.Location) time.Time
+//   DateRangeOfIn(s string, loc *time.Location) DateRange
+//
+// # Private Functions
+//   dateSplitZone(s string) (date, clock string, zone *time.Location)
+//   dateZoneName(t time.Time) string
+//   dateZoneOf(s string) *time.Location
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// dateClockEx matches a time of day like "9:30", "09:30:15"
+	// or "09:30:15.123".
+	dateClockEx = regexp.MustCompile(`^\d{1,2}:\d{2}(?::\d{2}(?:\.\d+)?)?$`)
+
+	// dateISOTimeEx matches the 'T' between the date and time
+	// in ISO 8601 dates, e.g. "2024-03-01T10:00".
+	dateISOTimeEx = regexp.MustCompile(`(\d)T(\d{1,2}:\d)`)
+
+	// dateOffsetEx matches a time zone offset like "+02:00", "+0200",
+	// "-05", "UTC+2" or "GMT-03:30".
+	dateOffsetEx = regexp.MustCompile(
+		`^(?:UTC|GMT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+	// dateTimeZoneEx matches a time zone offset or "Z" written
+	// right after a time, e.g. "10:00:00+02:00" or "10:00Z".
+	dateTimeZoneEx = regexp.MustCompile(
+		`(:\d{2}(?:\.\d+)?)(Z|[+-]\d{2}(?::?\d{2})?)$`)
+
+	// dateZoneNameEx matches IANA time zone names like "Europe/Paris".
+	dateZoneNameEx = regexp.MustCompile(
+		`^[A-Za-z][A-Za-z_+-]*(?:/[A-Za-z0-9_+-]+)+$`)
+)
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// DateOfIn converts any string-like value or time.Time to the date
+// (at midnight) in time zone 'loc', like DateOf does for UTC.
+//
+// A time.Time is first converted to 'loc', so the date may differ
+// from the date in the value's own time zone.
+//
+// A string like "2024-03-01" is a date in 'loc', but a string can also
+// include the time and a time zone, e.g. "2024-03-01 22:00 -0500",
+// "2024-03-01T22:00:00Z" or "2024-03-01 22:00 America/New_York".
+// In that case the moment in time is converted to 'loc' first.
+//
+// If value is a zero-length string, returns a zero-value time.Time
+// without logging an error. If the value can not be converted or
+// 'loc' is nil, logs an error and returns a zero-value time.Time.
+func DateOfIn(value interface{}, loc *time.Location) time.Time {
+	if loc == nil {
+		mod.Error(EInvalidArg, "^loc", "is nil")
+		return time.Time{}
+	}
+	switch v := value.(type) {
+	case time.Time:
+		{
+			y, m, d := v.In(loc).Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, loc)
+		}
+	case string:
+		{
+			if v == "" {
+				return time.Time{}
+			}
+			date, clock, zone := dateSplitZone(v)
+			if zone == nil {
+				zone = loc
+			}
+			if len(date) > 10 {
+				date = date[:10]
+			}
+			tm, err := time.ParseInLocation("2006-01-02", date, zone)
+			if err != nil {
+				mod.Error(err)
+				return time.Time{}
+			}
+			if clock != "" {
+				layout := "15:04"
+				if strings.Count(clock, ":") == 2 {
+					layout = "15:04:05"
+				}
+				hms, err := time.Parse(layout, clock)
+				if err != nil {
+					mod.Error(err)
+					return time.Time{}
+				}
+				tm = time.Date(tm.Year(), tm.Month(), tm.Day(), hms.Hour(),
+					hms.Minute(), hms.Second(), hms.Nanosecond(), zone)
+			}
+			return DateOfIn(tm, loc)
+		}
+	case *string:
+		if v != nil {
+			return DateOfIn(*v, loc)
+		}
+	}
+	mod.Error("Can not convert", reflect.TypeOf(value), "to date:", value)
+	return time.Time{}
+} //                                                                    DateOfIn
+
+// DateRangeOfIn creates and returns a DateRange structure from a string
+// like DateRangeOf, but with the dates in time zone 'loc' instead of
+// UTC. Relative expressions like "today" are resolved using the
+// current date in 'loc'. If the string ends with a time zone, e.g.
+// "2024-03 Europe/Paris" or "2024-03-01 10:00 +0200", the dates are
+// in that time zone instead.
+//
+// If 'loc' is nil, logs an error and returns a null DateRange.
+func DateRangeOfIn(s string, loc *time.Location) DateRange {
+	if loc == nil {
+		mod.Error(EInvalidArg, "^loc", "is nil")
+		return DateRange{}
+	}
+	return dateRangeAt(s, DateClock().In(loc), loc)
+} //                                                               DateRangeOfIn
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// dateSplitZone splits a date string that may end with a time
+// of day and a time zone, e.g. "2024-03-01 10:00 +0200", into
+// the date, the time of day ("" if not specified) and the time
+// zone (nil if not specified).
+func dateSplitZone(s string) (date, clock string, zone *time.Location) {
+	s = dateISOTimeEx.ReplaceAllString(s, "$1 $2")
+	s = dateTimeZoneEx.ReplaceAllString(s, "$1 $2")
+	fields := strings.Fields(s)
+	if n := len(fields); n > 1 {
+		if zone = dateZoneOf(fields[n-1]); zone != nil {
+			fields = fields[:n-1]
+		}
+	}
+	if n := len(fields); n > 1 && dateClockEx.MatchString(fields[n-1]) {
+		clock = fields[n-1]
+		fields = fields[:n-1]
+	}
+	return strings.Join(fields, " "), clock, zone
+} //                                                               dateSplitZone
+
+// dateZoneName returns the name of the time zone of 't' as used by
+// DateRange.String: the IANA name like "Europe/Paris" if there is
+// one, otherwise the offset from UTC like "+02:00".
+func dateZoneName(t time.Time) string {
+	name := t.Location().String()
+	if name == "" || name == "Local" {
+		return t.Format("-07:00")
+	}
+	return name
+} //                                                                dateZoneName
+
+// dateZoneOf returns the time zone specified by 's', which can be
+// "Z", "UTC", "GMT", an offset like "+02:00", "-0500" or "UTC+2",
+// or an IANA time zone name like "Europe/Paris". Returns nil if 's'
+// is not a time zone. Offsets of zero return time.UTC.
+func dateZoneOf(s string) *time.Location {
+	switch strings.ToUpper(s) {
+	case "Z", "UTC", "GMT":
+		return time.UTC
+	}
+	if m := dateOffsetEx.FindStringSubmatch(strings.ToUpper(s)); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		mins := 0
+		if m[3] != "" {
+			mins, _ = strconv.Atoi(m[3])
+		}
+		// without a colon, the hours need 2 digits: "+123" is ambiguous
+		if hours > 14 || mins > 59 ||
+			(len(m[2]) == 1 && m[3] != "" && !strings.Contains(s, ":")) {
+			return nil
+		}
+		offset := hours*60*60 + mins*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		if offset == 0 {
+			return time.UTC
+		}
+		return time.FixedZone("", offset)
+	}
+	if dateZoneNameEx.MatchString(s) {
+		if loc, err := time.LoadLocation(s); err == nil {
+			return loc
+		}
+	}
+	return nil
+} //                                                                  dateZoneOf
+
+// end
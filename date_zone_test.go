@@ -0,0 +1,268 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                             zr/[date_zone_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Functions
+//   Test_dzon_DateOfIn_
+//   Test_dzon_DateRangeOfIn_
+//   Test_dzon_DateRange_String_
+//
+// # Private Functions
+//   Test_dzon_dateSplitZone_
+//   Test_dzon_dateZoneOf_
+
+//  to test all items in date_zone.go use:
+//      go test --run Test_dzon_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+	"time"
+
+	// embedded time zone database, so that the tests
+	// don't depend on the time zones installed locally
+	_ "time/tzdata"
+)
+
+// dzonLocation returns the time zone with the specified IANA name.
+func dzonLocation(t *testing.T, name string) *time.Location {
+	ret, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("time zone %q not found: %v", name, err)
+	}
+	return ret
+} //                                                                dzonLocation
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// go test --run Test_dzon_DateOfIn_
+func Test_dzon_DateOfIn_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateOfIn(value interface{}, loc *time.Location) time.Time
+	//
+	var (
+		sydney = dzonLocation(t, "Australia/Sydney")
+		paris  = dzonLocation(t, "Europe/Paris")
+	)
+	test := func(value interface{}, loc *time.Location, expect string) {
+		got := DateOfIn(value, loc).Format("2006-01-02 15:04 MST")
+		if got != expect {
+			TFailf(t, `DateOfIn(%v, %v) returned %q instead of %q`,
+				value, loc, got, expect)
+		}
+	}
+	// dates without a time zone are in 'loc'
+	test("2024-03-01", sydney, "2024-03-01 00:00 AEDT")
+	test("2024-07-01", sydney, "2024-07-01 00:00 AEST")
+	test("2024-03-01", time.UTC, "2024-03-01 00:00 UTC")
+	test("2024-03-01 23:30", sydney, "2024-03-01 00:00 AEDT")
+	//
+	// times in other time zones may fall on another day
+	test("2024-03-01 22:00 -0500", sydney, "2024-03-02 00:00 AEDT")
+	test("2024-03-01T22:00:00Z", sydney, "2024-03-02 00:00 AEDT")
+	test("2024-03-01T12:59:59.999Z", sydney, "2024-03-01 00:00 AEDT")
+	test("2024-03-01T13:00:00Z", sydney, "2024-03-02 00:00 AEDT")
+	test("2024-03-01 10:00 +10:00", time.UTC, "2024-03-01 00:00 UTC")
+	test("2024-03-01 09:00 +10:00", time.UTC, "2024-02-29 00:00 UTC")
+	test("2024-03-01 23:00 Europe/Paris", sydney, "2024-03-02 00:00 AEDT")
+	test("2024-03-02 08:00 Australia/Sydney", paris, "2024-03-01 00:00 CET")
+	//
+	// time.Time values are converted to 'loc'
+	utc := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	test(utc, sydney, "2024-03-02 00:00 AEDT")
+	test(utc, paris, "2024-03-01 00:00 CET")
+	s := "2024-03-01"
+	test(&s, paris, "2024-03-01 00:00 CET")
+	//
+	// DateOf ignores the time zone, DateOfIn doesn't
+	TEqual(t, DateOf("2024-03-01 22:00 -0500").Format("2006-01-02"),
+		"2024-03-01")
+	TTrue(t, DateOfIn("", sydney).IsZero())
+	//
+	// errors
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, DateOfIn("2024-03-01", nil).IsZero())
+	TTrue(t, DateOfIn("2024-13-01", sydney).IsZero())
+	TTrue(t, DateOfIn("2024-03-01 25:00", sydney).IsZero())
+	TTrue(t, DateOfIn(123, sydney).IsZero())
+	TTrue(t, DateOfIn((*string)(nil), sydney).IsZero())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 5)
+} //                                                         Test_dzon_DateOfIn_
+
+// go test --run Test_dzon_DateRangeOfIn_
+func Test_dzon_DateRangeOfIn_(t *testing.T) {
+	TBegin(t)
+	//
+	// DateRangeOfIn(s string, loc *time.Location) DateRange
+	//
+	sydney := dzonLocation(t, "Australia/Sydney")
+	rng := DateRangeOfIn("2024-03", sydney)
+	TEqual(t, rng.From, time.Date(2024, 3, 1, 0, 0, 0, 0, sydney))
+	TEqual(t, rng.To, time.Date(2024, 3, 31, 23, 59, 59, 999999999, sydney))
+	TEqual(t, rng.String(), "2024-03-01 2024-03-31 Australia/Sydney")
+	//
+	// the start of the range is 11 hours ahead of UTC in March
+	TEqual(t, rng.From.UTC().Format(time.RFC3339), "2024-02-29T13:00:00Z")
+	TTrue(t, rng.Contains(time.Date(2024, 2, 29, 14, 0, 0, 0, time.UTC)))
+	TFalse(t, DateRangeOf("2024-03").Contains(
+		time.Date(2024, 2, 29, 14, 0, 0, 0, time.UTC)))
+	//
+	// "today" is the current date in 'loc'
+	prev := DateClock
+	defer func() { DateClock = prev }()
+	DateClock = func() time.Time {
+		return time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	}
+	TEqual(t, DateRangeOfIn("today", sydney).String(),
+		"2024-03-02 2024-03-02 Australia/Sydney")
+	TEqual(t, DateRangeOf("today").String(), "2024-03-01 2024-03-01")
+	TEqual(t, DateRangeOfIn("this week", sydney).String(),
+		"2024-02-26 2024-03-03 Australia/Sydney")
+	//
+	// a time zone in the string overrides 'loc'
+	test := func(s string, loc *time.Location, expect string) {
+		got := DateRangeOfIn(s, loc)
+		if got.String() != expect {
+			TFailf(t, `DateRangeOfIn(%q, %v) returned %q instead of %q`,
+				s, loc, got.String(), expect)
+		}
+	}
+	test("2024-03 Europe/Paris", sydney,
+		"2024-03-01 2024-03-31 Europe/Paris")
+	test("2024-03-01 10:00 +0200", sydney, "2024-03-01 2024-03-01 +02:00")
+	test("2024-03-01T23:30:00-05:00", time.UTC,
+		"2024-03-01 2024-03-01 -05:00")
+	test("2024-03-01 UTC", sydney, "2024-03-01 2024-03-01")
+	test("Q1 2024 to Q2 2024 America/New_York", time.UTC,
+		"2024-01-01 2024-06-30 America/New_York")
+	test("today Europe/London", sydney,
+		"2024-03-01 2024-03-01 Europe/London")
+	//
+	// DateRangeOf also accepts a time zone in the string
+	TEqual(t, DateRangeOf("2024 Asia/Tokyo").String(),
+		"2024-01-01 2024-12-31 Asia/Tokyo")
+	TEqual(t, DateRangeOf("7 Mar 2024 09:15").String(),
+		"2024-03-07 2024-03-07")
+	//
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TTrue(t, DateRangeOfIn("2024", nil).IsNull())
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 1)
+} //                                                    Test_dzon_DateRangeOfIn_
+
+// go test --run Test_dzon_DateRange_String_
+func Test_dzon_DateRange_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob DateRange) String() string
+	//
+	test := func(loc *time.Location, expect string) {
+		rng := DateRange{
+			From: time.Date(2024, 3, 1, 0, 0, 0, 0, loc),
+			To:   time.Date(2024, 3, 31, 0, 0, 0, 0, loc),
+		}
+		TEqual(t, rng.String(), expect)
+	}
+	test(time.UTC, "2024-03-01 2024-03-31")
+	test(dzonLocation(t, "UTC"), "2024-03-01 2024-03-31")
+	test(dzonLocation(t, "Europe/Paris"), "2024-03-01 2024-03-31 Europe/Paris")
+	test(time.FixedZone("", 10*60*60), "2024-03-01 2024-03-31 +10:00")
+	test(time.FixedZone("", -(3*60*60+30*60)), "2024-03-01 2024-03-31 -03:30")
+	test(time.FixedZone("AEST", 10*60*60), "2024-03-01 2024-03-31 AEST")
+	TEqual(t, DateRange{}.String(), "0001-01-01 0001-01-01")
+} //                                                 Test_dzon_DateRange_String_
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// go test --run Test_dzon_dateSplitZone_
+func Test_dzon_dateSplitZone_(t *testing.T) {
+	TBegin(t)
+	//
+	// dateSplitZone(s string) (date, clock string, zone *time.Location)
+	//
+	test := func(s, expectDate, expectClock, expectZone string) {
+		date, clock, zone := dateSplitZone(s)
+		name := "<nil>"
+		if zone != nil {
+			name = dateZoneName(time.Date(2024, 1, 1, 0, 0, 0, 0, zone))
+		}
+		if date != expectDate || clock != expectClock || name != expectZone {
+			TFailf(t, `dateSplitZone(%q) returned %q, %q, %q`,
+				s, date, clock, name)
+		}
+	}
+	test("2024-03-01", "2024-03-01", "", "<nil>")
+	test(" 2024-03-01  10:00 ", "2024-03-01", "10:00", "<nil>")
+	test("2024-03-01 10:00 +0200", "2024-03-01", "10:00", "+02:00")
+	test("2024-03-01T10:00:00Z", "2024-03-01", "10:00:00", "UTC")
+	test("2024-03-01T10:00:00.5+05:30", "2024-03-01", "10:00:00.5", "+05:30")
+	test("2024-03-01T10:00-05", "2024-03-01", "10:00", "-05:00")
+	test("1 March 2024 9:30 Europe/Paris", "1 March 2024", "9:30",
+		"Europe/Paris")
+	test("March 2024 GMT+1", "March 2024", "", "+01:00")
+	test("last week UTC", "last week", "", "UTC")
+	//
+	// not time zones
+	test("Jun/2008", "Jun/2008", "", "<nil>")
+	test("2024 Mars/Olympus", "2024 Mars/Olympus", "", "<nil>")
+	test("UTC", "UTC", "", "<nil>")
+	test("10:00", "10:00", "", "<nil>")
+	test("", "", "", "<nil>")
+} //                                                    Test_dzon_dateSplitZone_
+
+// go test --run Test_dzon_dateZoneOf_
+func Test_dzon_dateZoneOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// dateZoneOf(s string) *time.Location
+	//
+	test := func(s string, expectOffset int) {
+		loc := dateZoneOf(s)
+		if loc == nil {
+			TFailf(t, `dateZoneOf(%q) returned nil`, s)
+			return
+		}
+		_, offset := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Zone()
+		if offset != expectOffset {
+			TFailf(t, `dateZoneOf(%q) has offset %d instead of %d`,
+				s, offset, expectOffset)
+		}
+	}
+	test("Z", 0)
+	test("utc", 0)
+	test("GMT", 0)
+	test("+02:00", 2*60*60)
+	test("+0200", 2*60*60)
+	test("-05", -5*60*60)
+	test("+5:45", 5*60*60+45*60)
+	test("UTC+10", 10*60*60)
+	test("gmt-3:30", -(3*60*60 + 30*60))
+	test("Asia/Kolkata", 5*60*60+30*60)
+	test("America/Argentina/Buenos_Aires", -3*60*60)
+	TTrue(t, dateZoneOf("+00:00") == time.UTC)
+	//
+	for _, s := range []string{
+		"", "+", "+15", "+02:60", "+123", "2024", "Europe", "Europe/Nowhere",
+		"../etc/passwd", "CET-1",
+	} {
+		if loc := dateZoneOf(s); loc != nil {
+			TFailf(t, `dateZoneOf(%q) returned %v`, s, loc)
+		}
+	}
+} //                                                       Test_dzon_dateZoneOf_
+
+// end
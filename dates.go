@@ -23,6 +23,7 @@ package zr
 //
 // # Functions
 //   DateOf(value interface{}) time.Time
+//   DateRangeAt(s string, now time.Time) DateRange
 //   DateRangeOf(s string) DateRange
 //   DayMth(value interface{}) string
 //   DaysInMonth(year int, month time.Month) int
@@ -40,6 +41,8 @@ package zr
 //   YMD(t time.Time) string
 //
 // # Private Functions
+//   dateFormatWords(format string) string
+//   dateRangeAt(s string, now time.Time, loc *time.Location) DateRange
 //   stringDate(value interface{}, format string) string
 
 import (
@@ -48,6 +51,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Tip: Go uses the following date format reference:
@@ -106,6 +110,11 @@ var (
 // # DateRange
 
 // DateRange represents a time period.
+//
+// Both ends of a DateRange are inclusive: a range that covers whole
+// days ends at 23:59:59.999999999 on the last day, like the ranges
+// returned by DateRangeOf. A null range, or one that ends before it
+// starts, is empty: it contains nothing and overlaps nothing.
 type DateRange struct {
 	From time.Time
 	To   time.Time
@@ -118,8 +127,15 @@ func (ob DateRange) IsNull() bool {
 
 // String returns a string representation of the DateRange structure
 // and implements the fmt.Stringer interface.
+//
+// If the range is not in UTC, the time zone of From is appended,
+// e.g. "2024-03-01 2024-03-31 Europe/Paris" or "... +10:00".
 func (ob DateRange) String() string {
-	return ob.From.Format("2006-01-02") + " " + ob.To.Format("2006-01-02")
+	ret := ob.From.Format("2006-01-02") + " " + ob.To.Format("2006-01-02")
+	if zone := dateZoneName(ob.From); zone != "UTC" {
+		ret += " " + zone
+	}
+	return ret
 } //                                                                      String
 
 // -----------------------------------------------------------------------------
@@ -173,126 +189,38 @@ func DateOf(value interface{}) time.Time {
 	return time.Time{}
 } //                                                                      DateOf
 
-// DateRangeOf creates and returns a DateRange structure from a string. _ _
+// DateRangeAt creates and returns a DateRange structure from a string,
+// like DateRangeOf, but resolves relative expressions such as "today",
+// "last week" or "Q3" and dates without a year using the specified
+// current time 'now' instead of DateClock().
+func DateRangeAt(s string, now time.Time) DateRange {
+	return dateRangeAt(s, now, time.UTC)
+} //                                                                 DateRangeAt
+
+// DateRangeOf creates and returns a DateRange structure from a string.
+// The dates are in UTC, unless the string ends with a time zone like
+// "Europe/Paris" or "+02:00" (see DateRangeOfIn).
+//
+// Besides explicit dates, months and years, it accepts relative dates
+// and periods which are resolved using the current time from DateClock:
+//
+//   today, yesterday, tomorrow
+//   this/last/next day, week, month, quarter or year
+//   last/next N days, weeks, months, quarters or years
+//   YTD, QTD, MTD, WTD (year, quarter, month or week to date)
+//   Q1 to Q4, optionally followed or preceded by a year, e.g. Q3 2024
+//   this/last/next Monday, Tuesday, etc.
+//
+// It also accepts ISO weeks like 2024-W07 (see ISOWeekRange), and
+// fiscal years and quarters like FY2024 or FY2024 Q1, which use the
+// fiscal calendar set in DateFiscalCalendar.
+//
+// Weeks start on Monday. "Last 30 days" is the 30 days ending today
+// and "next 7 days" is the 7 days starting today, while "last week" is
+// the whole calendar week before the current one. "Next Monday" is the
+// first Monday after today and "last Monday" the latest before today.
 func DateRangeOf(s string) DateRange {
-	// pre-format
-	s = strings.ToUpper(strings.TrimSpace(s))
-	for _, sep := range []string{" ", ".", "/", "\\", "_"} {
-		for strings.Contains(s, sep) {
-			s = strings.ReplaceAll(s, sep, "-")
-		}
-	}
-	for strings.Contains(s, "--") {
-		s = strings.ReplaceAll(s, "--", "-")
-	}
-	for strings.Contains(s, "-TO-") {
-		s = strings.ReplaceAll(s, "-TO-", "~")
-	}
-	var (
-		y1, m1, d1 = 0, time.January, 1
-		y2, m2, d2 = 0, time.December, 31
-	)
-	// date range
-	if i := strings.Index(s, "~"); i != -1 {
-		var (
-			r1 = DateRangeOf(s[:i])
-			r2 = DateRangeOf(s[i+1:])
-		)
-		y1, m1, d1 = r1.From.Year(), r1.From.Month(), r1.From.Day()
-		y2, m2, d2 = r2.To.Year(), r2.To.Month(), r2.To.Day()
-	}
-	// year only
-	if len(s) == 4 {
-		dt, err := time.Parse("2006", s)
-		if err == nil {
-			y1, m1, d1 = dt.Year(), 1, 1
-			y2, m2, d2 = dt.Year(), 12, 31
-		}
-	}
-	// month only
-	if y1 == 0 {
-		for _, format := range []string{"Jan", "January"} {
-			dt, err := time.Parse(format, s)
-			if err == nil {
-				now := time.Now()
-				y1, m1, d1 = now.Year(), dt.Month(), 1
-				y2, m2, d2 = now.Year(), dt.Month(),
-					DaysInMonth(now.Year(), dt.Month())
-				break
-			}
-		}
-	}
-	// complete date
-	if y1 == 0 {
-		for _, format := range []string{
-			"2-1-2006",
-			"2-Jan-2006",
-			"2-January-2006",
-			"2006-01-02",
-			"2006-2-Jan",
-			"2006-2-January",
-			"2006-Jan-2",
-			"2006-January-2",
-			"Jan-2-2006",
-			"Jan-2006-2",
-		} {
-			dt, err := time.Parse(format, s)
-			if err == nil {
-				y1, m1, d1 = dt.Year(), dt.Month(), dt.Day()
-				y2, m2, d2 = dt.Year(), dt.Month(), dt.Day()
-				break
-			}
-		}
-	}
-	// month and year
-	if y1 == 0 {
-		for _, format := range []string{
-			"1-2006",
-			"2006-1",
-			"2006-Jan",
-			"2006-January",
-			"2006Jan",
-			"2006January",
-			"Jan-2006",
-			"Jan2006",
-			"January-2006",
-			"January2006",
-		} {
-			dt, err := time.Parse(format, s)
-			if err == nil {
-				y1, m1, d1 = dt.Year(), dt.Month(), 1
-				y2, m2, d2 = dt.Year(), dt.Month(),
-					DaysInMonth(dt.Year(), dt.Month())
-				break
-			}
-		}
-	}
-	// day and month
-	if y1 == 0 {
-		for _, format := range []string{
-			"2-Jan",
-			"2-January",
-			"2Jan",
-			"2January",
-			"Jan-2",
-			"Jan2",
-			"January-2",
-			"January2",
-		} {
-			dt, err := time.Parse(format, s)
-			if err == nil {
-				now := time.Now()
-				y1, m1, d1 = now.Year(), dt.Month(), dt.Day()
-				y2, m2, d2 = now.Year(), dt.Month(), dt.Day()
-				break
-			}
-		}
-	}
-	if y1 == 0 {
-		return DateRange{From: time.Time{}, To: time.Time{}}
-	}
-	return DateRange{From: time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC),
-		To: time.Date(y2, m2, d2, 23, 59, 59, 999999999, time.UTC)}
+	return DateRangeAt(s, DateClock())
 } //                                                                 DateRangeOf
 
 // DayMth returns a day-and-month string of the format
@@ -324,80 +252,22 @@ func DaysInMonth(year int, month time.Month) int {
 
 // FormatDateEN formats date using the specified format.
 // Uses English language names, hence the 'EN' suffix.
+//
+// The format is a pattern such as "Dddd, d Mmmm yyyy hh:nn tt".
+// See DatePattern for the supported fields. Unlike DatePattern,
+// words that are not made only of field letters are kept as text,
+// e.g. "Date: d Mmmm" gives "Date: 7 March". Words like "do" or
+// "is" are fields, so quote them to keep them as text, e.g.
+// "'is' d Mmmm". Use FormatDate() or DatePattern for the exact syntax.
+//
+// If the format is not valid, logs an error and returns a blank string.
 func FormatDateEN(format string, date time.Time) string {
-	var (
-		year  = date.Year()
-		month = int(date.Month())
-		day   = date.Day()
-		ret   = format
-	)
-	// TODO: implement day formats in FormatDateEN()
-	// ReplaceWord(ret, "dddd", "d", MatchCase)
-	// ReplaceWord(ret, "Dddd", "f", MatchCase)
-	// ReplaceWord(ret, "DDDD", "h", MatchCase)
-	// ReplaceWord(ret, "ddd", "c", MatchCase)
-	// ReplaceWord(ret, "Ddd", "e", MatchCase)
-	// ReplaceWord(ret, "DDD", "g", MatchCase)
-	/*TODO: create new code that can use this function
-	  change := func(word string, caseMode CaseMode, to string) {
-	      has := Contains
-	      if caseMode == IgnoreCase {
-	          has = ContainsI
-	      }
-	      if has(ret, word) {
-	          ret = ReplaceWord(ret, word, to, caseMode)
-	      }
-	  }
-	*/
-	// day (2 digit)
-	if ContainsI(ret, "dd") {
-		ret = ReplaceWord(ret, "dd", fmt.Sprintf("%02d", day), IgnoreCase)
-	}
-	// day (1 or 2 digits)
-	if ContainsI(ret, "d") {
-		ret = ReplaceWord(ret, "d", String(day), IgnoreCase)
-	}
-	// month (full)
-	if strings.Contains(ret, "MMMM") {
-		ret = ReplaceWord(ret, "MMMM",
-			strings.ToUpper(MonthNameEN(month)), MatchCase)
-	}
-	if strings.Contains(ret, "Mmmm") {
-		ret = ReplaceWord(ret, "Mmmm", MonthNameEN(month), MatchCase)
-	}
-	if strings.Contains(ret, "mmmm") {
-		ret = ReplaceWord(ret, "mmmm",
-			strings.ToLower(MonthNameEN(month)), MatchCase)
-	}
-	// month (3 letters)
-	if strings.Contains(ret, "MMM") {
-		ret = ReplaceWord(ret, "MMM",
-			strings.ToUpper(First(MonthNameEN(month), 3)), MatchCase)
-	}
-	if strings.Contains(ret, "Mmm") {
-		ret = ReplaceWord(ret, "Mmm", First(MonthNameEN(month), 3), MatchCase)
-	}
-	if strings.Contains(ret, "mmm") {
-		ret = ReplaceWord(ret, "mmm",
-			strings.ToLower(First(MonthNameEN(month), 3)), MatchCase)
-	}
-	// month (2 digits)
-	if ContainsI(ret, "mm") {
-		ret = ReplaceWord(ret, "mm", fmt.Sprintf("%02d", month), IgnoreCase)
-	}
-	// month (1 or 2 digits)
-	if ContainsI(ret, "m") {
-		ret = ReplaceWord(ret, "m", String(month), IgnoreCase)
-	}
-	// year (4 digits)
-	if ContainsI(ret, "YYYY") {
-		ret = ReplaceWord(ret, "YYYY", fmt.Sprintf("%04d", year), IgnoreCase)
-	}
-	// year (2 digits)
-	if ContainsI(ret, "YY") {
-		ret = ReplaceWord(ret, "YY", Last(String(year), 2), IgnoreCase)
+	pat, err := DatePatternE(dateFormatWords(format))
+	if err != nil {
+		mod.Error(err)
+		return ""
 	}
-	return ret
+	return pat.Format(date)
 } //                                                                FormatDateEN
 
 // IsDate returns true if the specified value can be converted to a date.
@@ -487,6 +357,8 @@ func MthYear(value interface{}) string {
 } //                                                                     MthYear
 
 // ParseDate reads a date string and returns the year, month and day number.
+// Returns zeros if the date can not be read. To get an error describing
+// the problem, or to read dates in other orders, use DateParser.
 func ParseDate(s string) (year, month, day int) {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -638,6 +510,192 @@ func YMD(t time.Time) string {
 // -----------------------------------------------------------------------------
 // # Private Functions
 
+// dateFormatWords quotes the words in a FormatDateEN format that are
+// not made only of date pattern fields, e.g. "Date: dd/mm/yyyy"
+// becomes "'Date': dd/mm/yyyy", so that DatePattern copies them as
+// they are. Quoted text and characters after a backslash are not
+// changed.
+func dateFormatWords(format string) string {
+	var (
+		sb strings.Builder
+		rs = []rune(format)
+	)
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case c == '\\' && i+1 < len(rs):
+			sb.WriteString(string(rs[i : i+2]))
+			i += 2
+		case c == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != '\'' {
+				j++
+			}
+			if j < len(rs) {
+				j++
+			}
+			sb.WriteString(string(rs[i:j]))
+			i = j
+		case unicode.IsLetter(c):
+			j := i + 1
+			for j < len(rs) && unicode.IsLetter(rs[j]) {
+				j++
+			}
+			word := string(rs[i:j])
+			pat, err := DatePatternE(word)
+			isFields := err == nil
+			for k := 0; isFields && k < len(pat.fields); k++ {
+				isFields = pat.fields[k].letter != 0
+			}
+			if !isFields {
+				word = "'" + word + "'"
+			}
+			sb.WriteString(word)
+			i = j
+		default:
+			sb.WriteRune(c)
+			i++
+		}
+	}
+	return sb.String()
+} //                                                             dateFormatWords
+
+// dateRangeAt implements DateRangeAt and DateRangeOfIn: it returns
+// the DateRange of string 's' with dates in time zone 'loc', unless
+// the string ends with a time zone. Relative expressions are resolved
+// using 'now'.
+func dateRangeAt(s string, now time.Time, loc *time.Location) DateRange {
+	// a time zone at the end overrides 'loc'; the time of day is ignored
+	s, _, zone := dateSplitZone(s)
+	if zone != nil {
+		loc, now = zone, now.In(zone)
+	}
+	// pre-format
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for _, sep := range []string{" ", ".", "/", "\\", "_"} {
+		for strings.Contains(s, sep) {
+			s = strings.ReplaceAll(s, sep, "-")
+		}
+	}
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "-")
+	}
+	for strings.Contains(s, "-TO-") {
+		s = strings.ReplaceAll(s, "-TO-", "~")
+	}
+	var (
+		y1, m1, d1 = 0, time.January, 1
+		y2, m2, d2 = 0, time.December, 31
+	)
+	// relative date or period, e.g. "yesterday" or "last 30 days"
+	if ret, ok := dateRangeRelative(s, now, loc); ok {
+		return ret
+	}
+	// fiscal year or ISO week, e.g. "FY2024" or "2024-W07"
+	if ret, ok := dateRangeFiscal(s, loc); ok {
+		return ret
+	}
+	// date range
+	if i := strings.Index(s, "~"); i != -1 {
+		var (
+			r1 = dateRangeAt(s[:i], now, loc)
+			r2 = dateRangeAt(s[i+1:], now, loc)
+		)
+		y1, m1, d1 = r1.From.Year(), r1.From.Month(), r1.From.Day()
+		y2, m2, d2 = r2.To.Year(), r2.To.Month(), r2.To.Day()
+	}
+	// year only
+	if len(s) == 4 {
+		dt, err := time.Parse("2006", s)
+		if err == nil {
+			y1, m1, d1 = dt.Year(), 1, 1
+			y2, m2, d2 = dt.Year(), 12, 31
+		}
+	}
+	// month only
+	if y1 == 0 {
+		for _, format := range []string{"Jan", "January"} {
+			dt, err := time.Parse(format, s)
+			if err == nil {
+				y1, m1, d1 = now.Year(), dt.Month(), 1
+				y2, m2, d2 = now.Year(), dt.Month(),
+					DaysInMonth(now.Year(), dt.Month())
+				break
+			}
+		}
+	}
+	// complete date
+	if y1 == 0 {
+		for _, format := range []string{
+			"2-1-2006",
+			"2-Jan-2006",
+			"2-January-2006",
+			"2006-01-02",
+			"2006-2-Jan",
+			"2006-2-January",
+			"2006-Jan-2",
+			"2006-January-2",
+			"Jan-2-2006",
+			"Jan-2006-2",
+		} {
+			dt, err := time.Parse(format, s)
+			if err == nil {
+				y1, m1, d1 = dt.Year(), dt.Month(), dt.Day()
+				y2, m2, d2 = dt.Year(), dt.Month(), dt.Day()
+				break
+			}
+		}
+	}
+	// month and year
+	if y1 == 0 {
+		for _, format := range []string{
+			"1-2006",
+			"2006-1",
+			"2006-Jan",
+			"2006-January",
+			"2006Jan",
+			"2006January",
+			"Jan-2006",
+			"Jan2006",
+			"January-2006",
+			"January2006",
+		} {
+			dt, err := time.Parse(format, s)
+			if err == nil {
+				y1, m1, d1 = dt.Year(), dt.Month(), 1
+				y2, m2, d2 = dt.Year(), dt.Month(),
+					DaysInMonth(dt.Year(), dt.Month())
+				break
+			}
+		}
+	}
+	// day and month
+	if y1 == 0 {
+		for _, format := range []string{
+			"2-Jan",
+			"2-January",
+			"2Jan",
+			"2January",
+			"Jan-2",
+			"Jan2",
+			"January-2",
+			"January2",
+		} {
+			dt, err := time.Parse(format, s)
+			if err == nil {
+				y1, m1, d1 = now.Year(), dt.Month(), dt.Day()
+				y2, m2, d2 = now.Year(), dt.Month(), dt.Day()
+				break
+			}
+		}
+	}
+	if y1 == 0 {
+		return DateRange{From: time.Time{}, To: time.Time{}}
+	}
+	return DateRange{From: time.Date(y1, m1, d1, 0, 0, 0, 0, loc),
+		To: time.Date(y2, m2, d2, 23, 59, 59, 999999999, loc)}
+} //                                                                 dateRangeAt
+
 // stringDate _ _
 func stringDate(value interface{}, format string) string {
 	const erv = ""
@@ -12,6 +12,7 @@ package zr
 //   Test_date_DateOf_
 //   Test_date_DateRangeOf_
 //   Test_date_DaysInMonth_
+//   Test_date_FormatDateEN_
 //   Test_date_IsDateOnly_
 //   Test_date_IsDate_
 //   Test_date_ParseDate_
@@ -265,6 +266,50 @@ func Test_date_DaysInMonth_(t *testing.T) {
 	TEqual(t, DaysInMonth(2016, 12), (31))
 } //                                                      Test_date_DaysInMonth_
 
+// go test --run Test_date_FormatDateEN_
+func Test_date_FormatDateEN_(t *testing.T) {
+	TBegin(t)
+	//
+	// FormatDateEN(format string, date time.Time) string
+	//
+	date := time.Date(2024, 3, 7, 9, 5, 0, 0, time.UTC)
+	test := func(format, expect string) {
+		got := FormatDateEN(format, date)
+		if got != expect {
+			TFailf(t, `FormatDateEN(%q) returned %q instead of %q`,
+				format, got, expect)
+		}
+	}
+	// formats supported by earlier versions
+	test("d/m/yyyy", "7/3/2024")
+	test("dd-mm-yy", "07-03-24")
+	test("YYYY-MM-DD", "2024-03-07")
+	test("d Mmm YYYY", "7 Mar 2024")
+	test("mmmm d, yyyy", "march 7, 2024")
+	test("Mmmm d", "March 7")
+	test("MMMM d", "MARCH 7")
+	//
+	// weekdays, times and ordinals
+	test("Dddd, do Mmmm yyyy", "Thursday, 7th March 2024")
+	test("Ddd hh:nn TT", "Thu 09:05 AM")
+	test("yyyy-'W'ww-'Q'q", "2024-W10-Q1")
+	test("h 'o''clock'", "9 o'clock")
+	//
+	// words that are not fields are kept as they are
+	test("Date: dd/mm/yyyy", "Date: 07/03/2024")
+	test("the d of Mmmm", "the 7 of March")
+	test("Week ww, yyyy \\Qq", "Week 10, 2024 Q1")
+	//
+	// invalid formats are logged
+	DisableErrors()
+	ec1 := GetErrorCount()
+	got := FormatDateEN("'d", date)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, got, "")
+	TEqual(t, ec2-ec1, 1)
+} //                                                     Test_date_FormatDateEN_
+
 // go test --run Test_date_IsDateOnly_
 func Test_date_IsDateOnly_(t *testing.T) {
 	TBegin(t)
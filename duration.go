@@ -0,0 +1,327 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                   zr/[duration.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Functions
+//   HumanDuration(d time.Duration, precision int) string
+//   HumanDurationRelative(d time.Duration, precision int) string
+//   ParseDurationEx(s string) (time.Duration, error)
+//
+// # Private Functions
+//   durationAdd(total time.Duration, num string, unit time.Duration) (
+//       time.Duration, bool,
+//   )
+//   durationText(d time.Duration, precision int, min time.Duration) (
+//       text string, rounded time.Duration,
+//   )
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// durationClockRx matches durations like "1:30", "01:30:00"
+	// or "01:30:00.250" (hours, minutes, seconds and fraction).
+	durationClockRx = regexp.MustCompile(
+		`^(\d+):(\d{2})(?::(\d{2}(?:\.\d+)?))?$`)
+
+	// durationISORx matches ISO 8601 durations without
+	// years and months, e.g. "P1DT2H", "PT30M" or "P2W".
+	durationISORx = regexp.MustCompile(
+		`^P(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?` +
+			`(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?` +
+			`(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+	// durationPartRx matches each number and unit in durations
+	// like "1d 4h", "2 weeks" or "1.5 hours, 10 minutes".
+	durationPartRx = regexp.MustCompile(`(\d+(?:\.\d+)?|\.\d+)\s*([a-zµμ]+)`)
+
+	// durationUnitNames maps the names of units in durations to their size.
+	durationUnitNames = map[string]time.Duration{
+		"ns": time.Nanosecond, "nsec": time.Nanosecond,
+		"nanosecond": time.Nanosecond,
+		"us":         time.Microsecond, "µs": time.Microsecond,
+		"μs": time.Microsecond, "usec": time.Microsecond,
+		"microsecond": time.Microsecond,
+		"ms":          time.Millisecond, "msec": time.Millisecond,
+		"millisecond": time.Millisecond,
+		"s":           time.Second, "sec": time.Second, "second": time.Second,
+		"m": time.Minute, "min": time.Minute, "minute": time.Minute,
+		"h": time.Hour, "hr": time.Hour, "hour": time.Hour,
+		"d": 24 * time.Hour, "day": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour,
+		"week": 7 * 24 * time.Hour,
+	}
+
+	// durationUnits lists the units used by HumanDuration, largest first.
+	durationUnits = []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+		{"millisecond", time.Millisecond},
+		{"microsecond", time.Microsecond},
+		{"nanosecond", time.Nanosecond},
+	}
+)
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// HumanDuration returns a human-friendly duration string, e.g.
+// "3 days, 4 hours" or "1 minute, 30 seconds". 'precision' is the
+// number of units to show, starting from the largest: the duration
+// is rounded to the smallest unit shown, so 3 days and 4 hours with
+// a precision of 1 gives "3 days". A precision less than 1 means 1.
+//
+// Units range from days to nanoseconds. Days are always 24 hours
+// long; weeks, months and years are not used because of this.
+// A negative duration starts with '-'.
+func HumanDuration(d time.Duration, precision int) string {
+	ret, _ := durationText(d, precision, time.Nanosecond)
+	if d < 0 {
+		ret = "-" + ret
+	}
+	return ret
+} //                                                               HumanDuration
+
+// HumanDurationRelative returns a human-friendly string describing
+// when something happened, or will happen, given the duration from
+// now: negative for the past, e.g. "2 minutes ago", and positive
+// for the future, e.g. "in 3 days, 4 hours". If the duration was
+// rounded, it is preceded by "about", e.g. "about 2 minutes ago".
+// Durations under half a second give "just now".
+//
+// Units range from days to seconds. 'precision' is the number
+// of units to show, like in HumanDuration().
+//
+// For example, to describe a time 't' in the past:
+//
+//	HumanDurationRelative(time.Until(t), 1)
+func HumanDurationRelative(d time.Duration, precision int) string {
+	ret, rounded := durationText(d, precision, time.Second)
+	if rounded == 0 {
+		return "just now"
+	}
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+	if rounded != abs {
+		ret = "about " + ret
+	}
+	if d < 0 {
+		return ret + " ago"
+	}
+	return "in " + ret
+} //                                                       HumanDurationRelative
+
+// ParseDurationEx reads a duration from a string, accepting more
+// formats than time.ParseDuration():
+//
+//	"1h30m", "1.5h"            Go durations (see time.ParseDuration)
+//	"1d 4h", "2 weeks"         numbers with units, which can be
+//	"1 day, 4 hours and 5 min" abbreviated and separated by spaces,
+//	                           commas or "and"
+//	"P1DT2H", "PT30M", "P2W"   ISO 8601 durations
+//	"01:30:00", "1:30"         hours, minutes and optional seconds
+//
+// Units are ns, us (µs), ms, s (sec, second), m (min, minute),
+// h (hr, hour), d (day) and w (wk, week), in singular or plural
+// and in any case. Days are 24 hours and weeks are 7 days long.
+// Years and months are not accepted, since their length varies.
+// Any of the formats can be preceded by '-' or '+'.
+//
+// Returns an error if the string is not a valid duration
+// or the duration is too long. Does not log errors.
+func ParseDurationEx(s string) (time.Duration, error) {
+	var (
+		src     = s
+		neg     = false
+		ret     time.Duration
+		invalid = fmt.Errorf("%s duration %q", EInvalid, src)
+	)
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = strings.TrimSpace(s[1:])
+	}
+	add := func(num string, unit time.Duration) bool {
+		sum, ok := durationAdd(ret, num, unit)
+		ret = sum
+		return ok
+	}
+	overflow := fmt.Errorf("%s duration %q", EOverflow, src)
+	upper := strings.ToUpper(s)
+	switch {
+	case s == "":
+		return 0, invalid
+	case s == "0":
+		return 0, nil
+	case strings.HasPrefix(upper, "P"):
+		m := durationISORx.FindStringSubmatch(upper)
+		if m == nil || upper == "P" || strings.HasSuffix(upper, "T") {
+			if date := strings.SplitN(upper, "T", 2)[0]; strings.ContainsAny(
+				date, "YM") {
+				return 0, fmt.Errorf("%s duration %q: years and months"+
+					" vary in length", EInvalid, src)
+			}
+			return 0, invalid
+		}
+		for i, unit := range []time.Duration{
+			7 * 24 * time.Hour, 24 * time.Hour,
+			time.Hour, time.Minute, time.Second,
+		} {
+			if m[i+1] != "" && !add(m[i+1], unit) {
+				return 0, overflow
+			}
+		}
+	case durationClockRx.MatchString(s):
+		m := durationClockRx.FindStringSubmatch(s)
+		mins, _ := strconv.Atoi(m[2])
+		secs, _ := strconv.ParseFloat("0"+m[3], 64)
+		if mins > 59 || secs >= 60 {
+			return 0, invalid
+		}
+		if !add(m[1], time.Hour) || !add(m[2], time.Minute) ||
+			(m[3] != "" && !add(m[3], time.Second)) {
+			return 0, overflow
+		}
+	default:
+		s = strings.ToLower(s)
+		tooLong := false
+		rest := durationPartRx.ReplaceAllStringFunc(s, func(part string) string {
+			m := durationPartRx.FindStringSubmatch(part)
+			unit, found := durationUnitNames[m[2]]
+			if !found && strings.HasSuffix(m[2], "s") {
+				unit, found = durationUnitNames[strings.TrimSuffix(m[2], "s")]
+			}
+			if !found {
+				return part
+			}
+			if !tooLong && !add(m[1], unit) {
+				tooLong = true
+			}
+			return " "
+		})
+		if tooLong {
+			return 0, overflow
+		}
+		rest = strings.NewReplacer(",", " ", " and ", " ").Replace(
+			" " + rest + " ")
+		if strings.TrimSpace(rest) != "" || !durationPartRx.MatchString(s) {
+			return 0, invalid
+		}
+	}
+	if neg {
+		ret = -ret
+	}
+	return ret, nil
+} //                                                             ParseDurationEx
+
+// -----------------------------------------------------------------------------
+// # Private Functions
+
+// durationAdd adds a number of units to 'total', where 'num' is a
+// number that may have a fraction, e.g. "1.5". Returns false if the
+// result is too large.
+func durationAdd(total time.Duration, num string, unit time.Duration) (
+	time.Duration, bool,
+) {
+	whole, frac := num, ""
+	if i := strings.IndexAny(num, ".,"); i != -1 {
+		whole, frac = num[:i], num[i+1:]
+	}
+	var n time.Duration
+	if whole != "" {
+		v, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || v > math.MaxInt64/int64(unit) {
+			return total, false
+		}
+		n = time.Duration(v) * unit
+	}
+	if frac != "" {
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return total, false
+		}
+		n += time.Duration(math.Round(f * float64(unit)))
+	}
+	if n < 0 || total > math.MaxInt64-n {
+		return total, false
+	}
+	return total + n, true
+} //                                                                 durationAdd
+
+// durationText returns the absolute value of 'd' as text for
+// HumanDuration and HumanDurationRelative, with 'precision' units
+// from days down to 'min', and the absolute duration after rounding.
+func durationText(d time.Duration, precision int, min time.Duration) (
+	text string, rounded time.Duration,
+) {
+	if precision < 1 {
+		precision = 1
+	}
+	if d == math.MinInt64 {
+		d++
+	}
+	if d < 0 {
+		d = -d
+	}
+	// find the largest and smallest units to show,
+	// rounding the duration to the smallest unit
+	last := len(durationUnits) - 1
+	for durationUnits[last].size < min {
+		last--
+	}
+	first := func(d time.Duration) int {
+		for i := 0; i < last; i++ {
+			if d >= durationUnits[i].size {
+				return i
+			}
+		}
+		return last
+	}
+	i := first(d)
+	if i+precision-1 < last {
+		last = i + precision - 1
+	}
+	rounded = d.Round(durationUnits[last].size)
+	if rounded < 0 { // Round() saturates, but let's be sure
+		rounded = d
+	}
+	var (
+		parts = []string{}
+		rest  = rounded
+	)
+	for i = first(rounded); i <= last; i++ {
+		unit := durationUnits[i]
+		n := rest / unit.size
+		rest -= n * unit.size
+		if n == 0 {
+			continue
+		}
+		s := fmt.Sprintf("%d %s", n, unit.name)
+		if n != 1 {
+			s += "s"
+		}
+		parts = append(parts, s)
+	}
+	if len(parts) == 0 {
+		return "0 seconds", rounded
+	}
+	return strings.Join(parts, ", "), rounded
+} //                                                                durationText
+
+// end
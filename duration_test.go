@@ -0,0 +1,199 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                              zr/[duration_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+//   Test_durn_HumanDuration_
+//   Test_durn_HumanDurationRelative_
+//   Test_durn_ParseDurationEx_
+//   Test_durn_ParseDurationEx_errors_
+
+//  to test all items in duration.go use:
+//      go test --run Test_durn_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const durnDay = 24 * time.Hour
+
+// go test --run Test_durn_HumanDuration_
+func Test_durn_HumanDuration_(t *testing.T) {
+	TBegin(t)
+	//
+	// HumanDuration(d time.Duration, precision int) string
+	//
+	test := func(d time.Duration, precision int, expect string) {
+		got := HumanDuration(d, precision)
+		if got != expect {
+			TFailf(t, `HumanDuration(%v, %d) returned %q instead of %q`,
+				d, precision, got, expect)
+		}
+	}
+	test(0, 1, "0 seconds")
+	test(time.Nanosecond, 1, "1 nanosecond")
+	test(250*time.Millisecond, 1, "250 milliseconds")
+	test(3*time.Microsecond, 2, "3 microseconds")
+	test(time.Second, 1, "1 second")
+	//
+	test(3*durnDay+4*time.Hour, 1, "3 days")
+	test(3*durnDay+4*time.Hour, 2, "3 days, 4 hours")
+	test(3*durnDay+4*time.Hour, 5, "3 days, 4 hours")
+	test(3*durnDay+12*time.Hour, 1, "4 days")
+	test(90*time.Second, 2, "1 minute, 30 seconds")
+	test(90*time.Second, 1, "2 minutes")
+	test(89*time.Second, 1, "1 minute")
+	test(90*time.Second, 0, "2 minutes")
+	test(1500*time.Millisecond, 1, "2 seconds")
+	test(1500*time.Millisecond, 2, "1 second, 500 milliseconds")
+	//
+	// zero units are not shown, but count towards the precision
+	test(time.Hour+5*time.Second, 2, "1 hour")
+	test(time.Hour+5*time.Second, 3, "1 hour, 5 seconds")
+	//
+	// rounding up to the next unit
+	test(59600*time.Millisecond, 1, "1 minute")
+	test(23*time.Hour+59*time.Minute+31*time.Second, 2, "1 day")
+	//
+	test(-90*time.Second, 2, "-1 minute, 30 seconds")
+	test(math.MaxInt64, 7, "106751 days, 23 hours, 47 minutes, 16 seconds, "+
+		"854 milliseconds, 775 microseconds, 807 nanoseconds")
+	test(math.MinInt64, 3, "-106751 days, 23 hours, 47 minutes")
+	//
+	// the output can be read by ParseDurationEx
+	for _, d := range []time.Duration{
+		time.Nanosecond, 90 * time.Second, 3*durnDay + 4*time.Hour,
+		-(time.Hour + 5*time.Second), math.MaxInt64,
+	} {
+		got, err := ParseDurationEx(HumanDuration(d, 7))
+		TEqual(t, err, nil)
+		TEqual(t, got, d)
+	}
+} //                                                    Test_durn_HumanDuration_
+
+// go test --run Test_durn_HumanDurationRelative_
+func Test_durn_HumanDurationRelative_(t *testing.T) {
+	TBegin(t)
+	//
+	// HumanDurationRelative(d time.Duration, precision int) string
+	//
+	test := func(d time.Duration, precision int, expect string) {
+		got := HumanDurationRelative(d, precision)
+		if got != expect {
+			TFailf(t, `HumanDurationRelative(%v, %d) returned %q`+
+				` instead of %q`, d, precision, got, expect)
+		}
+	}
+	test(0, 1, "just now")
+	test(-400*time.Millisecond, 1, "just now")
+	test(400*time.Millisecond, 1, "just now")
+	test(-600*time.Millisecond, 1, "about 1 second ago")
+	test(-2*time.Minute, 1, "2 minutes ago")
+	test(-(2*time.Minute + 5*time.Second), 1, "about 2 minutes ago")
+	test(-(2*time.Minute + 5*time.Second), 2, "2 minutes, 5 seconds ago")
+	test(-90*time.Second, 1, "about 2 minutes ago")
+	test(3*durnDay+4*time.Hour, 2, "in 3 days, 4 hours")
+	test(3*durnDay+4*time.Hour+10*time.Minute, 2, "in about 3 days, 4 hours")
+	test(1500*time.Millisecond, 3, "in about 2 seconds")
+	test(-(durnDay + 20*time.Millisecond), 1, "about 1 day ago")
+	//
+	// a time in the past
+	then := time.Now().Add(-5*time.Minute - 10*time.Second)
+	TEqual(t, HumanDurationRelative(time.Until(then), 1), "about 5 minutes ago")
+} //                                            Test_durn_HumanDurationRelative_
+
+// go test --run Test_durn_ParseDurationEx_
+func Test_durn_ParseDurationEx_(t *testing.T) {
+	TBegin(t)
+	//
+	// ParseDurationEx(s string) (time.Duration, error)
+	//
+	test := func(s string, expect time.Duration) {
+		got, err := ParseDurationEx(s)
+		if err != nil || got != expect {
+			TFailf(t, `ParseDurationEx(%q) returned %v, %v instead of %v`,
+				s, got, err, expect)
+		}
+	}
+	// Go durations
+	test("0", 0)
+	test("1h30m", 90*time.Minute)
+	test("1.5h", 90*time.Minute)
+	test(".5h", 30*time.Minute)
+	test("250ms", 250*time.Millisecond)
+	test("3µs", 3*time.Microsecond)
+	test("1h2m3s4ms5us6ns", time.Hour+2*time.Minute+3*time.Second+
+		4*time.Millisecond+5*time.Microsecond+6*time.Nanosecond)
+	test("9223372036854775807ns", math.MaxInt64)
+	//
+	// numbers with units
+	test("1d 4h", 28*time.Hour)
+	test("2 weeks", 14*durnDay)
+	test("1 day, 4 hours and 5 min", 28*time.Hour+5*time.Minute)
+	test("2hrs 15mins", 2*time.Hour+15*time.Minute)
+	test("1 Hour", time.Hour)
+	test("10 NS", 10*time.Nanosecond)
+	test("1.25 days", 30*time.Hour)
+	test("3 secs", 3*time.Second)
+	test("1w1d", 8*durnDay)
+	//
+	// ISO 8601
+	test("P1DT2H", 26*time.Hour)
+	test("PT30M", 30*time.Minute)
+	test("P2W", 14*durnDay)
+	test("PT0.5S", 500*time.Millisecond)
+	test("P1,5D", 36*time.Hour)
+	test("p1dt2h3m4s", 26*time.Hour+3*time.Minute+4*time.Second)
+	test("PT36H", 36*time.Hour)
+	//
+	// hours, minutes and seconds
+	test("01:30:00", 90*time.Minute)
+	test("1:30", 90*time.Minute)
+	test("0:00:01.5", 1500*time.Millisecond)
+	test("100:00", 100*time.Hour)
+	//
+	// signs
+	test("-1d", -durnDay)
+	test("+2h", 2*time.Hour)
+	test("-P1D", -durnDay)
+	test(" - 01:30 ", -90*time.Minute)
+} //                                                  Test_durn_ParseDurationEx_
+
+// go test --run Test_durn_ParseDurationEx_errors_
+func Test_durn_ParseDurationEx_errors_(t *testing.T) {
+	TBegin(t)
+	//
+	// ParseDurationEx(s string) (time.Duration, error)
+	//
+	test := func(s, expectErr string) {
+		got, err := ParseDurationEx(s)
+		if err == nil || got != 0 || !strings.Contains(err.Error(), expectErr) {
+			TFailf(t, `ParseDurationEx(%q) returned %v, %v`, s, got, err)
+		}
+	}
+	for _, s := range []string{
+		"", " ", "-", "90", "1h30", "1 fortnight", "h", "1.h", "1h, x",
+		"P", "PT", "P1H", "PT1D", "P1DT", "P-1D", "1:60", "1:30:60",
+		"1:3", "1:30:", ":30", "1h 01:30",
+	} {
+		test(s, EInvalid+" duration")
+	}
+	test("P1Y", "years and months vary in length")
+	test("P1M", "years and months vary in length")
+	test("P1Y2M3DT4H", "years and months vary in length")
+	test("10000000d", EOverflow)
+	test("9223372036854775807ns 1ns", EOverflow)
+	test("PT9999999999H", EOverflow)
+	test("99999999999:00", EOverflow)
+} //                                           Test_durn_ParseDurationEx_errors_
+
+// end
@@ -0,0 +1,346 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                      zr/[money.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # ISO 4217 Currency Codes:
+//   ISO4217MinorUnits = map[string]int
+//   CurrencyMinorUnits(code string) (int, bool)
+//   IsCurrencyCode(code string) bool
+//
+// # Money Type:
+//   Money struct
+//
+// # Money Factories:
+//   MoneyE(amount interface{}, code string) (Money, error)
+//   MoneyOf(amount interface{}, code string) Money
+//
+// # Properties:
+//   (m Money) Amount() Currency
+//   (m Money) Code() string
+//   (m Money) MinorUnits() int
+//
+// # String Output:
+//   (m Money) Fmt() string
+//   (m Money) GoString() string
+//   (m Money) InWordsEN(fmt string, opts ...InWordsOptions) string
+//   (m Money) String() string
+//
+// # Arithmetic:
+//   (m Money) Add(nums ...Money) Money
+//   (m Money) AddE(nums ...Money) (Money, error)
+//   (m Money) Sub(nums ...Money) Money
+//   (m Money) SubE(nums ...Money) (Money, error)
+//
+// # JSON:
+//   (m Money) MarshalJSON() ([]byte, error)
+//   (m *Money) UnmarshalJSON(data []byte) error
+//
+// # Helper Function
+//   moneyCheckCodes(m Money, nums []Money) error
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// # ISO 4217 Currency Codes:
+
+// ISO4217MinorUnits maps every active ISO 4217 currency code to its
+// minor unit exponent, i.e. the number of decimal places used by the
+// currency (2 for USD cents, 0 for JPY, 3 for KWD fils, etc).
+//
+// Codes for which ISO 4217 does not define a minor unit (precious
+// metals, bond market units, testing codes) are mapped to -1,
+// which makes Fmt() output a varying number of decimals.
+var ISO4217MinorUnits = map[string]int{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2,
+	"AUD": 2, "AWG": 2, "AZN": 2, "BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2,
+	"BHD": 3, "BIF": 0, "BMD": 2, "BND": 2, "BOB": 2, "BOV": 2, "BRL": 2,
+	"BSD": 2, "BTN": 2, "BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2,
+	"CHE": 2, "CHF": 2, "CHW": 2, "CLF": 4, "CLP": 0, "CNY": 2, "COP": 2,
+	"COU": 2, "CRC": 2, "CUC": 2, "CUP": 2, "CVE": 2, "CZK": 2, "DJF": 0,
+	"DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2, "EUR": 2,
+	"FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2,
+	"GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2,
+	"JOD": 3, "JPY": 0, "KES": 2, "KGS": 2, "KHR": 2, "KMF": 0, "KPW": 2,
+	"KRW": 0, "KWD": 3, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2,
+	"LRD": 2, "LSL": 2, "LYD": 3, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2,
+	"MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2,
+	"MXN": 2, "MXV": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2,
+	"NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2, "PGK": 2,
+	"PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0, "QAR": 2, "RON": 2, "RSD": 2,
+	"RUB": 2, "RWF": 0, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2,
+	"SGD": 2, "SHP": 2, "SLE": 2, "SOS": 2, "SRD": 2, "SSP": 2, "STN": 2,
+	"SVC": 2, "SYP": 2, "SZL": 2, "THB": 2, "TJS": 2, "TMT": 2, "TND": 3,
+	"TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2, "UAH": 2, "UGX": 0,
+	"USD": 2, "USN": 2, "UYI": 0, "UYU": 2, "UYW": 4, "UZS": 2, "VED": 2,
+	"VES": 2, "VND": 0, "VUV": 0, "WST": 2, "XAF": 0, "XCD": 2, "XCG": 2,
+	"XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2, "ZWL": 2,
+	//
+	// no minor unit defined:
+	"XAG": -1, "XAU": -1, "XBA": -1, "XBB": -1, "XBC": -1, "XBD": -1,
+	"XDR": -1, "XPD": -1, "XPT": -1, "XSU": -1, "XTS": -1, "XUA": -1,
+	"XXX": -1,
+} //                                                           ISO4217MinorUnits
+
+// CurrencyMinorUnits returns the number of decimal places used by the
+// currency specified by an ISO 4217 code, and true if the code is valid.
+// The code is not case-sensitive. If the code is not found returns 0, false.
+func CurrencyMinorUnits(code string) (int, bool) {
+	ret, found := ISO4217MinorUnits[strings.ToUpper(strings.TrimSpace(code))]
+	return ret, found
+} //                                                          CurrencyMinorUnits
+
+// IsCurrencyCode returns true if code is a valid ISO 4217 currency code.
+// The code is not case-sensitive.
+func IsCurrencyCode(code string) bool {
+	_, ret := CurrencyMinorUnits(code)
+	return ret
+} //                                                              IsCurrencyCode
+
+// -----------------------------------------------------------------------------
+// # Money Type:
+
+// Money represents a Currency amount in a specific currency,
+// identified by its ISO 4217 code (e.g. "USD", "EUR", "JPY").
+//
+// Arithmetic is only allowed between Money values having the
+// same currency code. Mixing currencies is reported as an error.
+type Money struct {
+	amount Currency
+	code   string
+} //                                                                       Money
+
+// -----------------------------------------------------------------------------
+// # Money Factories:
+
+// MoneyE creates a Money value from an amount and an ISO 4217 code.
+// The amount can be any value accepted by CurrencyE().
+// The code is not case-sensitive and is stored in uppercase.
+//
+// If the amount can not be converted or the currency code is not
+// valid, returns a zero-value Money and an error. Does not log the error.
+//
+func MoneyE(amount interface{}, code string) (Money, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !IsCurrencyCode(code) {
+		return Money{}, fmt.Errorf("%s currency code: %q", EInvalid, code)
+	}
+	n, err := CurrencyE(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: n, code: code}, nil
+} //                                                                      MoneyE
+
+// MoneyOf creates a Money value from an amount and an ISO 4217 code.
+// The amount can be any value accepted by CurrencyOf().
+//
+// If the amount can not be converted or the currency code is
+// not valid, returns a zero-value Money and logs an error.
+//
+func MoneyOf(amount interface{}, code string) Money {
+	ret, err := MoneyE(amount, code)
+	if err != nil {
+		mod.Error(err)
+	}
+	return ret
+} //                                                                     MoneyOf
+
+// -----------------------------------------------------------------------------
+// # Properties:
+
+// Amount returns the amount of money as a Currency value.
+func (m Money) Amount() Currency {
+	return m.amount
+} //                                                                      Amount
+
+// Code returns the ISO 4217 currency code of the money value.
+func (m Money) Code() string {
+	return m.code
+} //                                                                        Code
+
+// MinorUnits returns the number of decimal places used by
+// the currency, or -1 if the currency has no minor unit.
+func (m Money) MinorUnits() int {
+	ret, found := CurrencyMinorUnits(m.code)
+	if !found {
+		return -1
+	}
+	return ret
+} //                                                                  MinorUnits
+
+// -----------------------------------------------------------------------------
+// # String Output:
+
+// Fmt returns the money value as a string prefixed with the currency
+// code and formatted with the number of decimals used by the currency.
+// E.g. "USD 1,234.50", "JPY 1,235" or "KWD 1.250"
+func (m Money) Fmt() string {
+	s := m.amount.Fmt(m.MinorUnits())
+	if m.code == "" {
+		return s
+	}
+	return m.code + " " + s
+} //                                                                         Fmt
+
+// GoString outputs the value as a Go language string,
+// It implements the fmt.GoStringer interface.
+func (m Money) GoString() string {
+	return "zr.MoneyOf(" + m.amount.String() + ", " +
+		fmt.Sprintf("%q", m.code) + ")"
+} //                                                                    GoString
+
+// InWordsEN returns the money value as an English description in words.
+// See Currency.InWordsEN() for a description of the arguments.
+func (m Money) InWordsEN(fmt string, opts ...InWordsOptions) string {
+	return m.amount.InWordsEN(fmt, opts...)
+} //                                                                   InWordsEN
+
+// String returns the currency code followed by the amount
+// and implements the fmt.Stringer interface. E.g. "USD 1234.5"
+func (m Money) String() string {
+	if m.code == "" {
+		return m.amount.String()
+	}
+	return m.code + " " + m.amount.String()
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Arithmetic:
+
+// Add adds one or more money values and returns the result.
+// The object's value isn't changed.
+//
+// If any of the values has a different currency code, or the result
+// overflows Currency, logs an error and returns the object's value
+// without adding anything.
+//
+func (m Money) Add(nums ...Money) Money {
+	ret, err := m.AddE(nums...)
+	if err != nil {
+		mod.Error(err)
+	}
+	return ret
+} //                                                                         Add
+
+// AddE adds one or more money values and returns the result.
+// The object's value isn't changed.
+//
+// If any of the values has a different currency code, or the result
+// overflows Currency, returns the object's value and an error.
+// Does not log the error.
+//
+func (m Money) AddE(nums ...Money) (Money, error) {
+	if err := moneyCheckCodes(m, nums); err != nil {
+		return m, err
+	}
+	amounts := make([]Currency, len(nums))
+	for i, num := range nums {
+		amounts[i] = num.amount
+	}
+	amount, err := m.amount.AddE(amounts...)
+	if err != nil {
+		return m, err
+	}
+	m.amount = amount
+	return m, nil
+} //                                                                        AddE
+
+// Sub subtracts one or more money values and returns the result.
+// The object's value isn't changed.
+//
+// If any of the values has a different currency code, or the result
+// overflows Currency, logs an error and returns the object's value
+// without subtracting anything.
+//
+func (m Money) Sub(nums ...Money) Money {
+	ret, err := m.SubE(nums...)
+	if err != nil {
+		mod.Error(err)
+	}
+	return ret
+} //                                                                         Sub
+
+// SubE subtracts one or more money values and returns the result.
+// The object's value isn't changed.
+//
+// If any of the values has a different currency code, or the result
+// overflows Currency, returns the object's value and an error.
+// Does not log the error.
+//
+func (m Money) SubE(nums ...Money) (Money, error) {
+	if err := moneyCheckCodes(m, nums); err != nil {
+		return m, err
+	}
+	amounts := make([]Currency, len(nums))
+	for i, num := range nums {
+		amounts[i] = num.amount
+	}
+	amount, err := m.amount.SubE(amounts...)
+	if err != nil {
+		return m, err
+	}
+	m.amount = amount
+	return m, nil
+} //                                                                        SubE
+
+// -----------------------------------------------------------------------------
+// # JSON:
+
+// moneyJSON is the JSON representation of Money.
+type moneyJSON struct {
+	Amount   Currency `json:"amount"`
+	Currency string   `json:"currency"`
+} //                                                                   moneyJSON
+
+// MarshalJSON returns the JSON encoding of zr.Money.
+// E.g. {"amount":1234.5,"currency":"USD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.amount, Currency: m.code})
+} //                                                                 MarshalJSON
+
+// UnmarshalJSON unmarshals a JSON description of zr.Money.
+// Returns an error if the currency code is not a valid ISO 4217 code.
+// This method alters the object's value.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	//   ^  don't remove pointer receiver, it is necessary
+	if m == nil {
+		return errors.New(ENilReceiver)
+	}
+	var v moneyJSON
+	err := mod.json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	code := strings.ToUpper(strings.TrimSpace(v.Currency))
+	if !IsCurrencyCode(code) {
+		return fmt.Errorf("%s currency code: %q", EInvalid, v.Currency)
+	}
+	m.amount, m.code = v.Amount, code
+	return nil
+} //                                                               UnmarshalJSON
+
+// -----------------------------------------------------------------------------
+// # Helper Function
+
+// moneyCheckCodes returns an error if the currency
+// code of any value in nums differs from that of m.
+func moneyCheckCodes(m Money, nums []Money) error {
+	for _, num := range nums {
+		if num.code != m.code {
+			return fmt.Errorf("%s: %s and %s",
+				ECurrencyMismatch, m.code, num.code)
+		}
+	}
+	return nil
+} //                                                             moneyCheckCodes
+
+// end
@@ -0,0 +1,271 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                 zr/[money_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # ISO 4217 Currency Codes:
+//   Test_mony_CurrencyMinorUnits_
+//
+// # Money Factories:
+//   Test_mony_MoneyE_
+//
+// # String Output:
+//   Test_mony_Money_Fmt_
+//   Test_mony_Money_String_
+//
+// # Arithmetic:
+//   Test_mony_Money_Add_
+//   Test_mony_Money_Sub_
+//
+// # JSON:
+//   Test_mony_Money_JSON_
+
+//  to test all items in money.go use:
+//      go test --run Test_mony_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # ISO 4217 Currency Codes:
+
+// go test --run Test_mony_CurrencyMinorUnits_
+func Test_mony_CurrencyMinorUnits_(t *testing.T) {
+	TBegin(t)
+	//
+	// CurrencyMinorUnits(code string) (int, bool)
+	//
+	test := func(code string, expectUnits int, expectFound bool) {
+		units, found := CurrencyMinorUnits(code)
+		if units != expectUnits || found != expectFound {
+			TFailf(t, `CurrencyMinorUnits(%q) returned %d, %v`+
+				` instead of %d, %v`,
+				code, units, found, expectUnits, expectFound)
+		}
+	}
+	test("USD", 2, true)
+	test("eur", 2, true)
+	test(" GBP ", 2, true)
+	test("JPY", 0, true)
+	test("KRW", 0, true)
+	test("KWD", 3, true)
+	test("BHD", 3, true)
+	test("CLF", 4, true)
+	test("XAU", -1, true)
+	test("", 0, false)
+	test("US", 0, false)
+	test("ABC", 0, false)
+	//
+	// every code must consist of 3 uppercase letters
+	for code, units := range ISO4217MinorUnits {
+		if len(code) != 3 || strings.ToUpper(code) != code {
+			TFail(t, `Invalid code in ISO4217MinorUnits: `, code)
+		}
+		if units < -1 || units > 4 {
+			TFail(t, `Invalid minor units for `, code, `: `, units)
+		}
+	}
+} //                                               Test_mony_CurrencyMinorUnits_
+
+// -----------------------------------------------------------------------------
+// # Money Factories:
+
+// go test --run Test_mony_MoneyE_
+func Test_mony_MoneyE_(t *testing.T) {
+	TBegin(t)
+	//
+	// MoneyE(amount interface{}, code string) (Money, error)
+	//
+	{
+		m, err := MoneyE("12.34", "usd")
+		TTrue(t, err == nil)
+		TEqual(t, m.Code(), "USD")
+		TEqual(t, m.Amount(), cur("12.34"))
+	}
+	{
+		m, err := MoneyE(5, "ZZZ")
+		TTrue(t, err != nil)
+		TEqual(t, m, Money{})
+	}
+	{
+		m, err := MoneyE([]int{1}, "USD")
+		TTrue(t, err != nil)
+		TEqual(t, m, Money{})
+	}
+} //                                                           Test_mony_MoneyE_
+
+// -----------------------------------------------------------------------------
+// # String Output:
+
+// go test --run Test_mony_Money_Fmt_
+func Test_mony_Money_Fmt_(t *testing.T) {
+	TBegin(t)
+	//
+	// (m Money) Fmt() string
+	//
+	test := func(amount interface{}, code, expect string) {
+		TEqual(t, MoneyOf(amount, code).Fmt(), expect)
+	}
+	test("1234.5", "USD", "USD 1,234.50")
+	test("-1234.5", "EUR", "EUR -1,234.50")
+	test("1234", "JPY", "JPY 1,234")
+	test("1.25", "KWD", "KWD 1.250")
+	test("0.1234", "CLF", "CLF 0.1234")
+	test("1.5", "XAU", "XAU 1.5")
+	TEqual(t, Money{}.Fmt(), "0")
+} //                                                        Test_mony_Money_Fmt_
+
+// go test --run Test_mony_Money_String_
+func Test_mony_Money_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (m Money) String() string
+	//
+	TEqual(t, MoneyOf("1234.5", "USD").String(), "USD 1234.5")
+	TEqual(t, MoneyOf("-0.01", "GBP").String(), "GBP -0.01")
+	TEqual(t, MoneyOf("7", "EUR").GoString(), `zr.MoneyOf(7, "EUR")`)
+	TEqual(t, MoneyOf("11.02", "USD").InWordsEN("Dollar;;Cent"),
+		"Eleven Dollars and Two Cents")
+} //                                                     Test_mony_Money_String_
+
+// -----------------------------------------------------------------------------
+// # Arithmetic:
+
+// go test --run Test_mony_Money_Add_
+func Test_mony_Money_Add_(t *testing.T) {
+	TBegin(t)
+	//
+	// (m Money) Add(nums ...Money) Money
+	// (m Money) AddE(nums ...Money) (Money, error)
+	//
+	var (
+		a = MoneyOf("10.50", "USD")
+		b = MoneyOf("0.25", "USD")
+		c = MoneyOf("1", "EUR")
+	)
+	{
+		got, err := a.AddE(b, b)
+		TTrue(t, err == nil)
+		TEqual(t, got, MoneyOf("11", "USD"))
+		TEqual(t, a, MoneyOf("10.5", "USD")) // must not mutate
+	}
+	{
+		got, err := a.AddE(b, c)
+		TTrue(t, err != nil)
+		TTrue(t, strings.HasPrefix(err.Error(), ECurrencyMismatch))
+		TEqual(t, got, a)
+	}
+	{
+		DisableErrors()
+		ec := GetErrorCount()
+		got := a.Add(c)
+		TEqual(t, GetErrorCount(), ec+1)
+		TEqual(t, got, a)
+		EnableErrors()
+	}
+	{ // overflow is returned, not logged
+		large := MoneyOf("900000000000000", "USD")
+		DisableErrors()
+		ec := GetErrorCount()
+		got, err := large.AddE(large)
+		TEqual(t, GetErrorCount(), ec)
+		EnableErrors()
+		TTrue(t, err != nil)
+		TTrue(t, strings.HasPrefix(err.Error(), EOverflow))
+		TEqual(t, got, large)
+	}
+} //                                                        Test_mony_Money_Add_
+
+// go test --run Test_mony_Money_Sub_
+func Test_mony_Money_Sub_(t *testing.T) {
+	TBegin(t)
+	//
+	// (m Money) Sub(nums ...Money) Money
+	// (m Money) SubE(nums ...Money) (Money, error)
+	//
+	var (
+		a = MoneyOf("10.50", "EUR")
+		b = MoneyOf("0.25", "EUR")
+		c = MoneyOf("1", "USD")
+	)
+	{
+		got, err := a.SubE(b, b)
+		TTrue(t, err == nil)
+		TEqual(t, got, MoneyOf("10", "EUR"))
+	}
+	{
+		got, err := a.SubE(c)
+		TTrue(t, err != nil)
+		TEqual(t, got, a)
+	}
+	{
+		DisableErrors()
+		ec := GetErrorCount()
+		got := a.Sub(c)
+		TEqual(t, GetErrorCount(), ec+1)
+		TEqual(t, got, a)
+		EnableErrors()
+	}
+	{ // overflow is returned, not logged
+		large := MoneyOf("-900000000000000", "EUR")
+		DisableErrors()
+		ec := GetErrorCount()
+		got, err := large.SubE(MoneyOf("900000000000000", "EUR"))
+		TEqual(t, GetErrorCount(), ec)
+		EnableErrors()
+		TTrue(t, err != nil)
+		TTrue(t, strings.HasPrefix(err.Error(), EOverflow))
+		TEqual(t, got, large)
+	}
+} //                                                        Test_mony_Money_Sub_
+
+// -----------------------------------------------------------------------------
+// # JSON:
+
+// go test --run Test_mony_Money_JSON_
+func Test_mony_Money_JSON_(t *testing.T) {
+	TBegin(t)
+	//
+	// (m Money) MarshalJSON() ([]byte, error)
+	// (m *Money) UnmarshalJSON(data []byte) error
+	//
+	{
+		m := MoneyOf("1234.5", "USD")
+		data, err := json.Marshal(m)
+		TTrue(t, err == nil)
+		TEqual(t, string(data), `{"amount":1234.5,"currency":"USD"}`)
+		//
+		var back Money
+		err = json.Unmarshal(data, &back)
+		TTrue(t, err == nil)
+		TEqual(t, back, m)
+	}
+	{
+		var m Money
+		err := json.Unmarshal([]byte(`{"amount":1,"currency":"eur"}`), &m)
+		TTrue(t, err == nil)
+		TEqual(t, m, MoneyOf(1, "EUR"))
+	}
+	{
+		var m Money
+		err := json.Unmarshal([]byte(`{"amount":1,"currency":"???"}`), &m)
+		TTrue(t, err != nil)
+		TEqual(t, m, Money{})
+	}
+	{
+		var m *Money
+		err := m.UnmarshalJSON([]byte(`{"amount":1,"currency":"USD"}`))
+		TTrue(t, err != nil)
+	}
+} //                                                       Test_mony_Money_JSON_
+
+// end
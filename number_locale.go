@@ -0,0 +1,241 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                              zr/[number_locale.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Types
+//   NegativeStyle int
+//   NumberLocale struct
+//
+// # Built-in Locales
+//   NumberLocales = map[string]NumberLocale
+//   NumberLocaleOf(tag string) (NumberLocale, bool)
+//
+// # Formatting Functions
+//   CommaDelimitLocale(number string, decimalPlaces int, loc NumberLocale) string
+//   (n Currency) FmtLocale(
+//       decimalPlaces int, loc NumberLocale, optSymbol ...string,
+//   ) string
+//
+// # Internal Methods/Functions
+//   (loc NumberLocale) localize(number, symbol string) string
+//   (loc NumberLocale) groupDigits(digits string) string
+
+import (
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// # Types
+
+// NegativeStyle specifies how negative numbers are written.
+type NegativeStyle int
+
+const (
+	// NegativeMinus writes a leading minus sign, e.g. "-1,234.50"
+	NegativeMinus NegativeStyle = iota
+
+	// NegativeParentheses encloses negative numbers in parentheses,
+	// as usual in accounting, e.g. "(1,234.50)"
+	NegativeParentheses
+
+	// NegativeTrailingMinus writes a trailing minus sign, e.g. "1,234.50-"
+	NegativeTrailingMinus
+)
+
+// NumberLocale describes how numbers and amounts are written
+// in a particular language or region.
+type NumberLocale struct {
+	// GroupSeparator is written between digit groups, e.g. "," or "."
+	GroupSeparator string
+
+	// DecimalSeparator is written before the decimals, e.g. "." or ","
+	// If blank, "." is used.
+	DecimalSeparator string
+
+	// Grouping specifies the sizes of digit groups, starting from the
+	// decimal separator. The last size is repeated for the remaining
+	// digits. E.g. {3} gives 1,234,567 and {3, 2} (lakh) gives 12,34,567.
+	// If empty, digits are not grouped.
+	Grouping []int
+
+	// SymbolAfter places the currency symbol after the number
+	// (e.g. "1.234,50 €"). Otherwise it is placed before it.
+	SymbolAfter bool
+
+	// SymbolSpace inserts a no-break space between the symbol
+	// and the number.
+	SymbolSpace bool
+
+	// NegativeStyle specifies how negative numbers are written.
+	NegativeStyle NegativeStyle
+} //                                                                NumberLocale
+
+// -----------------------------------------------------------------------------
+// # Built-in Locales
+
+// NumberLocales contains the number formats of common locales, indexed
+// by language tag (e.g. "en-US", "de-DE"). Locales that group digits with
+// spaces use no-break spaces, so amounts are not split across lines.
+// You can add more locales, or copy an existing one and change it,
+// e.g. to set NegativeParentheses.
+var NumberLocales = map[string]NumberLocale{
+	"de-CH": {"\u2019", ".", []int{3}, false, true, NegativeMinus},
+	"de-DE": {".", ",", []int{3}, true, true, NegativeMinus},
+	"en-GB": {",", ".", []int{3}, false, false, NegativeMinus},
+	"en-IN": {",", ".", []int{3, 2}, false, false, NegativeMinus},
+	"en-US": {",", ".", []int{3}, false, false, NegativeMinus},
+	"es-ES": {".", ",", []int{3}, true, true, NegativeMinus},
+	"fr-FR": {"\u202f", ",", []int{3}, true, true, NegativeMinus},
+	"hi-IN": {",", ".", []int{3, 2}, false, false, NegativeMinus},
+	"it-IT": {".", ",", []int{3}, true, true, NegativeMinus},
+	"ja-JP": {",", ".", []int{3}, false, false, NegativeMinus},
+	"nl-NL": {".", ",", []int{3}, false, true, NegativeMinus},
+	"pl-PL": {"\u00a0", ",", []int{3}, true, true, NegativeMinus},
+	"pt-BR": {".", ",", []int{3}, false, true, NegativeMinus},
+	"ru-RU": {"\u00a0", ",", []int{3}, true, true, NegativeMinus},
+	"sv-SE": {"\u00a0", ",", []int{3}, true, true, NegativeMinus},
+	"zh-CN": {",", ".", []int{3}, false, false, NegativeMinus},
+} //                                                               NumberLocales
+
+// NumberLocaleOf returns the built-in number locale for the
+// specified language tag, e.g. "en-US", "de_DE" or "EN-in".
+// Returns false if the locale is not found in NumberLocales.
+func NumberLocaleOf(tag string) (NumberLocale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if i := strings.Index(tag, "-"); i != -1 {
+		tag = strings.ToLower(tag[:i]) + "-" + strings.ToUpper(tag[i+1:])
+	}
+	ret, found := NumberLocales[tag]
+	return ret, found
+} //                                                              NumberLocaleOf
+
+// -----------------------------------------------------------------------------
+// # Formatting Functions
+
+// CommaDelimitLocale delimits a numeric string using the digit grouping,
+// group separator and decimal separator of the specified locale, and
+// sets the required number of decimal places, like CommaDelimit().
+// Numbers are not rounded, just cut at the required number of decimals.
+//
+// For example, the following returns "-1.234.567,89":
+// CommaDelimitLocale("-1234567.891", 2, NumberLocales["de-DE"])
+func CommaDelimitLocale(
+	number string, decimalPlaces int, loc NumberLocale,
+) string {
+	return loc.localize(CommaDelimit(number, decimalPlaces), "")
+} //                                                          CommaDelimitLocale
+
+// FmtLocale returns the currency value as a string formatted using the
+// specified locale, with the given number of decimal places (see Fmt()).
+// If a currency symbol is specified, it is placed before or after the
+// number as specified by the locale.
+//
+// For example, the following returns "-₹12,34,567.50":
+// CurrencyOf(-1234567.5).FmtLocale(2, NumberLocales["en-IN"], "₹")
+func (n Currency) FmtLocale(
+	decimalPlaces int, loc NumberLocale, optSymbol ...string,
+) string {
+	symbol := ""
+	switch len(optSymbol) {
+	case 0:
+		{
+			// no symbol
+		}
+	case 1:
+		{
+			symbol = optSymbol[0]
+		}
+	default:
+		mod.Error(EInvalidArg + ": Too many 'optSymbol' values")
+		symbol = optSymbol[0]
+	}
+	return loc.localize(n.Fmt(decimalPlaces), symbol)
+} //                                                                   FmtLocale
+
+// -----------------------------------------------------------------------------
+// # Internal Methods/Functions
+
+// localize converts a number formatted with commas and a decimal point
+// (as output by Fmt() or CommaDelimit()) to the specified locale's
+// format, and adds the currency symbol if one is specified.
+func (loc NumberLocale) localize(number, symbol string) string {
+	negative := strings.HasPrefix(number, "-")
+	if negative {
+		number = number[1:]
+	}
+	var (
+		digits   = strings.ReplaceAll(number, ",", "")
+		decimals = ""
+	)
+	if i := strings.Index(digits, "."); i != -1 {
+		digits, decimals = digits[:i], digits[i+1:]
+	}
+	var sb strings.Builder
+	sb.WriteString(loc.groupDigits(digits))
+	if decimals != "" {
+		if loc.DecimalSeparator == "" {
+			sb.WriteString(".")
+		} else {
+			sb.WriteString(loc.DecimalSeparator)
+		}
+		sb.WriteString(decimals)
+	}
+	ret := sb.String()
+	if symbol != "" {
+		space := ""
+		if loc.SymbolSpace {
+			space = "\u00a0"
+		}
+		if loc.SymbolAfter {
+			ret = ret + space + symbol
+		} else {
+			ret = symbol + space + ret
+		}
+	}
+	if negative {
+		switch loc.NegativeStyle {
+		case NegativeParentheses:
+			ret = "(" + ret + ")"
+		case NegativeTrailingMinus:
+			ret += "-"
+		default:
+			ret = "-" + ret
+		}
+	}
+	return ret
+} //                                                                    localize
+
+// groupDigits inserts the locale's group separator between digit groups
+// of the integer part of a number, as specified by loc.Grouping.
+func (loc NumberLocale) groupDigits(digits string) string {
+	if len(loc.Grouping) == 0 || loc.GroupSeparator == "" {
+		return digits
+	}
+	var (
+		groups []string
+		end    = len(digits)
+		i      = 0
+	)
+	for end > 0 {
+		size := loc.Grouping[i]
+		if i < len(loc.Grouping)-1 {
+			i++
+		}
+		if size <= 0 || size >= end {
+			groups = append(groups, digits[:end])
+			break
+		}
+		groups = append(groups, digits[end-size:end])
+		end -= size
+	}
+	// groups were collected from right to left
+	for a, b := 0, len(groups)-1; a < b; a, b = a+1, b-1 {
+		groups[a], groups[b] = groups[b], groups[a]
+	}
+	return strings.Join(groups, loc.GroupSeparator)
+} //                                                                 groupDigits
+
+// end
@@ -0,0 +1,145 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                         zr/[number_locale_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Built-in Locales
+//   Test_nloc_NumberLocaleOf_
+//
+// # Formatting Functions
+//   Test_nloc_CommaDelimitLocale_
+//   Test_nloc_Currency_FmtLocale_
+
+//  to test all items in number_locale.go use:
+//      go test --run Test_nloc_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Built-in Locales
+
+// go test --run Test_nloc_NumberLocaleOf_
+func Test_nloc_NumberLocaleOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// NumberLocaleOf(tag string) (NumberLocale, bool)
+	//
+	test := func(tag, expect string) {
+		loc, found := NumberLocaleOf(tag)
+		got := CommaDelimitLocale("1234567.5", 2, loc)
+		if !found || got != expect {
+			TFailf(t, `NumberLocaleOf(%q) gave %q, %v instead of %q`,
+				tag, got, found, expect)
+		}
+	}
+	test("en-US", "1,234,567.50")
+	test("de_DE", "1.234.567,50")
+	test(" EN-in ", "12,34,567.50")
+	test("fr-fr", "1\u202f234\u202f567,50")
+	test("de-CH", "1\u2019234\u2019567.50")
+	//
+	_, found := NumberLocaleOf("xx-YY")
+	TFalse(t, found)
+} //                                                   Test_nloc_NumberLocaleOf_
+
+// -----------------------------------------------------------------------------
+// # Formatting Functions
+
+// go test --run Test_nloc_CommaDelimitLocale_
+func Test_nloc_CommaDelimitLocale_(t *testing.T) {
+	TBegin(t)
+	//
+	// CommaDelimitLocale(number string, decimalPlaces int, loc NumberLocale) string
+	//
+	var (
+		de = NumberLocales["de-DE"]
+		in = NumberLocales["en-IN"]
+		us = NumberLocales["en-US"]
+	)
+	test := func(number string, decimalPlaces int, loc NumberLocale,
+		expect string) {
+		got := CommaDelimitLocale(number, decimalPlaces, loc)
+		if got != expect {
+			TFailf(t, `CommaDelimitLocale(%q, %d, %v) returned %q`+
+				` instead of %q`, number, decimalPlaces, loc, got, expect)
+		}
+	}
+	test("0", 0, de, "0")
+	test("123", 2, de, "123,00")
+	test("-1234567.891", 2, de, "-1.234.567,89")
+	test("1234567.891", 2, us, "1,234,567.89")
+	test("1000", 0, in, "1,000")
+	test("100000", 0, in, "1,00,000")
+	test("-123456789.5", 1, in, "-12,34,56,789.5")
+	//
+	// the zero locale doesn't group digits and uses a decimal point
+	test("-1234567.891", 2, NumberLocale{}, "-1234567.89")
+	//
+	// negative styles
+	acc := us
+	acc.NegativeStyle = NegativeParentheses
+	test("-1234.5", 2, acc, "(1,234.50)")
+	test("1234.5", 2, acc, "1,234.50")
+	acc.NegativeStyle = NegativeTrailingMinus
+	test("-1234.5", 2, acc, "1,234.50-")
+	//
+	// irregular grouping: first group of 3, then 4
+	test("123456789012", 0, NumberLocale{"_", ".", []int{3, 4}, false,
+		false, NegativeMinus}, "1_2345_6789_012")
+} //                                               Test_nloc_CommaDelimitLocale_
+
+// go test --run Test_nloc_Currency_FmtLocale_
+func Test_nloc_Currency_FmtLocale_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) FmtLocale(
+	//     decimalPlaces int, loc NumberLocale, optSymbol ...string,
+	// ) string
+	//
+	test := func(n Currency, decimalPlaces int, tag, symbol, expect string) {
+		got := n.FmtLocale(decimalPlaces, NumberLocales[tag], symbol)
+		if got != expect {
+			TFailf(t, `%v.FmtLocale(%d, %q, %q) returned %q instead of %q`,
+				n, decimalPlaces, tag, symbol, got, expect)
+		}
+	}
+	test(cur(1234567.89), 2, "en-US", "", "1,234,567.89")
+	test(cur(1234567.89), 2, "en-US", "$", "$1,234,567.89")
+	test(cur(-1234567.89), 2, "en-US", "$", "-$1,234,567.89")
+	test(cur(1234567.89), 2, "de-DE", "€", "1.234.567,89\u00a0€")
+	test(cur(-0.5), 2, "de-DE", "€", "-0,50\u00a0€")
+	test(cur(1234567.89), 2, "en-IN", "₹", "₹12,34,567.89")
+	test(cur(1234567.89), 2, "nl-NL", "€", "€\u00a01.234.567,89")
+	test(cur(1234567.89), 2, "pt-BR", "R$", "R$\u00a01.234.567,89")
+	test(cur(1234567), 0, "ja-JP", "¥", "¥1,234,567")
+	test(cur(-42.1), 4, "sv-SE", "kr", "-42,1000\u00a0kr")
+	//
+	// accounting style
+	{
+		acc := NumberLocales["en-US"]
+		acc.NegativeStyle = NegativeParentheses
+		TEqual(t, cur(-1234.5).FmtLocale(2, acc, "$"), "($1,234.50)")
+		TEqual(t, cur(1234.5).FmtLocale(2, acc, "$"), "$1,234.50")
+		TEqual(t, cur(-1234.5).FmtLocale(2, acc), "(1,234.50)")
+	}
+	// too many symbols are logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got := cur(1).FmtLocale(2, NumberLocales["en-US"], "$", "USD")
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, got, "$1.00")
+		TEqual(t, ec2, ec1+1)
+	}
+} //                                               Test_nloc_Currency_FmtLocale_
+
+// end
@@ -0,0 +1,711 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                               zr/[number_words.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Types
+//   NumberGender int
+//   NumberSpeller interface
+//
+// # Speller Registry
+//   NumberSpellers = map[string]NumberSpeller
+//   NumberSpellerOf(lang string) (NumberSpeller, bool)
+//
+// # Amounts in Words
+//   (n Currency) InWords(lang, fmt string) string
+//   (m Money) InWords(lang, fmt string) string
+//
+// # English: NumberSpellerEN
+// # French: NumberSpellerFR
+// # German: NumberSpellerDE
+// # Spanish: NumberSpellerES
+//
+// # Helper Functions
+//   numberWordsGender(unit string) (string, NumberGender)
+//   numberWordsUnit(count int64, single, plural string) string
+
+import (
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// # Types
+
+// NumberGender specifies the grammatical gender of the noun that
+// follows a number. Some languages use different number words
+// for each gender, e.g. "un euro" but "une livre" in French.
+type NumberGender int
+
+const (
+	// GenderNone spells a number on its own, as when counting,
+	// e.g. "eins" in German or "uno" in Spanish.
+	GenderNone NumberGender = iota
+
+	// GenderMasculine spells a number followed by a masculine
+	// noun, e.g. "ein Euro" in German or "un euro" in Spanish.
+	GenderMasculine
+
+	// GenderFeminine spells a number followed by a feminine noun,
+	// e.g. "une livre" in French or "una peseta" in Spanish.
+	GenderFeminine
+
+	// GenderNeuter spells a number followed by a neuter noun,
+	// e.g. "ein Pfund" in German.
+	GenderNeuter
+)
+
+// NumberSpeller spells numbers and amounts in words in a particular
+// language. Implement this interface and add it to NumberSpellers
+// to support more languages in Currency.InWords().
+type NumberSpeller interface {
+
+	// AndWord returns the word that joins the large and the
+	// small denomination of an amount, e.g. "and" in English.
+	AndWord() string
+
+	// OnlyWord returns the word appended to amounts on cheques to
+	// show that nothing follows, e.g. "Only" in English. Returns
+	// a blank string if the language doesn't use such a word.
+	OnlyWord() string
+
+	// SpellInt returns a number in words. The gender of the
+	// noun that follows the number is used by languages
+	// that have different words for each gender.
+	SpellInt(number int64, gender NumberGender) string
+
+	// UnitName returns the name of a currency unit (e.g. "euro")
+	// as written after the given number of units, using the
+	// plural when needed. If the plural is blank, it is
+	// formed from the singular using the language's rules.
+	UnitName(count int64, single, plural string) string
+} //                                                               NumberSpeller
+
+// -----------------------------------------------------------------------------
+// # Speller Registry
+
+// NumberSpellers contains the available number spellers, indexed by
+// lower-case ISO 639-1 language code (e.g. "en", "fr"). You can
+// add spellers for other languages to this map.
+var NumberSpellers = map[string]NumberSpeller{
+	"de": NumberSpellerDE{},
+	"en": NumberSpellerEN{},
+	"es": NumberSpellerES{},
+	"fr": NumberSpellerFR{},
+} //                                                              NumberSpellers
+
+// NumberSpellerOf returns the number speller for the specified
+// language. lang can be a language code (e.g. "fr") or a language
+// tag (e.g. "fr-CA" or "fr_CA"). A speller registered for the whole
+// tag is preferred to the speller for the language.
+// Returns false if there is no speller for the language.
+func NumberSpellerOf(lang string) (NumberSpeller, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	lang = strings.ReplaceAll(lang, "_", "-")
+	if ret, found := NumberSpellers[lang]; found {
+		return ret, true
+	}
+	if i := strings.Index(lang, "-"); i != -1 {
+		ret, found := NumberSpellers[lang[:i]]
+		return ret, found
+	}
+	return nil, false
+} //                                                             NumberSpellerOf
+
+// -----------------------------------------------------------------------------
+// # Amounts in Words
+
+// InWords returns the currency value as a description in words
+// in the specified language, e.g. for printing cheques.
+// lang is a language code or tag (see NumberSpellerOf()).
+//
+// fmt has the same format as in InWordsEN(), with the unit names
+// written in the specified language: e.g. "euro;;centime;Only".
+// The final "Only" marker is replaced with the language's word
+// (see NumberSpeller.OnlyWord()).
+//
+// If a unit name ends with "/f" the unit is feminine, and with "/n"
+// it is neuter, otherwise it is masculine. E.g. "livre/f;;penny"
+// gives "vingt et une livres" in French.
+//
+// Logs an error and returns a blank string if the
+// language has no speller in NumberSpellers.
+//
+// For example, the following returns "quatre-vingt-un euros
+// et deux centimes":
+// CurrencyOf(81.02).InWords("fr", "euro;;centime")
+func (n Currency) InWords(lang, fmt string) string {
+	sp, found := NumberSpellerOf(lang)
+	if !found {
+		mod.Error(ENotFound, "number speller for", lang)
+		return ""
+	}
+	i := n.i64
+	if i < 0 {
+		i = -n.i64
+	}
+	var (
+		bigUnits = i / 1e4
+		smlUnits = (i - bigUnits*1e4) / 100
+		hasOnly  = strings.HasSuffix(strings.ToLower(fmt), "only")
+	)
+	if hasOnly {
+		fmt = fmt[:len(fmt)-4]
+	}
+	parts := strings.Split(fmt, ";")
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+	var (
+		big1, bigGender = numberWordsGender(parts[0])
+		bigN, bigGenN   = numberWordsGender(parts[1])
+		sml1, smlGender = numberWordsGender(parts[2])
+		smlN, smlGenN   = numberWordsGender(parts[3])
+		ret             = ""
+	)
+	if bigGender == GenderMasculine {
+		bigGender = bigGenN
+	}
+	if smlGender == GenderMasculine {
+		smlGender = smlGenN
+	}
+	if bigUnits > 0 && (big1+bigN) != "" {
+		ret += sp.SpellInt(bigUnits, bigGender) + " " +
+			sp.UnitName(bigUnits, big1, bigN)
+	}
+	if smlUnits > 0 && (sml1+smlN) != "" {
+		if ret != "" {
+			ret += " " + sp.AndWord() + " "
+		}
+		ret += sp.SpellInt(smlUnits, smlGender) + " " +
+			sp.UnitName(smlUnits, sml1, smlN)
+	}
+	if hasOnly && ret != "" && sp.OnlyWord() != "" {
+		ret += " " + sp.OnlyWord()
+	}
+	return ret
+} //                                                                     InWords
+
+// InWords returns the money value as a description in words in the
+// specified language. See Currency.InWords() for a description
+// of the lang and fmt arguments.
+func (m Money) InWords(lang, fmt string) string {
+	return m.amount.InWords(lang, fmt)
+} //                                                                     InWords
+
+// -----------------------------------------------------------------------------
+// # English: NumberSpellerEN
+
+// NumberSpellerEN spells numbers in English, using IntInWordsEN().
+type NumberSpellerEN struct{}
+
+// AndWord returns "and".
+func (NumberSpellerEN) AndWord() string {
+	return "and"
+} //                                                                     AndWord
+
+// OnlyWord returns "Only".
+func (NumberSpellerEN) OnlyWord() string {
+	return "Only"
+} //                                                                    OnlyWord
+
+// SpellInt returns a number in English words, e.g. "Twenty One".
+// English number words don't depend on gender.
+func (NumberSpellerEN) SpellInt(number int64, gender NumberGender) string {
+	return IntInWordsEN(number)
+} //                                                                    SpellInt
+
+// UnitName returns the English unit name, adding "s" to
+// form the plural if the plural is not specified.
+func (NumberSpellerEN) UnitName(count int64, single, plural string) string {
+	return numberWordsUnit(count, single, plural, "s")
+} //                                                                    UnitName
+
+// -----------------------------------------------------------------------------
+// # French: NumberSpellerFR
+
+// NumberSpellerFR spells numbers in French using traditional spelling,
+// e.g. "quatre-vingts", "deux cent vingt et un", "un million".
+type NumberSpellerFR struct{}
+
+// frOnes are French names of numbers from 0 to 19.
+var frOnes = []string{
+	"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit",
+	"neuf", "dix", "onze", "douze", "treize", "quatorze", "quinze",
+	"seize", "dix-sept", "dix-huit", "dix-neuf",
+}
+
+// frTens are French names of tens from 20 to 60.
+var frTens = []string{"", "", "vingt", "trente", "quarante", "cinquante",
+	"soixante"}
+
+// AndWord returns "et".
+func (NumberSpellerFR) AndWord() string {
+	return "et"
+} //                                                                     AndWord
+
+// OnlyWord returns "seulement".
+func (NumberSpellerFR) OnlyWord() string {
+	return "seulement"
+} //                                                                    OnlyWord
+
+// SpellInt returns a number in French words, e.g. "vingt et une"
+// for 21 followed by a feminine noun.
+func (ob NumberSpellerFR) SpellInt(number int64, gender NumberGender) string {
+	if number == 0 {
+		return frOnes[0]
+	}
+	var (
+		words []string
+		num   = uint64(number)
+	)
+	if number < 0 {
+		words = append(words, "moins")
+		num = -num
+	}
+	scales := []struct {
+		base uint64
+		name string
+	}{
+		{1e18, "trillion"}, {1e15, "billiard"}, {1e12, "billion"},
+		{1e9, "milliard"}, {1e6, "million"},
+	}
+	for _, sc := range scales {
+		count := num / sc.base
+		if count == 0 {
+			continue
+		}
+		num %= sc.base
+		name := sc.name
+		if count > 1 {
+			name += "s"
+		}
+		words = append(words, ob.below1000(count, GenderMasculine, true), name)
+	}
+	// "mille" is invariable and is not preceded by "un"
+	if count := num / 1000; count > 0 {
+		num %= 1000
+		if count > 1 {
+			words = append(words, ob.below1000(count, GenderMasculine, false))
+		}
+		words = append(words, "mille")
+	}
+	if num > 0 {
+		words = append(words, ob.below1000(num, gender, true))
+	}
+	return strings.Join(words, " ")
+} //                                                                    SpellInt
+
+// UnitName returns the French unit name, adding "s" to form the
+// plural if the plural is not specified. Whole millions and larger
+// amounts take "de", e.g. "un million d'euros".
+func (NumberSpellerFR) UnitName(count int64, single, plural string) string {
+	if plural == "" && single != "" && !strings.ContainsAny(
+		single[len(single)-1:], "sxz") {
+		plural = single + "s"
+	}
+	ret := numberWordsUnit(count, single, plural, "")
+	if count >= 1e6 && count%1e6 == 0 && ret != "" {
+		if strings.ContainsAny(strings.ToLower(ret[:1]), "aeiouy") ||
+			strings.HasPrefix(strings.ToLower(ret), "é") {
+			return "d'" + ret
+		}
+		return "de " + ret
+	}
+	return ret
+} //                                                                    UnitName
+
+// below1000 spells a number from 1 to 999. isLast specifies if
+// nothing follows the number, or if it is followed by a noun like
+// "millions": "cent" and "quatre-vingt" then take a final "s".
+func (ob NumberSpellerFR) below1000(
+	num uint64, gender NumberGender, isLast bool,
+) string {
+	var (
+		hundreds = num / 100
+		rest     = num % 100
+		ret      = ""
+	)
+	if hundreds > 0 {
+		ret = "cent"
+		if hundreds > 1 {
+			ret = frOnes[hundreds] + " cent"
+			if rest == 0 && isLast {
+				ret += "s"
+			}
+		}
+		if rest == 0 {
+			return ret
+		}
+		ret += " "
+	}
+	return ret + ob.below100(rest, gender, isLast)
+} //                                                                   below1000
+
+// below100 spells a number from 1 to 99.
+func (NumberSpellerFR) below100(
+	num uint64, gender NumberGender, isLast bool,
+) string {
+	one := "un"
+	if gender == GenderFeminine {
+		one = "une"
+	}
+	var (
+		tens  = num / 10
+		units = num % 10
+	)
+	switch {
+	case num == 1:
+		return one
+	case num < 20:
+		return frOnes[num]
+	case tens <= 6 && units == 0:
+		return frTens[tens]
+	case tens <= 6 && units == 1:
+		return frTens[tens] + " et " + one
+	case tens <= 6:
+		return frTens[tens] + "-" + frOnes[units]
+	case num == 71:
+		return "soixante et onze"
+	case tens == 7:
+		return "soixante-" + frOnes[num-60]
+	case num == 80 && isLast:
+		return "quatre-vingts"
+	case num == 80:
+		return "quatre-vingt"
+	case tens == 8 && units == 1:
+		return "quatre-vingt-" + one
+	}
+	return "quatre-vingt-" + frOnes[num-80]
+} //                                                                    below100
+
+// -----------------------------------------------------------------------------
+// # German: NumberSpellerDE
+
+// NumberSpellerDE spells numbers in German. Numbers below one million
+// are written as one compound word, e.g. "zweitausenddreihundertvier".
+type NumberSpellerDE struct{}
+
+// deOnes are German names of numbers from 0 to 19.
+var deOnes = []string{
+	"null", "eins", "zwei", "drei", "vier", "fünf", "sechs", "sieben",
+	"acht", "neun", "zehn", "elf", "zwölf", "dreizehn", "vierzehn",
+	"fünfzehn", "sechzehn", "siebzehn", "achtzehn", "neunzehn",
+}
+
+// deTens are German names of tens from 20 to 90.
+var deTens = []string{"", "", "zwanzig", "dreißig", "vierzig", "fünfzig",
+	"sechzig", "siebzig", "achtzig", "neunzig"}
+
+// AndWord returns "und".
+func (NumberSpellerDE) AndWord() string {
+	return "und"
+} //                                                                     AndWord
+
+// OnlyWord returns a blank string, since German amounts
+// in words are not followed by such a word.
+func (NumberSpellerDE) OnlyWord() string {
+	return ""
+} //                                                                    OnlyWord
+
+// SpellInt returns a number in German words, e.g. "einundzwanzig",
+// "eine Million zweihunderttausend". A final one is spelled "eins"
+// on its own, "ein" before masculine or neuter and "eine" before
+// feminine nouns, e.g. "einhundertein Euro".
+func (ob NumberSpellerDE) SpellInt(number int64, gender NumberGender) string {
+	if number == 0 {
+		return deOnes[0]
+	}
+	var (
+		words []string
+		num   = uint64(number)
+	)
+	if number < 0 {
+		words = append(words, "minus")
+		num = -num
+	}
+	scales := []struct {
+		base           uint64
+		single, plural string
+	}{
+		{1e18, "Trillion", "Trillionen"}, {1e15, "Billiarde", "Billiarden"},
+		{1e12, "Billion", "Billionen"}, {1e9, "Milliarde", "Milliarden"},
+		{1e6, "Million", "Millionen"},
+	}
+	for _, sc := range scales {
+		count := num / sc.base
+		if count == 0 {
+			continue
+		}
+		num %= sc.base
+		if count == 1 {
+			words = append(words, "eine", sc.single)
+			continue
+		}
+		words = append(words, ob.below1000(count, "eine"), sc.plural)
+	}
+	// numbers below one million are written as one word
+	word := ""
+	if count := num / 1000; count > 0 {
+		num %= 1000
+		word = ob.below1000(count, "ein") + "tausend"
+	}
+	if num > 0 {
+		one := "ein"
+		switch gender {
+		case GenderNone:
+			one = "eins"
+		case GenderFeminine:
+			one = "eine"
+		}
+		word += ob.below1000(num, one)
+	}
+	if word != "" {
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+} //                                                                    SpellInt
+
+// UnitName returns the German unit name. German currency names
+// don't change in the plural, e.g. "zehn Euro", so the singular
+// is used if the plural is not specified.
+func (NumberSpellerDE) UnitName(count int64, single, plural string) string {
+	if plural == "" {
+		plural = single
+	}
+	return numberWordsUnit(count, single, plural, "")
+} //                                                                    UnitName
+
+// below1000 spells a number from 1 to 999 as a single word.
+// one is the word used when the number ends with 1.
+func (NumberSpellerDE) below1000(num uint64, one string) string {
+	var (
+		hundreds = num / 100
+		rest     = num % 100
+		tens     = rest / 10
+		units    = rest % 10
+		ret      = ""
+	)
+	if hundreds == 1 {
+		ret = "einhundert"
+	} else if hundreds > 1 {
+		ret = deOnes[hundreds] + "hundert"
+	}
+	switch {
+	case rest == 0:
+		return ret
+	case rest == 1:
+		return ret + one
+	case rest < 20:
+		return ret + deOnes[rest]
+	case units == 0:
+		return ret + deTens[tens]
+	case units == 1:
+		return ret + "einund" + deTens[tens]
+	}
+	return ret + deOnes[units] + "und" + deTens[tens]
+} //                                                                   below1000
+
+// -----------------------------------------------------------------------------
+// # Spanish: NumberSpellerES
+
+// NumberSpellerES spells numbers in Spanish using the long scale,
+// e.g. "mil millones" for 10^9 and "un billón" for 10^12.
+type NumberSpellerES struct{}
+
+// esOnes are Spanish names of numbers from 0 to 29.
+var esOnes = []string{
+	"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete",
+	"ocho", "nueve", "diez", "once", "doce", "trece", "catorce", "quince",
+	"dieciséis", "diecisiete", "dieciocho", "diecinueve", "veinte",
+	"veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco",
+	"veintiséis", "veintisiete", "veintiocho", "veintinueve",
+}
+
+// esTens are Spanish names of tens from 30 to 90.
+var esTens = []string{"", "", "", "treinta", "cuarenta", "cincuenta",
+	"sesenta", "setenta", "ochenta", "noventa"}
+
+// esHundreds are Spanish names of hundreds from 200 to 900 (masculine).
+var esHundreds = []string{"", "ciento", "doscientos", "trescientos",
+	"cuatrocientos", "quinientos", "seiscientos", "setecientos",
+	"ochocientos", "novecientos"}
+
+// AndWord returns "con".
+func (NumberSpellerES) AndWord() string {
+	return "con"
+} //                                                                     AndWord
+
+// OnlyWord returns "exactamente".
+func (NumberSpellerES) OnlyWord() string {
+	return "exactamente"
+} //                                                                    OnlyWord
+
+// SpellInt returns a number in Spanish words. A final one is spelled
+// "uno" on its own, "un" before masculine and "una" before feminine
+// nouns, e.g. "veintiún euros", "doscientas una pesetas".
+func (ob NumberSpellerES) SpellInt(number int64, gender NumberGender) string {
+	if number == 0 {
+		return esOnes[0]
+	}
+	var (
+		words []string
+		num   = uint64(number)
+	)
+	if number < 0 {
+		words = append(words, "menos")
+		num = -num
+	}
+	scales := []struct {
+		base           uint64
+		single, plural string
+	}{
+		{1e18, "trillón", "trillones"}, {1e12, "billón", "billones"},
+		{1e6, "millón", "millones"},
+	}
+	for _, sc := range scales {
+		count := num / sc.base
+		if count == 0 {
+			continue
+		}
+		num %= sc.base
+		if count == 1 {
+			words = append(words, "un", sc.single)
+			continue
+		}
+		words = append(words, ob.below1e6(count, GenderMasculine),
+			sc.plural)
+	}
+	if num > 0 {
+		words = append(words, ob.below1e6(num, gender))
+	}
+	return strings.Join(words, " ")
+} //                                                                    SpellInt
+
+// UnitName returns the Spanish unit name. If the plural is not
+// specified, it is formed by adding "s" after a vowel and "es"
+// after a consonant. Whole millions and larger amounts take "de",
+// e.g. "un millón de euros".
+func (NumberSpellerES) UnitName(count int64, single, plural string) string {
+	if plural == "" && single != "" {
+		plural = single + "es"
+		if strings.ContainsAny(single[len(single)-1:], "aeiouAEIOU") {
+			plural = single + "s"
+		}
+	}
+	ret := numberWordsUnit(count, single, plural, "")
+	if count >= 1e6 && count%1e6 == 0 && ret != "" {
+		return "de " + ret
+	}
+	return ret
+} //                                                                    UnitName
+
+// below1e6 spells a number from 1 to 999,999.
+func (ob NumberSpellerES) below1e6(num uint64, gender NumberGender) string {
+	var (
+		thousands = num / 1000
+		rest      = num % 1000
+		words     []string
+	)
+	if thousands > 0 {
+		// "mil" is not preceded by "un"; "veintiún mil"
+		// is also used when counting on its own
+		if thousands > 1 {
+			g := gender
+			if g == GenderNone {
+				g = GenderMasculine
+			}
+			words = append(words, ob.below1000(thousands, g))
+		}
+		words = append(words, "mil")
+	}
+	if rest > 0 {
+		words = append(words, ob.below1000(rest, gender))
+	}
+	return strings.Join(words, " ")
+} //                                                                    below1e6
+
+// below1000 spells a number from 1 to 999.
+func (NumberSpellerES) below1000(num uint64, gender NumberGender) string {
+	var (
+		hundreds = num / 100
+		rest     = num % 100
+		words    []string
+	)
+	if num == 100 {
+		return "cien"
+	}
+	if hundreds > 0 {
+		word := esHundreds[hundreds]
+		if hundreds > 1 && gender == GenderFeminine {
+			word = strings.TrimSuffix(word, "os") + "as"
+		}
+		words = append(words, word)
+	}
+	if rest > 0 {
+		var (
+			tens  = rest / 10
+			units = rest % 10
+			word  = ""
+		)
+		if rest < 30 {
+			word = esOnes[rest]
+		} else {
+			word = esTens[tens]
+			if units > 0 {
+				word += " y " + esOnes[units]
+			}
+		}
+		if units == 1 && rest != 11 {
+			switch gender {
+			case GenderFeminine:
+				word = strings.TrimSuffix(word, "o") + "a"
+			case GenderMasculine, GenderNeuter:
+				word = strings.TrimSuffix(word, "o")
+				if rest == 21 {
+					word = "veintiún"
+				}
+			}
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+} //                                                                   below1000
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// numberWordsGender removes the gender marker ("/f" for feminine, "/n"
+// for neuter) from a unit name and returns the name and its gender.
+// Units without a marker are masculine.
+func numberWordsGender(unit string) (string, NumberGender) {
+	switch {
+	case strings.HasSuffix(unit, "/f"):
+		return unit[:len(unit)-2], GenderFeminine
+	case strings.HasSuffix(unit, "/n"):
+		return unit[:len(unit)-2], GenderNeuter
+	}
+	return unit, GenderMasculine
+} //                                                           numberWordsGender
+
+// numberWordsUnit returns the singular or plural unit name for the
+// given count, like Currency.InWordsEN(): if the singular is blank,
+// the plural is always used; if the plural is blank, suffix is
+// added to the singular to form the plural.
+func numberWordsUnit(count int64, single, plural, suffix string) string {
+	switch {
+	case single == "":
+		return plural
+	case plural == "":
+		if count > 1 {
+			return single + suffix
+		}
+		return single
+	case count == 1:
+		return single
+	}
+	return plural
+} //                                                             numberWordsUnit
+
+// end
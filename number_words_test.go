@@ -0,0 +1,219 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                          zr/[number_words_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Speller Registry
+//   Test_nwrd_NumberSpellerOf_
+//
+// # Amounts in Words
+//   Test_nwrd_Currency_InWords_
+//
+// # Spellers
+//   Test_nwrd_NumberSpellerDE_
+//   Test_nwrd_NumberSpellerES_
+//   Test_nwrd_NumberSpellerFR_
+
+//  to test all items in number_words.go use:
+//      go test --run Test_nwrd_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Speller Registry
+
+// go test --run Test_nwrd_NumberSpellerOf_
+func Test_nwrd_NumberSpellerOf_(t *testing.T) {
+	TBegin(t)
+	//
+	// NumberSpellerOf(lang string) (NumberSpeller, bool)
+	//
+	test := func(lang string, expect NumberSpeller) {
+		got, found := NumberSpellerOf(lang)
+		if !found || got != expect {
+			TFailf(t, `NumberSpellerOf(%q) gave %T, %v`, lang, got, found)
+		}
+	}
+	test("en", NumberSpellerEN{})
+	test("FR", NumberSpellerFR{})
+	test("de-CH", NumberSpellerDE{})
+	test(" es_MX ", NumberSpellerES{})
+	//
+	_, found := NumberSpellerOf("xx")
+	TFalse(t, found)
+} //                                                  Test_nwrd_NumberSpellerOf_
+
+// -----------------------------------------------------------------------------
+// # Amounts in Words
+
+// go test --run Test_nwrd_Currency_InWords_
+func Test_nwrd_Currency_InWords_(t *testing.T) {
+	TBegin(t)
+	//
+	// (n Currency) InWords(lang, fmt string) string
+	//
+	test := func(n Currency, lang, fmt, expect string) {
+		got := n.InWords(lang, fmt)
+		if got != expect {
+			TFailf(t, `%v.InWords(%q, %q) returned %q instead of %q`,
+				n, lang, fmt, got, expect)
+		}
+	}
+	// English gives the same result as InWordsEN()
+	for _, n := range []Currency{cur(0), cur(1), cur(1.01), cur(21.5),
+		cur(-1234.56), cur(1000000)} {
+		for _, fmt := range []string{"Dollar;;Cent;Only", "Euro;Euro;Cent",
+			";Pounds;Penny;Pence", ""} {
+			test(n, "en", fmt, n.InWordsEN(fmt))
+		}
+	}
+	// French
+	test(cur(1), "fr", "euro;;centime", "un euro")
+	test(cur(81.02), "fr", "euro;;centime",
+		"quatre-vingt-un euros et deux centimes")
+	test(cur(80), "fr", "euro;;centime;Only", "quatre-vingts euros seulement")
+	test(cur(21.21), "fr", "livre/f;;penny;pence",
+		"vingt et une livres et vingt et un pence")
+	test(cur(1000000), "fr", "euro;;centime", "un million d'euros")
+	test(cur(2000000), "fr", "franc;;centime", "deux millions de francs")
+	test(cur(2000001), "fr", "franc;;centime", "deux millions un francs")
+	//
+	// German
+	test(cur(1), "de", "Euro;;Cent", "ein Euro")
+	test(cur(1), "de", "Pfund/n;;Penny;Pence", "ein Pfund")
+	test(cur(21.01), "de", "Euro;;Cent;Only",
+		"einundzwanzig Euro und ein Cent")
+	test(cur(1), "de", "Mark/f;;Pfennig", "eine Mark")
+	test(cur(2354), "de", "Euro;;Cent",
+		"zweitausenddreihundertvierundfünfzig Euro")
+	//
+	// Spanish
+	test(cur(1), "es", "euro;;céntimo", "un euro")
+	test(cur(21.01), "es", "euro;;céntimo;Only",
+		"veintiún euros con un céntimo exactamente")
+	test(cur(201), "es", "peseta/f;;céntimo", "doscientas una pesetas")
+	test(cur(100), "es", "dólar;;centavo", "cien dólares")
+	test(cur(1000000), "es", "euro;;céntimo", "un millón de euros")
+	//
+	// unknown languages are logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got := cur(1).InWords("xx", "euro;;cent")
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, got, "")
+		TEqual(t, ec2-ec1, 1)
+	}
+	// Money
+	TEqual(t, MoneyOf(5, "EUR").InWords("fr", "euro;;centime"), "cinq euros")
+} //                                                 Test_nwrd_Currency_InWords_
+
+// -----------------------------------------------------------------------------
+// # Spellers
+
+// go test --run Test_nwrd_NumberSpellerDE_
+func Test_nwrd_NumberSpellerDE_(t *testing.T) {
+	TBegin(t)
+	//
+	// (NumberSpellerDE) SpellInt(number int64, gender NumberGender) string
+	//
+	test := func(number int64, gender NumberGender, expect string) {
+		got := NumberSpellerDE{}.SpellInt(number, gender)
+		if got != expect {
+			TFailf(t, `SpellInt(%d, %d) returned %q instead of %q`,
+				number, gender, got, expect)
+		}
+	}
+	test(0, GenderNone, "null")
+	test(1, GenderNone, "eins")
+	test(1, GenderMasculine, "ein")
+	test(1, GenderFeminine, "eine")
+	test(17, GenderNone, "siebzehn")
+	test(30, GenderNone, "dreißig")
+	test(101, GenderNone, "einhunderteins")
+	test(101, GenderMasculine, "einhundertein")
+	test(999, GenderNone, "neunhundertneunundneunzig")
+	test(1000, GenderNone, "eintausend")
+	test(21000, GenderNone, "einundzwanzigtausend")
+	test(1000000, GenderNone, "eine Million")
+	test(2500000, GenderNone, "zwei Millionen fünfhunderttausend")
+	test(1000000001, GenderNone, "eine Milliarde eins")
+	test(-12, GenderNone, "minus zwölf")
+} //                                                  Test_nwrd_NumberSpellerDE_
+
+// go test --run Test_nwrd_NumberSpellerES_
+func Test_nwrd_NumberSpellerES_(t *testing.T) {
+	TBegin(t)
+	//
+	// (NumberSpellerES) SpellInt(number int64, gender NumberGender) string
+	//
+	test := func(number int64, gender NumberGender, expect string) {
+		got := NumberSpellerES{}.SpellInt(number, gender)
+		if got != expect {
+			TFailf(t, `SpellInt(%d, %d) returned %q instead of %q`,
+				number, gender, got, expect)
+		}
+	}
+	test(0, GenderNone, "cero")
+	test(1, GenderNone, "uno")
+	test(1, GenderMasculine, "un")
+	test(1, GenderFeminine, "una")
+	test(16, GenderNone, "dieciséis")
+	test(21, GenderNone, "veintiuno")
+	test(21, GenderFeminine, "veintiuna")
+	test(22, GenderNone, "veintidós")
+	test(31, GenderMasculine, "treinta y un")
+	test(100, GenderNone, "cien")
+	test(101, GenderNone, "ciento uno")
+	test(500, GenderFeminine, "quinientas")
+	test(1000, GenderNone, "mil")
+	test(21000, GenderNone, "veintiún mil")
+	test(1000000, GenderNone, "un millón")
+	test(2000000000, GenderNone, "dos mil millones")
+	test(1000000000000, GenderNone, "un billón")
+	test(-7, GenderNone, "menos siete")
+} //                                                  Test_nwrd_NumberSpellerES_
+
+// go test --run Test_nwrd_NumberSpellerFR_
+func Test_nwrd_NumberSpellerFR_(t *testing.T) {
+	TBegin(t)
+	//
+	// (NumberSpellerFR) SpellInt(number int64, gender NumberGender) string
+	//
+	test := func(number int64, gender NumberGender, expect string) {
+		got := NumberSpellerFR{}.SpellInt(number, gender)
+		if got != expect {
+			TFailf(t, `SpellInt(%d, %d) returned %q instead of %q`,
+				number, gender, got, expect)
+		}
+	}
+	test(0, GenderNone, "zéro")
+	test(1, GenderFeminine, "une")
+	test(17, GenderNone, "dix-sept")
+	test(21, GenderNone, "vingt et un")
+	test(71, GenderNone, "soixante et onze")
+	test(72, GenderNone, "soixante-douze")
+	test(80, GenderNone, "quatre-vingts")
+	test(81, GenderFeminine, "quatre-vingt-une")
+	test(99, GenderNone, "quatre-vingt-dix-neuf")
+	test(100, GenderNone, "cent")
+	test(200, GenderNone, "deux cents")
+	test(201, GenderNone, "deux cent un")
+	test(1000, GenderNone, "mille")
+	test(80000, GenderNone, "quatre-vingt mille")
+	test(200000, GenderNone, "deux cent mille")
+	test(80000000, GenderNone, "quatre-vingts millions")
+	test(1000000000, GenderNone, "un milliard")
+	test(-21, GenderNone, "moins vingt et un")
+} //                                                  Test_nwrd_NumberSpellerFR_
+
+// end
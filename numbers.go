@@ -10,6 +10,12 @@ package zr
 //   MaxUint
 //   MinInt
 //
+// # Number Words Options
+//   AndMode int
+//   NumberScale int
+//   WordsCase int
+//   InWordsOptions struct
+//
 // # Regular Expressions
 //   NumberEx
 //
@@ -23,15 +29,28 @@ package zr
 //   MinMaxGap(values []int) (min, max int)
 //
 // # Formatting Functions
+//   AmountInWordsEN(n Currency, fmt string, opts ...InWordsOptions) string
+//   BigIntInWordsEN(number *big.Int, opts ...InWordsOptions) string
 //   BlankZero(s string) string
 //   CommaDelimit(number string, decimalPlaces int) string
-//   IntInWordsEN(number int64) string
+//   IntInWordsEN(number int64, opts ...InWordsOptions) string
+//
+// # Number Words Helpers
+//   amountInWordsEN(
+//       bigUnits *big.Int, smlUnits int64, fmt string, opts []InWordsOptions,
+//   ) string
+//   inWordsOptionsOf(opts []InWordsOptions) InWordsOptions
+//   (opt InWordsOptions) caseOf(s string, isFirst bool) string
+//   (opt InWordsOptions) scaleUnits() []inWordsUnit
+//   (opt InWordsOptions) spell(words []string, n *big.Int, isTop bool) []string
+//   (opt InWordsOptions) spell1000(words []string, n int64) []string
 
 import (
 	"bytes"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"regexp"
 	"sort"
@@ -74,6 +93,84 @@ var TensEN = []string{
 	"Sixty", "Seventy", "Eighty", "Ninety",
 }
 
+// -----------------------------------------------------------------------------
+// # Number Words Options
+
+// AndMode specifies where IntInWordsEN() and
+// AmountInWordsEN() insert the word "and".
+type AndMode int
+
+const (
+	// AndHundreds inserts "and" after hundreds,
+	// e.g. "One Hundred and Five", "One Thousand Five"
+	AndHundreds AndMode = iota
+
+	// AndNone never inserts "and",
+	// e.g. "One Hundred Five", "One Thousand Five"
+	AndNone
+
+	// AndBritish inserts "and" after hundreds, and before the final
+	// tens and units of larger numbers, as usual in British English,
+	// e.g. "One Hundred and Five", "One Thousand and Five"
+	AndBritish
+)
+
+// NumberScale specifies the names of large numbers
+// used by IntInWordsEN() and AmountInWordsEN().
+type NumberScale int
+
+const (
+	// ScaleShort is the short scale used in the United States and in
+	// modern British English: each new name is a thousand times the
+	// previous one, e.g. Million (10^6), Billion (10^9)
+	ScaleShort NumberScale = iota
+
+	// ScaleLong is the traditional British long scale: each new name is
+	// a million times the previous one, e.g. Million (10^6), Thousand
+	// Million (10^9), Billion (10^12)
+	ScaleLong
+
+	// ScaleIndian uses the Indian numbering system, e.g.
+	// Lakh (10^5), Crore (10^7), Thousand Crore (10^10)
+	ScaleIndian
+)
+
+// WordsCase specifies the capitalization of
+// number words, e.g. "Twenty One" or "twenty one".
+type WordsCase int
+
+const (
+	// CaseTitle capitalizes each number word, e.g. "Twenty One"
+	CaseTitle WordsCase = iota
+
+	// CaseLower writes all words in lower case, e.g. "twenty one"
+	CaseLower
+
+	// CaseUpper writes all words in upper case, e.g. "TWENTY ONE"
+	CaseUpper
+
+	// CaseSentence capitalizes only the first word, e.g. "Twenty one"
+	CaseSentence
+)
+
+// InWordsOptions specifies how IntInWordsEN(), BigIntInWordsEN()
+// and AmountInWordsEN() write numbers in words. The zero value
+// gives the default style: short scale, "and" after hundreds,
+// title case and no hyphens, e.g. "Two Hundred and Fifty Six".
+type InWordsOptions struct {
+	// Scale specifies the names of large numbers
+	Scale NumberScale
+
+	// And specifies where to insert the word "and"
+	And AndMode
+
+	// Case specifies the capitalization of words
+	Case WordsCase
+
+	// Hyphenate joins tens and units with a hyphen, e.g. "Twenty-One"
+	Hyphenate bool
+} //                                                              InWordsOptions
+
 // -----------------------------------------------------------------------------
 // # Regular Expressions
 
@@ -465,6 +562,10 @@ func MaxIntOf(values []int) (max int, found bool) {
 //      If both specifiers are omitted for either denomination, the
 //      denomination will not be returned. See the examples below.
 //
+// opts: optionally specifies the number words style (see InWordsOptions).
+//       The options' case applies to the number words, "and" and "Only",
+//       but not to the currency names given in fmt.
+//
 // Returns: The amount in words as a string, including the currency
 //          and the word "Only". Uses proper capitalisation.
 //
@@ -473,77 +574,43 @@ func MaxIntOf(values []int) (max int, found bool) {
 //          (11.02,"Dollar;;Cent")  "Eleven Dollars and Two Cents"
 //          (11.02,"Euro")          "Eleven Euros"
 //          (11.02,"Pound;;;Pence") "Eleven Pounds and Two Pence"
-func AmountInWordsEN(n Currency, fmt string) string {
+func AmountInWordsEN(n Currency, fmt string, opts ...InWordsOptions) string {
 	i := n.i64
 	if i < 0 {
 		i = -n.i64
 	}
-	var (
-		bigUnits = i / 1e4
-		smlUnits = (i - bigUnits*1e4) / 100
-		hasOnly  = strings.HasSuffix(strings.ToLower(fmt), "only")
-	)
-	if hasOnly {
-		fmt = fmt[:len(fmt)-4]
-	}
-	getPart := func(partNo int) string {
-		parts := strings.Split(fmt, ";")
-		if partNo < 0 || partNo >= len(parts) {
-			return ""
-		}
-		return parts[partNo]
+	bigUnits := i / 1e4
+	return amountInWordsEN(
+		big.NewInt(bigUnits), (i-bigUnits*1e4)/100, fmt, opts)
+} //                                                             AmountInWordsEN
+
+// BigIntInWordsEN returns the given number as a description in words,
+// like IntInWordsEN(), but accepts numbers of any size. Numbers beyond
+// the largest scale name are written using repeated names, e.g.
+// "One Thousand Vigintillion" for 10^66 with the short scale.
+//
+// opts optionally specifies the style of the words (see InWordsOptions).
+// Logs an error and returns a blank string if number is nil.
+func BigIntInWordsEN(number *big.Int, opts ...InWordsOptions) string {
+	if number == nil {
+		mod.Error(EInvalidArg, "^number", ":", nil)
+		return ""
 	}
-	var (
-		big1 = getPart(0)
-		bigN = getPart(1)
-		sml1 = getPart(2)
-		smlN = getPart(3)
-		ret  = ""
-	)
-	if bigUnits > 0 && (big1+bigN) != "" {
-		ret += IntInWordsEN(bigUnits) + " "
-		if big1 == "" && bigN != "" {
-			ret += bigN
-		} else if big1 != "" && bigN == "" {
-			ret += big1
-			if bigUnits > 1 {
-				ret += "s"
-			}
-		} else if big1 != "" && bigN != "" {
-			if bigUnits == 1 {
-				ret += big1
-			}
-			if bigUnits > 1 {
-				ret += bigN
-			}
-		}
+	opt := inWordsOptionsOf(opts)
+	var words []string
+	if number.Sign() < 0 {
+		words = append(words, "Minus")
 	}
-	if ((sml1 + smlN) != "") && smlUnits > 0 {
-		if (big1+bigN != "") && bigUnits > 0 {
-			ret += " and "
-		}
-		ret += IntInWordsEN(smlUnits) + " "
-		if sml1 == "" && smlN != "" {
-			ret += smlN
-		} else if sml1 != "" && smlN == "" {
-			ret += sml1
-			if smlUnits > 1 {
-				ret += "s"
-			}
-		} else if sml1 != "" && smlN != "" {
-			if smlUnits == 1 {
-				ret += sml1
-			}
-			if smlUnits > 1 {
-				ret += smlN
-			}
-		}
+	if number.Sign() == 0 {
+		words = append(words, DigitNamesEN[0])
+	} else {
+		words = opt.spell(words, new(big.Int).Abs(number), true)
 	}
-	if hasOnly && len(strings.TrimSpace(ret)) > 0 {
-		ret += " Only"
+	for i, word := range words {
+		words[i] = opt.caseOf(word, i == 0)
 	}
-	return ret
-} //                                                             AmountInWordsEN
+	return strings.Join(words, " ")
+} //                                                             BigIntInWordsEN
 
 // BlankZero returns a blank string when given a string
 // containing only zeros, decimal points and white-spaces.
@@ -628,89 +695,228 @@ func CommaDelimit(number string, decimalPlaces int) string {
 // IntInWordsEN returns the given number as a description in words.
 // Uses English language names, hence the 'EN' suffix.
 // This function is useful for showing amounts in invoices, etc.
-// 'number' must be a positive integer in the range of 0 to 1 trillion.
-// E.g. IntInWordsEN(256) returns "Two Hundred and Fifty Six"
-func IntInWordsEN(number int64) string {
-	if number == 0 {
-		return DigitNamesEN[0]
-	}
-	// divide number into billions, millions, thousands, units, etc.
-	groups := []struct {
-		n    int64
-		unit string
-		base int64
-	}{
-		{0, "", 1},               // units
-		{0, "Thousand", 1e3},     // 10^3 (10^3 has 3 zeros, etc)
-		{0, "Million", 1e6},      // 10^6
-		{0, "Billion", 1e9},      // 10^9
-		{0, "Trillion", 1e12},    // 10^12
-		{0, "Quadrillion", 1e15}, // 10^15
-		{0, "Quintillion", 1e18}, // 10^18
-		// math.MaxInt64 = 9223372036854775807
-	}
-	{
-		n := number
-		for i := len(groups) - 1; i >= 0; i-- {
-			gr := &groups[i]
-			if n < gr.base {
-				continue
-			}
-			gr.n = n / gr.base
-			n -= gr.n * gr.base
+// Negative numbers start with "Minus". For numbers that don't
+// fit in int64, use BigIntInWordsEN().
+//
+// opts optionally specifies the style of the words (see InWordsOptions).
+// E.g. IntInWordsEN(256) returns "Two Hundred and Fifty Six",
+// and IntInWordsEN(1234000, InWordsOptions{Scale: ScaleIndian})
+// returns "Twelve Lakh Thirty Four Thousand".
+func IntInWordsEN(number int64, opts ...InWordsOptions) string {
+	return BigIntInWordsEN(big.NewInt(number), opts...)
+} //                                                                IntInWordsEN
+
+// -----------------------------------------------------------------------------
+// # Number Words Helpers
+
+// amountInWordsEN implements AmountInWordsEN() and BigCurrency.InWordsEN().
+// bigUnits is the number of large units, e.g. dollars, and smlUnits the
+// number of small units, e.g. cents. Both must not be negative.
+func amountInWordsEN(
+	bigUnits *big.Int, smlUnits int64, fmt string, opts []InWordsOptions,
+) string {
+	opt := inWordsOptionsOf(opts)
+	var (
+		isOne   = bigUnits.Cmp(big.NewInt(1)) == 0
+		isMany  = bigUnits.Cmp(big.NewInt(1)) > 0
+		hasOnly = strings.HasSuffix(strings.ToLower(fmt), "only")
+	)
+	if hasOnly {
+		fmt = fmt[:len(fmt)-4]
+	}
+	getPart := func(partNo int) string {
+		parts := strings.Split(fmt, ";")
+		if partNo < 0 || partNo >= len(parts) {
+			return ""
 		}
+		return parts[partNo]
 	}
-	var retBuf bytes.Buffer
-	ws := retBuf.WriteString
-	for i := len(groups) - 1; i >= 0; i-- {
-		n := groups[i].n
-		if n == 0 {
-			continue
+	var (
+		big1 = getPart(0)
+		bigN = getPart(1)
+		sml1 = getPart(2)
+		smlN = getPart(3)
+		ret  = ""
+	)
+	// numbers after the first one are not capitalized in sentence case
+	spell := func(number *big.Int) string {
+		o := opt
+		if o.Case == CaseSentence && ret != "" {
+			o.Case = CaseLower
 		}
-		unit := groups[i].unit
-		//
-		// count hundreds, tens and units
-		n100 := n / 100
-		n -= n100 * 100
-		n10 := n / 10
-		n -= n10 * 10
-		n1 := n
-		//
-		// append names of hundreds to result
-		if n100 != 0 {
-			ws(" ")
-			ws(DigitNamesEN[n100])
-			ws(" Hundred")
-		}
-		if (n1 != 0 || n10 != 0) && n100 != 0 {
-			ws(" and")
-		}
-		// append tens and units (or teen numbers) to result
-		switch {
-		case n10 == 1 && n1 != 0:
-			if n1 >= 0 && n1 <= 9 {
-				ws(" ")
-				ws(TeensEN[n1-1])
+		return BigIntInWordsEN(number, o)
+	}
+	if bigUnits.Sign() > 0 && (big1+bigN) != "" {
+		ret += spell(bigUnits) + " "
+		if big1 == "" && bigN != "" {
+			ret += bigN
+		} else if big1 != "" && bigN == "" {
+			ret += big1
+			if isMany {
+				ret += "s"
+			}
+		} else if big1 != "" && bigN != "" {
+			if isOne {
+				ret += big1
+			}
+			if isMany {
+				ret += bigN
 			}
-		case n10 == 0 && n1 != 0:
-			{
-				ws(" ")
-				ws(DigitNamesEN[n1])
+		}
+	}
+	if ((sml1 + smlN) != "") && smlUnits > 0 {
+		if (big1+bigN != "") && bigUnits.Sign() > 0 {
+			ret += " " + opt.caseOf("and", false) + " "
+		}
+		ret += spell(big.NewInt(smlUnits)) + " "
+		if sml1 == "" && smlN != "" {
+			ret += smlN
+		} else if sml1 != "" && smlN == "" {
+			ret += sml1
+			if smlUnits > 1 {
+				ret += "s"
 			}
-		default:
-			if n10 != 0 {
-				ws(" ")
-				ws(TensEN[n10-1])
+		} else if sml1 != "" && smlN != "" {
+			if smlUnits == 1 {
+				ret += sml1
 			}
-			if n1 != 0 {
-				ws(" ")
-				ws(DigitNamesEN[n1])
+			if smlUnits > 1 {
+				ret += smlN
 			}
 		}
-		ws(" ")
-		ws(unit)
 	}
-	return strings.TrimSpace(retBuf.String())
-} //                                                                IntInWordsEN
+	if hasOnly && len(strings.TrimSpace(ret)) > 0 {
+		ret += " " + opt.caseOf("Only", false)
+	}
+	return ret
+} //                                                             amountInWordsEN
+
+// inWordsUnit is the name of a power of ten, e.g. {6, "Million"}.
+type inWordsUnit struct {
+	exp  int
+	name string
+}
+
+// inWordsOptionsOf returns the options passed to a variadic
+// opts parameter, or the default options if none are given.
+// Logs an error if more than one option value is given.
+func inWordsOptionsOf(opts []InWordsOptions) InWordsOptions {
+	switch len(opts) {
+	case 0:
+		return InWordsOptions{}
+	case 1:
+		return opts[0]
+	}
+	mod.Error(EInvalidArg + ": Too many 'opts' values")
+	return opts[0]
+} //                                                            inWordsOptionsOf
+
+// caseOf returns a word (written in title case) in the case
+// specified by the options. isFirst specifies if the
+// word starts a sentence when using CaseSentence.
+func (opt InWordsOptions) caseOf(s string, isFirst bool) string {
+	switch opt.Case {
+	case CaseLower:
+		return strings.ToLower(s)
+	case CaseUpper:
+		return strings.ToUpper(s)
+	case CaseSentence:
+		s = strings.ToLower(s)
+		if isFirst && s != "" {
+			s = strings.ToUpper(s[:1]) + s[1:]
+		}
+	}
+	return s
+} //                                                                      caseOf
+
+// scaleUnits returns the names of powers of ten used by
+// the options' number scale, from largest to smallest.
+func (opt InWordsOptions) scaleUnits() []inWordsUnit {
+	switch opt.Scale {
+	case ScaleLong:
+		return []inWordsUnit{
+			{60, "Decillion"}, {54, "Nonillion"}, {48, "Octillion"},
+			{42, "Septillion"}, {36, "Sextillion"}, {30, "Quintillion"},
+			{24, "Quadrillion"}, {18, "Trillion"}, {12, "Billion"},
+			{6, "Million"}, {3, "Thousand"},
+		}
+	case ScaleIndian:
+		return []inWordsUnit{{7, "Crore"}, {5, "Lakh"}, {3, "Thousand"}}
+	}
+	return []inWordsUnit{
+		{63, "Vigintillion"}, {60, "Novemdecillion"},
+		{57, "Octodecillion"}, {54, "Septendecillion"},
+		{51, "Sexdecillion"}, {48, "Quindecillion"},
+		{45, "Quattuordecillion"}, {42, "Tredecillion"},
+		{39, "Duodecillion"}, {36, "Undecillion"}, {33, "Decillion"},
+		{30, "Nonillion"}, {27, "Octillion"}, {24, "Septillion"},
+		{21, "Sextillion"}, {18, "Quintillion"}, {15, "Quadrillion"},
+		{12, "Trillion"}, {9, "Billion"}, {6, "Million"}, {3, "Thousand"},
+	}
+} //                                                                  scaleUnits
+
+// spell appends the words of a positive number to words and returns
+// the result. n is changed. isTop is false when spelling the
+// number of millions, etc. within a larger number.
+func (opt InWordsOptions) spell(
+	words []string, n *big.Int, isTop bool,
+) []string {
+	var (
+		base  = new(big.Int)
+		count = new(big.Int)
+		ten   = big.NewInt(10)
+	)
+	// British style: "One Thousand and Five"
+	if isTop && opt.And == AndBritish && n.Cmp(big.NewInt(1000)) >= 0 {
+		tail := new(big.Int).Mod(n, big.NewInt(1000)).Int64()
+		if tail > 0 && tail < 100 {
+			words = opt.spell(words, n.Sub(n, big.NewInt(tail)), false)
+			words = append(words, "and")
+			return opt.spell1000(words, tail)
+		}
+	}
+	for _, unit := range opt.scaleUnits() {
+		base.Exp(ten, big.NewInt(int64(unit.exp)), nil)
+		if n.Cmp(base) < 0 {
+			continue
+		}
+		count.QuoRem(n, base, n)
+		words = opt.spell(words, count, false)
+		words = append(words, unit.name)
+	}
+	if n.Sign() > 0 {
+		words = opt.spell1000(words, n.Int64())
+	}
+	return words
+} //                                                                       spell
+
+// spell1000 appends the words of a number from 1 to 999
+// to words, and returns the result.
+func (opt InWordsOptions) spell1000(words []string, n int64) []string {
+	var (
+		n100 = n / 100
+		n10  = n % 100 / 10
+		n1   = n % 10
+	)
+	if n100 != 0 {
+		words = append(words, DigitNamesEN[n100], "Hundred")
+		if n%100 != 0 && opt.And != AndNone {
+			words = append(words, "and")
+		}
+	}
+	switch {
+	case n10 == 1 && n1 != 0:
+		words = append(words, TeensEN[n1-1])
+	case n10 == 0 && n1 != 0:
+		words = append(words, DigitNamesEN[n1])
+	case n10 != 0 && n1 == 0:
+		words = append(words, TensEN[n10-1])
+	case n10 != 0 && opt.Hyphenate:
+		words = append(words, TensEN[n10-1]+"-"+DigitNamesEN[n1])
+	case n10 != 0:
+		words = append(words, TensEN[n10-1], DigitNamesEN[n1])
+	}
+	return words
+} //                                                                   spell1000
 
 // end
@@ -6,11 +6,14 @@
 package zr
 
 // # Numeric Function Tests
+//   Test_nums_AmountInWordsEN_
+//   Test_nums_BigIntInWordsEN_
 //   Test_nums_BlankZero_
 //   Test_nums_CommaDelimit_
 //   Test_nums_Float64_ // TODO: create unit test
 //   Test_nums_Int_
 //   Test_nums_IntInWordsEN_
+//   Test_nums_IntInWordsEN_opts_
 //   Test_nums_IsNumber_
 
 //  to test all items in numbers.go use:
@@ -23,6 +26,8 @@ package zr
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -111,6 +116,90 @@ func Test_nums_CommaDelimit_(t *testing.T) {
 	test("12345678", 0, "12,345,678")
 } //                                                     Test_nums_CommaDelimit_
 
+// go test --run Test_nums_AmountInWordsEN_
+func Test_nums_AmountInWordsEN_(t *testing.T) {
+	TBegin(t)
+	//
+	// AmountInWordsEN(n Currency, fmt string, opts ...InWordsOptions) string
+	//
+	test := func(n Currency, fmt string, opt InWordsOptions, expect string) {
+		got := AmountInWordsEN(n, fmt, opt)
+		if got != expect {
+			t.Errorf("AmountInWordsEN(%v, %q, %+v) returned %q instead of %q",
+				n, fmt, opt, got, expect)
+		}
+	}
+	var (
+		def = InWordsOptions{}
+		up  = InWordsOptions{Case: CaseUpper, Hyphenate: true}
+		sen = InWordsOptions{Case: CaseSentence, And: AndBritish}
+	)
+	test(CurrencyOf(11.02), ";;Cent;Only", def, "Two Cents Only")
+	test(CurrencyOf(11.02), "Dollar;;Cent", def, "Eleven Dollars and Two Cents")
+	test(CurrencyOf(11.02), "Euro", def, "Eleven Euros")
+	test(CurrencyOf(11.02), "Pound;;;Pence", def, "Eleven Pounds and Two Pence")
+	test(CurrencyOf(21.22), "Dollar;;Cent;Only", up,
+		"TWENTY-ONE Dollars AND TWENTY-TWO Cents ONLY")
+	test(CurrencyOf(1005.5), "pound;;penny;pence;Only", sen,
+		"One thousand and five pounds and fifty pence only")
+	//
+	// the same as Currency.InWordsEN() and without options
+	TEqual(t, AmountInWordsEN(CurrencyOf(1234.56), "Dollar;;Cent"),
+		CurrencyOf(1234.56).InWordsEN("Dollar;;Cent"))
+	TEqual(t, AmountInWordsEN(CurrencyOf(1234.56), "Dollar;;Cent", def),
+		AmountInWordsEN(CurrencyOf(1234.56), "Dollar;;Cent"))
+} //                                                  Test_nums_AmountInWordsEN_
+
+// go test --run Test_nums_BigIntInWordsEN_
+func Test_nums_BigIntInWordsEN_(t *testing.T) {
+	TBegin(t)
+	//
+	// BigIntInWordsEN(number *big.Int, opts ...InWordsOptions) string
+	//
+	test := func(number string, opt InWordsOptions, expect string) {
+		n, _ := new(big.Int).SetString(number, 10)
+		got := BigIntInWordsEN(n, opt)
+		if got != expect {
+			t.Errorf("BigIntInWordsEN(%s, %+v) returned %q instead of %q",
+				number, opt, got, expect)
+		}
+	}
+	var (
+		short = InWordsOptions{}
+		long  = InWordsOptions{Scale: ScaleLong}
+		india = InWordsOptions{Scale: ScaleIndian}
+	)
+	test("0", short, "Zero")
+	test("-5", short, "Minus Five")
+	test("18446744073709551616", short, "Eighteen Quintillion"+
+		" Four Hundred and Forty Six Quadrillion"+
+		" Seven Hundred and Forty Four Trillion"+
+		" Seventy Three Billion"+
+		" Seven Hundred and Nine Million"+
+		" Five Hundred and Fifty One Thousand"+
+		" Six Hundred and Sixteen")
+	test("1"+strings.Repeat("0", 63), short, "One Vigintillion")
+	test("1"+strings.Repeat("0", 66), short, "One Thousand Vigintillion")
+	test("1000000000", long, "One Thousand Million")
+	test("2000000000000", long, "Two Billion")
+	test("5000000000000000000", long, "Five Trillion")
+	test("100000", india, "One Lakh")
+	test("12345678", india, "One Crore Twenty Three Lakh"+
+		" Forty Five Thousand Six Hundred and Seventy Eight")
+	test("1000000000000", india, "One Lakh Crore")
+	//
+	// nil is logged
+	{
+		DisableErrors()
+		ec1 := GetErrorCount()
+		got := BigIntInWordsEN(nil)
+		ec2 := GetErrorCount()
+		EnableErrors()
+		TEqual(t, got, "")
+		TEqual(t, ec2-ec1, 1)
+	}
+} //                                                  Test_nums_BigIntInWordsEN_
+
 // go test --run Test_nums_IntInWordsEN_
 func Test_nums_IntInWordsEN_(t *testing.T) {
 	TBegin(t)
@@ -248,6 +337,58 @@ func Test_nums_IntInWordsEN_(t *testing.T) {
 	)
 } //                                                     Test_nums_IntInWordsEN_
 
+// go test --run Test_nums_IntInWordsEN_opts_
+func Test_nums_IntInWordsEN_opts_(t *testing.T) {
+	TBegin(t)
+	//
+	// IntInWordsEN(number int64, opts ...InWordsOptions) string
+	//
+	test := func(input int64, opt InWordsOptions, expect string) {
+		got := IntInWordsEN(input, opt)
+		if got != expect {
+			t.Errorf("IntInWordsEN(%d, %+v) returned %q instead of %q",
+				input, opt, got, expect)
+		}
+	}
+	// the zero value gives the default style
+	test(256, InWordsOptions{}, "Two Hundred and Fifty Six")
+	//
+	// scales
+	test(1234000, InWordsOptions{Scale: ScaleIndian},
+		"Twelve Lakh Thirty Four Thousand")
+	test(1000000000, InWordsOptions{Scale: ScaleIndian}, "One Hundred Crore")
+	test(1500000000, InWordsOptions{Scale: ScaleLong},
+		"One Thousand Five Hundred Million")
+	//
+	// "and" insertion
+	test(105, InWordsOptions{And: AndNone}, "One Hundred Five")
+	test(1005, InWordsOptions{}, "One Thousand Five")
+	test(1005, InWordsOptions{And: AndBritish}, "One Thousand and Five")
+	test(2000020, InWordsOptions{And: AndBritish}, "Two Million and Twenty")
+	test(2000120, InWordsOptions{And: AndBritish},
+		"Two Million One Hundred and Twenty")
+	test(105, InWordsOptions{And: AndBritish}, "One Hundred and Five")
+	//
+	// case and hyphens
+	test(21, InWordsOptions{Hyphenate: true}, "Twenty-One")
+	test(1021, InWordsOptions{Case: CaseLower, Hyphenate: true},
+		"one thousand twenty-one")
+	test(-321, InWordsOptions{Case: CaseUpper},
+		"MINUS THREE HUNDRED AND TWENTY ONE")
+	test(121, InWordsOptions{Case: CaseSentence, Hyphenate: true},
+		"One hundred and twenty-one")
+	//
+	// int64 limits
+	test(math.MinInt64, InWordsOptions{Case: CaseLower},
+		"minus nine quintillion"+
+			" two hundred and twenty three quadrillion"+
+			" three hundred and seventy two trillion"+
+			" thirty six billion"+
+			" eight hundred and fifty four million"+
+			" seven hundred and seventy five thousand"+
+			" eight hundred and eight")
+} //                                                Test_nums_IntInWordsEN_opts_
+
 // go test --run Test_nums_Int_
 func Test_nums_Int_(t *testing.T) {
 	TBegin(t)
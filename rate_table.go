@@ -0,0 +1,460 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                 zr/[rate_table.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # RateTable Type:
+//   RateTable struct
+//   rateEntry struct
+//   rateRow struct
+//
+// # Methods (ob *RateTable)
+//   ) Convert(
+//       amount Currency, from, to string, date time.Time,
+//   ) (Currency, error)
+//   ) ConvertMoney(m Money, to string, date time.Time) (Money, error)
+//   ) LoadCSV(r io.Reader) error
+//   ) LoadJSON(r io.Reader) error
+//   ) Rate(from, to string, date time.Time) (*big.Rat, error)
+//   ) Set(from, to string, date time.Time, rate interface{}) error
+//
+// # Private Methods (ob *RateTable)
+//   ) find(from, to string, day time.Time) (*big.Rat, time.Time, bool)
+//   ) load(rows []rateRow) error
+//   ) pair(from, to string, day time.Time) (*big.Rat, bool)
+//   ) set(from, to string, day time.Time, rate *big.Rat)
+//
+// # Helper Functions
+//   rateCheckCodes(from, to string) error
+//   rateCode(code string) string
+//   rateDay(date time.Time) time.Time
+//   rateOf(value interface{}) (*big.Rat, error)
+//   (row rateRow) parse() (
+//       from, to string, day time.Time, rate *big.Rat, err error,
+//   )
+
+import (
+	"encoding/csv"
+	"encoding/json" // json.Unmarshal is used via mod.json.* (mockable)
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateDateRx matches dates in "YYYY-MM-DD" format.
+var rateDateRx = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// -----------------------------------------------------------------------------
+// # RateTable Type:
+
+// RateTable stores exchange rates between currencies, each effective from
+// a given date until the next rate for the same pair of currencies, and
+// converts amounts between currencies using these rates.
+//
+// A rate from EUR to USD of 1.0842 means one euro buys 1.0842 dollars.
+// If a pair has no rate, the inverse rate is used (e.g. USD to EUR),
+// then a rate calculated through the Base currency, if specified
+// (e.g. EUR to GBP is calculated as EUR to USD times USD to GBP).
+//
+// Rates are stored exactly as rational numbers, so inverse and
+// triangulated rates don't lose precision. Only converted amounts
+// are rounded, as specified by Rounding and MinorUnits.
+//
+// The zero value is an empty table ready to use. Set the exported
+// fields before using the table. Adding rates and converting
+// amounts is safe for concurrent use.
+type RateTable struct {
+	// Base is the currency code used to calculate rates between
+	// currencies that have no direct or inverse rate, e.g. "USD".
+	// If blank, rates are not triangulated.
+	Base string
+
+	// Rounding specifies how converted amounts are rounded.
+	// The zero value is RoundHalfEven.
+	Rounding RoundingMode
+
+	// MinorUnits rounds converted amounts to the number of decimal
+	// places used by the target currency (e.g. 2 for USD, 0 for JPY),
+	// as given by CurrencyMinorUnits(). Otherwise, amounts are
+	// rounded to 4 decimal places.
+	MinorUnits bool
+
+	mu    sync.RWMutex
+	rates map[string][]rateEntry // rates by "FROM/TO", sorted by date
+} //                                                                   RateTable
+
+// rateEntry is an exchange rate effective from a date.
+type rateEntry struct {
+	day  time.Time
+	rate *big.Rat
+} //                                                                   rateEntry
+
+// rateRow is an exchange rate read by LoadCSV() or LoadJSON().
+type rateRow struct {
+	Date string      `json:"date"`
+	From string      `json:"from"`
+	To   string      `json:"to"`
+	Rate json.Number `json:"rate"`
+	no   int         // number of the CSV record or JSON array item
+} //                                                                     rateRow
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *RateTable)
+
+// Convert converts an amount from one currency to another, using the
+// rate effective on the specified date (see Rate()), and rounds the
+// result as specified by Rounding and MinorUnits.
+//
+// Returns zero and an error if there is no rate for the date, or an
+// overflow value and an error if the result doesn't fit in Currency.
+// Does not log errors.
+func (ob *RateTable) Convert(
+	amount Currency, from, to string, date time.Time,
+) (Currency, error) {
+	if amount.IsOverflow() {
+		return amount, errCurrencyOverflowOperand
+	}
+	rate, err := ob.Rate(from, to, date)
+	if err != nil {
+		return Currency{0}, err
+	}
+	places := 4
+	if ob.MinorUnits {
+		n, found := CurrencyMinorUnits(to)
+		if found && n >= 0 && n < 4 {
+			places = n
+		}
+	}
+	unit := big.NewInt(1)
+	for i := places; i < 4; i++ {
+		unit.Mul(unit, big.NewInt(10))
+	}
+	// round to whole units, then scale back to Currency's 4 places
+	var (
+		num = new(big.Int).Mul(big.NewInt(amount.i64), rate.Num())
+		den = new(big.Int).Mul(rate.Denom(), unit)
+		ret = roundQuo(num, den, ob.Rounding)
+	)
+	return currencyOfBigE(ret.Mul(ret, unit), amount, " ", from, " to ", to)
+} //                                                                     Convert
+
+// ConvertMoney converts a Money value to another currency, like
+// Convert(), and returns the result in the target currency.
+// Does not log errors.
+func (ob *RateTable) ConvertMoney(
+	m Money, to string, date time.Time,
+) (Money, error) {
+	to = rateCode(to)
+	amount, err := ob.Convert(m.amount, m.code, to, date)
+	return Money{amount: amount, code: to}, err
+} //                                                                ConvertMoney
+
+// LoadCSV reads exchange rates from CSV data and adds them to the table.
+//
+// Each record has four fields: the effective date in "YYYY-MM-DD"
+// format, the source currency code, the target currency code and the
+// rate, e.g. "2024-01-31,EUR,USD,1.0842". The first record is skipped
+// if it is a header, i.e. its first field is not a date. Blank lines
+// and lines starting with '#' are ignored.
+//
+// If any record is invalid, returns an error that specifies
+// the record and doesn't add any rates. Does not log errors.
+func (ob *RateTable) LoadCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return fmt.Errorf("%s rates CSV: %v", EFailedReading, err)
+	}
+	rows := make([]rateRow, 0, len(records))
+	for i, rec := range records {
+		if i == 0 && !rateDateRx.MatchString(strings.TrimSpace(rec[0])) {
+			continue // header
+		}
+		rows = append(rows, rateRow{rec[0], rec[1], rec[2],
+			json.Number(strings.TrimSpace(rec[3])), i + 1})
+	}
+	return ob.load(rows)
+} //                                                                     LoadCSV
+
+// LoadJSON reads exchange rates from JSON data and adds them to the
+// table. The data is an array of objects having "date", "from", "to"
+// and "rate" fields. For example, the following array has one rate:
+// [{"date": "2024-01-31", "from": "EUR", "to": "USD", "rate": 1.0842}]
+//
+// The rate can be a number or a string. It is read exactly,
+// without converting it to float64.
+//
+// If any rate is invalid, returns an error that specifies
+// the rate and doesn't add any rates. Does not log errors.
+func (ob *RateTable) LoadJSON(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s rates JSON: %v", EFailedReading, err)
+	}
+	var rows []rateRow
+	err = mod.json.Unmarshal(data, &rows)
+	if err != nil {
+		return fmt.Errorf("%s rates JSON: %v", EFailedParsing, err)
+	}
+	for i := range rows {
+		rows[i].no = i + 1
+	}
+	return ob.load(rows)
+} //                                                                    LoadJSON
+
+// Rate returns the exchange rate from one currency to another, effective
+// on the specified date: the rate with the latest date that is not after
+// the given date. Only the calendar date of date is used.
+//
+// If the pair has no rate, uses the inverse rate; if both a rate and
+// an inverse rate are effective, uses the one with the later date.
+// If neither is found and Base is specified, multiplies the rates
+// from the source currency to Base and from Base to the target.
+//
+// Currency codes are not case-sensitive. The rate from a currency
+// to itself is always 1. Returns nil and an error if no rate is found.
+func (ob *RateTable) Rate(
+	from, to string, date time.Time,
+) (*big.Rat, error) {
+	if ob == nil {
+		return nil, errors.New(ENilReceiver)
+	}
+	from, to = rateCode(from), rateCode(to)
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("%s: blank currency code", EInvalidArg)
+	}
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	day := rateDay(date)
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+	if ret, found := ob.pair(from, to, day); found {
+		return ret, nil
+	}
+	base := rateCode(ob.Base)
+	if base != "" && base != from && base != to {
+		a, foundA := ob.pair(from, base, day)
+		b, foundB := ob.pair(base, to, day)
+		if foundA && foundB {
+			return a.Mul(a, b), nil
+		}
+	}
+	return nil, fmt.Errorf("%s: rate from %s to %s on %s",
+		ENotFound, from, to, day.Format("2006-01-02"))
+} //                                                                        Rate
+
+// Set adds an exchange rate from one currency to another, effective
+// from the specified date, replacing any rate set for the same date.
+// Only the calendar date of date is used.
+//
+// rate is the amount of the target currency bought by one unit of
+// the source currency. It can be a numeric string (e.g. "1.0842"
+// or "1/3"), json.Number, a float (used with its shortest decimal
+// representation), an integer, Currency or *big.Rat.
+//
+// Returns an error if a currency code is blank, if both codes
+// are the same, or if the rate is not a positive number.
+// Does not log errors.
+func (ob *RateTable) Set(
+	from, to string, date time.Time, rate interface{},
+) error {
+	if ob == nil {
+		return errors.New(ENilReceiver)
+	}
+	from, to = rateCode(from), rateCode(to)
+	err := rateCheckCodes(from, to)
+	if err != nil {
+		return err
+	}
+	r, err := rateOf(rate)
+	if err != nil {
+		return err
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.set(from, to, rateDay(date), r)
+	return nil
+} //                                                                         Set
+
+// -----------------------------------------------------------------------------
+// # Private Methods (ob *RateTable)
+
+// find returns the rate from one currency to another with the latest
+// date that is not after day, the rate's date, and true if found.
+// ob.mu must be locked.
+func (ob *RateTable) find(
+	from, to string, day time.Time,
+) (*big.Rat, time.Time, bool) {
+	entries := ob.rates[from+"/"+to]
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].day.After(day)
+	})
+	if i == 0 {
+		return nil, time.Time{}, false
+	}
+	return entries[i-1].rate, entries[i-1].day, true
+} //                                                                        find
+
+// load validates rows of exchange rates and adds them to the table.
+// If any row is invalid, returns an error and doesn't add any rates.
+func (ob *RateTable) load(rows []rateRow) error {
+	if ob == nil {
+		return errors.New(ENilReceiver)
+	}
+	type parsed struct {
+		from, to string
+		day      time.Time
+		rate     *big.Rat
+	}
+	ar := make([]parsed, len(rows))
+	for i, row := range rows {
+		from, to, day, rate, err := row.parse()
+		if err != nil {
+			return fmt.Errorf("rate %d: %v", row.no, err)
+		}
+		ar[i] = parsed{from, to, day, rate}
+	}
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	for _, it := range ar {
+		ob.set(it.from, it.to, it.day, it.rate)
+	}
+	return nil
+} //                                                                        load
+
+// pair returns a new copy of the direct or inverse rate between two
+// currencies effective on day, and true if found. ob.mu must be locked.
+func (ob *RateTable) pair(from, to string, day time.Time) (*big.Rat, bool) {
+	var (
+		rate, day1, found   = ob.find(from, to, day)
+		inv, day2, invFound = ob.find(to, from, day)
+	)
+	if found && (!invFound || !day1.Before(day2)) {
+		return new(big.Rat).Set(rate), true
+	}
+	if invFound {
+		return new(big.Rat).Inv(inv), true
+	}
+	return nil, false
+} //                                                                        pair
+
+// set adds or replaces a rate. ob.mu must be locked.
+func (ob *RateTable) set(from, to string, day time.Time, rate *big.Rat) {
+	if ob.rates == nil {
+		ob.rates = make(map[string][]rateEntry)
+	}
+	var (
+		key     = from + "/" + to
+		entries = ob.rates[key]
+		i       = sort.Search(len(entries), func(i int) bool {
+			return !entries[i].day.Before(day)
+		})
+	)
+	if i < len(entries) && entries[i].day.Equal(day) {
+		entries[i].rate = rate
+		return
+	}
+	entries = append(entries, rateEntry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = rateEntry{day: day, rate: rate}
+	ob.rates[key] = entries
+} //                                                                         set
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// rateCheckCodes returns an error if a currency code
+// is blank, or if both currency codes are the same.
+func rateCheckCodes(from, to string) error {
+	switch {
+	case from == "" || to == "":
+		return fmt.Errorf("%s: blank currency code", EInvalidArg)
+	case from == to:
+		return fmt.Errorf("%s: rate from %s to itself", EInvalidArg, from)
+	}
+	return nil
+} //                                                              rateCheckCodes
+
+// rateCode returns a currency code in upper case, without spaces.
+func rateCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+} //                                                                    rateCode
+
+// rateDay returns the calendar date of date, at midnight UTC,
+// which is used as the effective date of exchange rates.
+func rateDay(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+} //                                                                     rateDay
+
+// rateOf converts an exchange rate to a rational number.
+// See RateTable.Set() for the types that can be converted.
+// Returns an error if the rate is not a positive number.
+func rateOf(value interface{}) (*big.Rat, error) {
+	var ret *big.Rat
+	switch v := value.(type) {
+	case string:
+		ret, _ = new(big.Rat).SetString(strings.TrimSpace(v))
+	case json.Number:
+		ret, _ = new(big.Rat).SetString(strings.TrimSpace(string(v)))
+	case float64:
+		ret = currencyFloatRat(v)
+	case float32:
+		// format with 32 bits, so that float32(0.1) is 0.1
+		ret, _ = new(big.Rat).SetString(
+			strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case int:
+		ret = big.NewRat(int64(v), 1)
+	case int64:
+		ret = big.NewRat(v, 1)
+	case Currency:
+		if !v.IsOverflow() {
+			ret = big.NewRat(v.i64, 1e4)
+		}
+	case *big.Rat:
+		if v != nil {
+			ret = new(big.Rat).Set(v)
+		}
+	}
+	if ret == nil || ret.Sign() <= 0 {
+		return nil, fmt.Errorf("%s exchange rate: %v", EInvalid, value)
+	}
+	return ret, nil
+} //                                                                      rateOf
+
+// parse validates an exchange rate read by LoadCSV() or LoadJSON(),
+// and returns its currency codes, effective date and rate.
+func (row rateRow) parse() (
+	from, to string, day time.Time, rate *big.Rat, err error,
+) {
+	from, to = rateCode(row.From), rateCode(row.To)
+	err = rateCheckCodes(from, to)
+	if err != nil {
+		return "", "", time.Time{}, nil, err
+	}
+	day, err = time.Parse("2006-01-02", strings.TrimSpace(row.Date))
+	if err != nil {
+		return "", "", time.Time{}, nil,
+			fmt.Errorf("%s date %q", EInvalid, row.Date)
+	}
+	rate, err = rateOf(row.Rate)
+	if err != nil {
+		return "", "", time.Time{}, nil, err
+	}
+	return from, to, day, rate, nil
+} //                                                                       parse
+
+// end
@@ -0,0 +1,310 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                            zr/[rate_table_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Methods (ob *RateTable)
+//   Test_rtbl_RateTable_Convert_
+//   Test_rtbl_RateTable_ConvertMoney_
+//   Test_rtbl_RateTable_LoadCSV_
+//   Test_rtbl_RateTable_LoadJSON_
+//   Test_rtbl_RateTable_Rate_
+//   Test_rtbl_RateTable_Set_
+
+//  to test all items in rate_table.go use:
+//      go test --run Test_rtbl_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rtblFixtureCSV contains exchange rates used by the tests
+const rtblFixtureCSV = `date,from,to,rate
+# rates are fictitious
+2024-01-01, EUR, USD, 1.10
+2024-02-01, EUR, USD, 1.08
+2024-01-01, USD, JPY, 150
+2024-01-15, GBP, USD, 1.25
+2024-03-01, USD, EUR, 0.9
+`
+
+// rtblFixtureJSON contains the same rates as rtblFixtureCSV
+const rtblFixtureJSON = `[
+    {"date": "2024-01-01", "from": "EUR", "to": "USD", "rate": 1.10},
+    {"date": "2024-02-01", "from": "EUR", "to": "USD", "rate": "1.08"},
+    {"date": "2024-01-01", "from": "USD", "to": "JPY", "rate": 150},
+    {"date": "2024-01-15", "from": "GBP", "to": "USD", "rate": 1.25},
+    {"date": "2024-03-01", "from": "usd", "to": "eur", "rate": 0.9}
+]`
+
+// rtblDate returns the date from a "YYYY-MM-DD" string.
+func rtblDate(s string) time.Time {
+	ret, _ := time.Parse("2006-01-02", s)
+	return ret
+} //                                                                    rtblDate
+
+// rtblTable returns a rate table loaded with rtblFixtureCSV.
+func rtblTable(t *testing.T) *RateTable {
+	var ret RateTable
+	err := ret.LoadCSV(strings.NewReader(rtblFixtureCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ret
+} //                                                                   rtblTable
+
+// -----------------------------------------------------------------------------
+// # Methods (ob *RateTable)
+
+// go test --run Test_rtbl_RateTable_Convert_
+func Test_rtbl_RateTable_Convert_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) Convert(
+	//     amount Currency, from, to string, date time.Time,
+	// ) (Currency, error)
+	//
+	rt := rtblTable(t)
+	test := func(amount, from, to, date, expect string) {
+		got, err := rt.Convert(CurrencyOf(amount), from, to, rtblDate(date))
+		if err != nil || got.String() != expect {
+			TFailf(t, `Convert(%s, %s, %s, %s) returned %v, %v`+
+				` instead of %s`, amount, from, to, date, got, err, expect)
+		}
+	}
+	test("100", "EUR", "USD", "2024-01-31", "110")
+	test("100", "EUR", "USD", "2024-02-01", "108")
+	test("100", "usd", "eur", "2024-01-31", "90.9091")
+	test("100", "USD", "EUR", "2024-03-05", "90")
+	test("12.34", "EUR", "EUR", "2020-01-01", "12.34")
+	//
+	// no rate before the first effective date
+	_, err := rt.Convert(CurrencyOf(1), "EUR", "USD", rtblDate("2023-12-31"))
+	TTrue(t, err != nil)
+	//
+	// triangulation through the base currency
+	_, err = rt.Convert(CurrencyOf(1), "EUR", "JPY", rtblDate("2024-01-31"))
+	TTrue(t, err != nil)
+	rt.Base = "usd"
+	test("100", "EUR", "JPY", "2024-01-31", "16500")
+	test("100", "GBP", "EUR", "2024-01-31", "113.6364")
+	test("1", "JPY", "GBP", "2024-01-31", "0.0053")
+	//
+	// rounding to the target currency's minor units
+	rt.MinorUnits = true
+	test("100", "GBP", "EUR", "2024-01-31", "113.64")
+	test("1.23", "EUR", "JPY", "2024-01-31", "203")
+	rt.Rounding = RoundUp
+	test("1.23", "EUR", "JPY", "2024-01-31", "203")
+	test("1.22", "EUR", "JPY", "2024-01-31", "202")
+	test("100", "GBP", "EUR", "2024-01-31", "113.64")
+	rt.Rounding = RoundDown
+	test("100", "GBP", "EUR", "2024-01-31", "113.63")
+	test("-100", "GBP", "EUR", "2024-01-31", "-113.63")
+	//
+	// overflow
+	got, err := rt.Convert(CurrencyOf(CurrencyIntLimit), "USD", "JPY",
+		rtblDate("2024-01-31"))
+	TTrue(t, got.IsOverflow())
+	TTrue(t, err != nil)
+} //                                                Test_rtbl_RateTable_Convert_
+
+// go test --run Test_rtbl_RateTable_ConvertMoney_
+func Test_rtbl_RateTable_ConvertMoney_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) ConvertMoney(
+	//     m Money, to string, date time.Time,
+	// ) (Money, error)
+	//
+	rt := rtblTable(t)
+	rt.MinorUnits = true
+	got, err := rt.ConvertMoney(MoneyOf(10, "EUR"), "usd",
+		rtblDate("2024-01-15"))
+	TEqual(t, got.String(), "USD 11")
+	TEqual(t, err, nil)
+	//
+	_, err = rt.ConvertMoney(MoneyOf(10, "EUR"), "CHF", rtblDate("2024-01-15"))
+	TTrue(t, err != nil)
+} //                                           Test_rtbl_RateTable_ConvertMoney_
+
+// go test --run Test_rtbl_RateTable_LoadCSV_
+func Test_rtbl_RateTable_LoadCSV_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) LoadCSV(r io.Reader) error
+	//
+	// no header
+	{
+		var rt RateTable
+		err := rt.LoadCSV(strings.NewReader("2024-01-01,EUR,USD,1.1\n"))
+		TEqual(t, err, nil)
+		r, _ := rt.Rate("EUR", "USD", rtblDate("2024-01-01"))
+		TEqual(t, r.RatString(), "11/10")
+	}
+	// errors, which don't add any rates
+	test := func(csv, expectErr string) {
+		var rt RateTable
+		err := rt.LoadCSV(strings.NewReader(csv))
+		if err == nil || !strings.Contains(err.Error(), expectErr) {
+			TFailf(t, `LoadCSV(%q) returned error %v`, csv, err)
+		}
+		_, err = rt.Rate("EUR", "USD", rtblDate("2024-12-31"))
+		TTrue(t, err != nil)
+	}
+	test("2024-01-01,EUR,USD,1.1\n2024-01-02,EUR,USD\n", EFailedReading)
+	test("2024-01-01,EUR,USD,1.1\n2024-01-02,EUR,USD,x\n", "rate 2:")
+	test("2024-01-01,EUR,USD,1.1\n2024-13-01,EUR,USD,1\n", "rate 2:")
+	test("date,from,to,rate\n2024-01-01,EUR,USD,-1\n", "rate 2:")
+	test("2024-01-01,EUR,USD,1.1\n2024-01-02,EUR,EUR,1\n", "itself")
+	test("2024-01-01,EUR,USD,1.1\n2024-01-02,,USD,1\n", "blank")
+} //                                                Test_rtbl_RateTable_LoadCSV_
+
+// go test --run Test_rtbl_RateTable_LoadJSON_
+func Test_rtbl_RateTable_LoadJSON_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) LoadJSON(r io.Reader) error
+	//
+	var (
+		fromCSV  = rtblTable(t)
+		fromJSON RateTable
+	)
+	err := fromJSON.LoadJSON(strings.NewReader(rtblFixtureJSON))
+	TEqual(t, err, nil)
+	for _, date := range []string{"2024-01-01", "2024-02-15", "2024-03-01"} {
+		for _, pair := range [][2]string{
+			{"EUR", "USD"}, {"USD", "EUR"}, {"USD", "JPY"}, {"GBP", "USD"},
+		} {
+			r1, err1 := fromCSV.Rate(pair[0], pair[1], rtblDate(date))
+			r2, err2 := fromJSON.Rate(pair[0], pair[1], rtblDate(date))
+			TEqual(t, err1, err2)
+			if err1 == nil {
+				TEqual(t, r1.RatString(), r2.RatString())
+			}
+		}
+	}
+	err = fromJSON.LoadJSON(strings.NewReader(`{"rates": []}`))
+	TTrue(t, err != nil)
+	err = fromJSON.LoadJSON(strings.NewReader(
+		`[{"date": "2024-01-01", "from": "EUR", "to": "USD", "rate": 0}]`))
+	TTrue(t, err != nil)
+} //                                               Test_rtbl_RateTable_LoadJSON_
+
+// go test --run Test_rtbl_RateTable_Rate_
+func Test_rtbl_RateTable_Rate_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) Rate(from, to string, date time.Time) (*big.Rat, error)
+	//
+	rt := rtblTable(t)
+	test := func(from, to, date, expect string) {
+		got, err := rt.Rate(from, to, rtblDate(date))
+		if err != nil || got.RatString() != expect {
+			TFailf(t, `Rate(%s, %s, %s) returned %v, %v instead of %s`,
+				from, to, date, got, err, expect)
+		}
+	}
+	test("EUR", "USD", "2024-01-01", "11/10")
+	test("EUR", "USD", "2024-02-29", "27/25")
+	test("USD", "EUR", "2024-01-01", "10/11")
+	//
+	// the later of the direct and the inverse rate is used
+	test("EUR", "USD", "2024-03-01", "10/9")
+	test("USD", "EUR", "2024-03-01", "9/10")
+	//
+	// the time of day and location don't matter
+	{
+		loc := time.FixedZone("UTC-10", -10*60*60)
+		got, _ := rt.Rate("EUR", "USD", time.Date(2024, 2, 1, 23, 0, 0, 0, loc))
+		TEqual(t, got.RatString(), "27/25")
+	}
+	// triangulation
+	rt.Base = "USD"
+	test("EUR", "JPY", "2024-01-01", "165")
+	test("JPY", "EUR", "2024-01-01", "1/165")
+	test("GBP", "EUR", "2024-02-01", "125/108")
+	//
+	// the returned rate can be changed without affecting the table
+	{
+		r, _ := rt.Rate("EUR", "USD", rtblDate("2024-01-01"))
+		r.SetInt64(5)
+		test("EUR", "USD", "2024-01-01", "11/10")
+	}
+	// errors
+	for _, args := range [][3]string{
+		{"EUR", "USD", "2023-12-31"}, {"", "USD", "2024-01-01"},
+		{"EUR", "CHF", "2024-01-01"}, {"GBP", "JPY", "2024-01-14"},
+	} {
+		got, err := rt.Rate(args[0], args[1], rtblDate(args[2]))
+		TTrue(t, got == nil)
+		TTrue(t, err != nil)
+	}
+	var nilTable *RateTable
+	_, err := nilTable.Rate("EUR", "USD", rtblDate("2024-01-01"))
+	TTrue(t, err != nil)
+} //                                                   Test_rtbl_RateTable_Rate_
+
+// go test --run Test_rtbl_RateTable_Set_
+func Test_rtbl_RateTable_Set_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob *RateTable) Set(
+	//     from, to string, date time.Time, rate interface{},
+	// ) error
+	//
+	var (
+		rt  RateTable
+		day = rtblDate("2024-01-01")
+	)
+	test := func(rate interface{}, expect string) {
+		err := rt.Set("eur", "usd", day, rate)
+		got, _ := rt.Rate("EUR", "USD", day)
+		if err != nil || got.RatString() != expect {
+			TFailf(t, `Set(%#v) gave %v, %v instead of %s`,
+				rate, got, err, expect)
+		}
+	}
+	test("1.0842", "5421/5000")
+	test("1/3", "1/3")
+	test(0.1, "1/10")
+	test(float32(0.1), "1/10")
+	test(float32(1.0842), "5421/5000")
+	test(2, "2")
+	test(CurrencyOf("1.5"), "3/2")
+	//
+	for _, rate := range []interface{}{
+		"0", "-1", "x", nil, 1.5i, float32(math.NaN()),
+	} {
+		TTrue(t, rt.Set("EUR", "USD", day, rate) != nil)
+	}
+	TTrue(t, rt.Set("EUR", "eur", day, 1) != nil)
+	TTrue(t, rt.Set("EUR", " ", day, 1) != nil)
+	//
+	// concurrent use
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			date := day.AddDate(0, 0, i)
+			_ = rt.Set("GBP", "USD", date, i)
+			_, _ = rt.Convert(CurrencyOf(1), "USD", "GBP", date)
+		}(i)
+	}
+	wg.Wait()
+	r, _ := rt.Rate("GBP", "USD", day.AddDate(0, 0, 30))
+	TEqual(t, r.RatString(), "10")
+} //                                                    Test_rtbl_RateTable_Set_
+
+// end
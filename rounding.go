@@ -0,0 +1,182 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                   zr/[rounding.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Rounding Modes:
+//   RoundingMode int
+//   RoundHalfEven
+//   RoundHalfUp
+//   RoundHalfDown
+//   RoundUp
+//   RoundDown
+//   RoundCeiling
+//   RoundFloor
+//   (mode RoundingMode) String() string
+//
+// # Helper Functions
+//   roundQuo(num, den *big.Int, mode RoundingMode) *big.Int
+//   roundQuoInt64(num, den int64, mode RoundingMode) int64
+//   roundUpNeeded(
+//       isNegative, isOdd bool, halfCmp int, mode RoundingMode,
+//   ) bool
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// -----------------------------------------------------------------------------
+// # Rounding Modes:
+
+// RoundingMode specifies how to round a number
+// when it has to be reduced to fewer decimal places.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest neighbour, and to the even
+	// neighbour when both are equally near (banker's rounding).
+	// E.g. 2.5 -> 2, 3.5 -> 4, -2.5 -> -2
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest neighbour, and away
+	// from zero when both neighbours are equally near.
+	// E.g. 2.5 -> 3, -2.5 -> -3
+	RoundHalfUp
+
+	// RoundHalfDown rounds to the nearest neighbour, and towards
+	// zero when both neighbours are equally near.
+	// E.g. 2.5 -> 2, 2.6 -> 3, -2.5 -> -2
+	RoundHalfDown
+
+	// RoundUp rounds away from zero. E.g. 2.1 -> 3, -2.1 -> -3
+	RoundUp
+
+	// RoundDown rounds towards zero (truncates). E.g. 2.9 -> 2, -2.9 -> -2
+	RoundDown
+
+	// RoundCeiling rounds towards positive infinity.
+	// E.g. 2.1 -> 3, -2.9 -> -2
+	RoundCeiling
+
+	// RoundFloor rounds towards negative infinity.
+	// E.g. 2.9 -> 2, -2.1 -> -3
+	RoundFloor
+)
+
+// String returns the name of the rounding mode, e.g. "RoundHalfEven"
+// and implements the fmt.Stringer interface.
+func (mode RoundingMode) String() string {
+	switch mode {
+	case RoundHalfEven:
+		return "RoundHalfEven"
+	case RoundHalfUp:
+		return "RoundHalfUp"
+	case RoundHalfDown:
+		return "RoundHalfDown"
+	case RoundUp:
+		return "RoundUp"
+	case RoundDown:
+		return "RoundDown"
+	case RoundCeiling:
+		return "RoundCeiling"
+	case RoundFloor:
+		return "RoundFloor"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(mode))
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// roundQuo returns num / den rounded to an integer using the
+// specified rounding mode. den must not be zero.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	var (
+		isNegative = (num.Sign() < 0) != (den.Sign() < 0)
+		twiceRem   = new(big.Int).Abs(r)
+	)
+	twiceRem.Lsh(twiceRem, 1)
+	halfCmp := twiceRem.Cmp(new(big.Int).Abs(den))
+	if roundUpNeeded(isNegative, q.Bit(0) == 1, halfCmp, mode) {
+		if isNegative {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+} //                                                                    roundQuo
+
+// roundQuoInt64 returns num / den rounded to an integer using the
+// specified rounding mode. den must not be zero. The result of
+// MinInt64 / -1 is not representable and is not checked here.
+func roundQuoInt64(num, den int64, mode RoundingMode) int64 {
+	q, r := num/den, num%den
+	if r == 0 {
+		return q
+	}
+	isNegative := (num < 0) != (den < 0)
+	//
+	// compare the remainder to half of the divisor without overflowing:
+	// |r| < |den|, so (|den| - |r|) is always positive
+	absR, absD := uint64(r), uint64(den)
+	if r < 0 {
+		absR = uint64(-r)
+	}
+	if den < 0 {
+		absD = uint64(-den)
+	}
+	halfCmp := 0
+	if rest := absD - absR; absR > rest {
+		halfCmp = 1
+	} else if absR < rest {
+		halfCmp = -1
+	}
+	if roundUpNeeded(isNegative, q%2 != 0, halfCmp, mode) {
+		if isNegative {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+} //                                                               roundQuoInt64
+
+// roundUpNeeded decides if a truncated quotient with a non-zero
+// remainder should be moved away from zero by one unit.
+//
+// isNegative: true if the exact quotient is negative.
+// isOdd: true if the truncated quotient is odd.
+// halfCmp: -1, 0 or 1 if the remainder is less than,
+//          equal to, or greater than half of the divisor.
+func roundUpNeeded(
+	isNegative, isOdd bool, halfCmp int, mode RoundingMode,
+) bool {
+	switch mode {
+	case RoundHalfEven:
+		return halfCmp > 0 || (halfCmp == 0 && isOdd)
+	case RoundHalfUp:
+		return halfCmp >= 0
+	case RoundHalfDown:
+		return halfCmp > 0
+	case RoundUp:
+		return true
+	case RoundDown:
+		return false
+	case RoundCeiling:
+		return !isNegative
+	case RoundFloor:
+		return isNegative
+	}
+	mod.Error(EInvalidArg, "^mode", ":", mode)
+	return false
+} //                                                               roundUpNeeded
+
+// end
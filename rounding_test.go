@@ -0,0 +1,124 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                              zr/[rounding_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Rounding Modes:
+//   Test_rndg_RoundingMode_String_
+//
+// # Helper Functions
+//   Test_rndg_roundQuo_
+//
+// # Test Data
+//   rndgTable = []struct
+
+//  to test all items in rounding.go use:
+//      go test --run Test_rndg_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// # Rounding Modes:
+
+// go test --run Test_rndg_RoundingMode_String_
+func Test_rndg_RoundingMode_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (mode RoundingMode) String() string
+	//
+	TEqual(t, RoundHalfEven.String(), "RoundHalfEven")
+	TEqual(t, RoundHalfUp.String(), "RoundHalfUp")
+	TEqual(t, RoundHalfDown.String(), "RoundHalfDown")
+	TEqual(t, RoundUp.String(), "RoundUp")
+	TEqual(t, RoundDown.String(), "RoundDown")
+	TEqual(t, RoundCeiling.String(), "RoundCeiling")
+	TEqual(t, RoundFloor.String(), "RoundFloor")
+	TEqual(t, RoundingMode(99).String(), "RoundingMode(99)")
+} //                                              Test_rndg_RoundingMode_String_
+
+// -----------------------------------------------------------------------------
+// # Helper Functions
+
+// go test --run Test_rndg_roundQuo_
+func Test_rndg_roundQuo_(t *testing.T) {
+	TBegin(t)
+	//
+	// roundQuo(num, den *big.Int, mode RoundingMode) *big.Int
+	// roundQuoInt64(num, den int64, mode RoundingMode) int64
+	//
+	test := func(num, den int64, mode RoundingMode, expect int64) {
+		got := roundQuo(big.NewInt(num), big.NewInt(den), mode)
+		if !got.IsInt64() || got.Int64() != expect {
+			TFailf(t, `roundQuo(%d, %d, %v) returned %v instead of %d`,
+				num, den, mode, got, expect)
+		}
+		got64 := roundQuoInt64(num, den, mode)
+		if got64 != expect {
+			TFailf(t, `roundQuoInt64(%d, %d, %v) returned %d instead of %d`,
+				num, den, mode, got64, expect)
+		}
+	}
+	// the standard rounding table, with each value scaled by 10
+	for _, row := range rndgTable {
+		for i, mode := range rndgModes {
+			test(row.tenths, 10, mode, row.expect[i])
+			test(-row.tenths, -10, mode, row.expect[i])
+		}
+	}
+	// exact division
+	test(100, 10, RoundUp, 10)
+	test(-100, 10, RoundFloor, -10)
+	//
+	// halves with odd divisors can't occur, but thirds must be handled
+	test(2, 3, RoundHalfEven, 1)
+	test(1, 3, RoundHalfEven, 0)
+	test(-2, 3, RoundHalfDown, -1)
+	//
+	// extreme values must not overflow when comparing remainders
+	test(math.MaxInt64, math.MaxInt64-1, RoundHalfUp, 1)
+	test(math.MaxInt64, 2, RoundHalfEven, 4611686018427387904)
+	test(math.MinInt64, 2, RoundHalfEven, -4611686018427387904)
+	test(math.MinInt64+1, math.MaxInt64, RoundUp, -1)
+} //                                                         Test_rndg_roundQuo_
+
+// -----------------------------------------------------------------------------
+// # Test Data
+
+// rndgModes lists the rounding modes in the
+// same order as the columns of rndgTable.
+var rndgModes = []RoundingMode{
+	RoundUp, RoundDown, RoundCeiling, RoundFloor,
+	RoundHalfUp, RoundHalfDown, RoundHalfEven,
+}
+
+// rndgTable is the standard table of rounding results of numbers
+// rounded to an integer using each rounding mode (values are in tenths,
+// so 55 means 5.5). This is the table usually used to specify bank
+// and accounting rounding rules, e.g. in Java's RoundingMode.
+var rndgTable = []struct {
+	tenths int64
+	expect [7]int64
+}{ //           UP  DOWN  CEIL  FLOOR  H_UP  H_DOWN  H_EVEN
+	{55, [7]int64{6, 5, 6, 5, 6, 5, 6}},
+	{25, [7]int64{3, 2, 3, 2, 3, 2, 2}},
+	{16, [7]int64{2, 1, 2, 1, 2, 2, 2}},
+	{11, [7]int64{2, 1, 2, 1, 1, 1, 1}},
+	{10, [7]int64{1, 1, 1, 1, 1, 1, 1}},
+	{-10, [7]int64{-1, -1, -1, -1, -1, -1, -1}},
+	{-11, [7]int64{-2, -1, -1, -2, -1, -1, -1}},
+	{-16, [7]int64{-2, -1, -1, -2, -2, -2, -2}},
+	{-25, [7]int64{-3, -2, -2, -3, -3, -2, -2}},
+	{-55, [7]int64{-6, -5, -5, -6, -6, -5, -6}},
+}
+
+// end
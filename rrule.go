@@ -0,0 +1,638 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                      zr/[rrule.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Types
+//   RRule struct
+//   RRuleDay struct
+//
+// # Functions
+//   ParseRRule(s string) (RRule, error)
+//
+// # Methods (ob RRule)
+//   ) Occurrences(rng DateRange) []time.Time
+//   ) String() string
+//
+// # Methods (ob RRuleDay)
+//   ) String() string
+//
+// # Private Methods/Functions
+//   (ob RRule) expand(from, to, start time.Time) []time.Time
+//   (ob RRule) matches(day, from, to, start time.Time) bool
+//   (ob RRule) periodStart(start time.Time) time.Time
+//   (ob RRule) validate() error
+//   rruleDayOf(s string) (RRuleDay, error)
+//   rruleDays(from, to time.Time) int
+//   rruleFormat(name string, t time.Time) string
+//   rruleInts(s string, min, max int) ([]int, error)
+//   rruleTimeOf(s string, loc *time.Location) (time.Time, error)
+//   rruleWeekdayOf(s string) (time.Weekday, bool)
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleFreqs maps the frequencies supported by RRule to FREQ values.
+var rruleFreqs = map[DateUnit]string{
+	DateUnitDay:   "DAILY",
+	DateUnitWeek:  "WEEKLY",
+	DateUnitMonth: "MONTHLY",
+	DateUnitYear:  "YEARLY",
+}
+
+// rruleWeekdays contains the weekday codes used
+// in BYDAY and WKST, starting from Sunday.
+var rruleWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// -----------------------------------------------------------------------------
+// # Types
+
+// RRule is a recurrence rule as specified by RFC 5545 (iCalendar),
+// which describes a series of dates, such as "the last business day
+// of every month" or "the 15th of every quarter".
+//
+// Use ParseRRule to read a rule like "FREQ=MONTHLY;BYMONTHDAY=15",
+// or create the structure directly. Use Occurrences to get the
+// dates of the series that fall within a date range.
+//
+// Only daily, weekly, monthly and yearly rules are supported,
+// with the INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH,
+// BYSETPOS and WKST parts.
+type RRule struct {
+	// Start is the first date (and time) of the series (DTSTART).
+	// The time of day and time zone of Start apply to all dates in
+	// the series. If Start is zero, the series starts at the
+	// beginning of the range passed to Occurrences.
+	Start time.Time
+
+	// Freq is DateUnitDay, DateUnitWeek, DateUnitMonth or DateUnitYear
+	// for DAILY, WEEKLY, MONTHLY and YEARLY rules.
+	Freq DateUnit
+
+	// Interval is the number of days, weeks, months or years
+	// between periods of the series. Zero means 1.
+	Interval int
+
+	// Count limits the series to the specified number of dates,
+	// if not zero. It can't be used together with Until.
+	Count int
+
+	// Until is the last date (and time) of the series, if not zero.
+	// If Until is at midnight, the whole day is included.
+	Until time.Time
+
+	// ByDay lists the days of the week in each period, optionally
+	// with ordinals: e.g. {time.Friday, -1} is the last Friday.
+	ByDay []RRuleDay
+
+	// ByMonthDay lists the days of the month (1 to 31), or days
+	// from the end of the month if negative: -1 is the last day.
+	ByMonthDay []int
+
+	// ByMonth limits the series to the specified months.
+	ByMonth []time.Month
+
+	// BySetPos selects dates by position from all the dates matched in
+	// each period: e.g. -1 is the last. Positions start from 1.
+	BySetPos []int
+
+	// WeekStart is the first day of the week (WKST), which only
+	// changes weekly rules with an interval. Note that the zero
+	// value is Sunday, while ParseRRule uses Monday by default.
+	WeekStart time.Weekday
+} //                                                                       RRule
+
+// RRuleDay is a day of the week in the BYDAY part of a recurrence rule.
+type RRuleDay struct {
+	Weekday time.Weekday
+
+	// N is zero for every Weekday of the period, 1 for the first,
+	// 2 for the second, etc. or -1 for the last, -2 for the
+	// second-last, etc. Only monthly and yearly rules can use N.
+	N int
+} //                                                                    RRuleDay
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// ParseRRule reads a recurrence rule written as specified by RFC 5545,
+// for example "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1" (the
+// last business day of every month). The rule can be preceded by
+// "RRULE:" and by a DTSTART line, which sets the Start field:
+//
+//	DTSTART:20240115
+//	RRULE:FREQ=MONTHLY;INTERVAL=3
+//
+// DTSTART can be a date, a UTC time like "20240115T090000Z", or
+// a local time like "DTSTART;TZID=Europe/Paris:20240115T090000".
+//
+// Returns an error if the rule is not valid or uses parts that
+// are not supported by RRule. Does not log errors.
+func ParseRRule(s string) (RRule, error) {
+	var (
+		ret   = RRule{WeekStart: time.Monday}
+		rule  string
+		until string
+	)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		upper := strings.ToUpper(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(upper, "DTSTART"):
+			i := strings.Index(line, ":")
+			if i == -1 {
+				return RRule{}, fmt.Errorf("%s DTSTART %q", EInvalid, line)
+			}
+			loc := time.UTC
+			for _, param := range strings.Split(line[:i], ";")[1:] {
+				name, value := param, ""
+				if j := strings.Index(param, "="); j != -1 {
+					name, value = param[:j], param[j+1:]
+				}
+				switch strings.ToUpper(name) {
+				case "TZID":
+					zone, err := time.LoadLocation(value)
+					if err != nil {
+						return RRule{}, fmt.Errorf("%s DTSTART time zone %q",
+							EInvalid, value)
+					}
+					loc = zone
+				case "VALUE":
+				default:
+					return RRule{}, fmt.Errorf("%s DTSTART %q", EInvalid, line)
+				}
+			}
+			start, err := rruleTimeOf(line[i+1:], loc)
+			if err != nil {
+				return RRule{}, err
+			}
+			ret.Start = start
+		case rule != "":
+			return RRule{}, fmt.Errorf("%s RRULE line %q", EInvalid, line)
+		default:
+			rule = strings.TrimPrefix(upper, "RRULE:")
+		}
+	}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(rule, ";") {
+		i := strings.Index(part, "=")
+		if i < 1 || i == len(part)-1 || seen[part[:i]] {
+			return RRule{}, fmt.Errorf("%s RRULE part %q", EInvalid, part)
+		}
+		name, value := part[:i], part[i+1:]
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			found := false
+			for unit, freq := range rruleFreqs {
+				if freq == value {
+					ret.Freq, found = unit, true
+				}
+			}
+			if !found {
+				err = fmt.Errorf("unsupported RRULE frequency %q", value)
+			}
+		case "INTERVAL", "COUNT":
+			n, err2 := strconv.Atoi(value)
+			if err2 != nil || n < 1 {
+				err = fmt.Errorf("%s RRULE part %q", EInvalid, part)
+			} else if name == "INTERVAL" {
+				ret.Interval = n
+			} else {
+				ret.Count = n
+			}
+		case "UNTIL":
+			until = value
+		case "BYDAY":
+			for _, s := range strings.Split(value, ",") {
+				day, err2 := rruleDayOf(s)
+				if err2 != nil {
+					err = err2
+					break
+				}
+				ret.ByDay = append(ret.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			ret.ByMonthDay, err = rruleInts(value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = rruleInts(value, 1, 12)
+			for _, m := range months {
+				ret.ByMonth = append(ret.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			ret.BySetPos, err = rruleInts(value, -366, 366)
+		case "WKST":
+			day, found := rruleWeekdayOf(value)
+			if !found {
+				err = fmt.Errorf("%s RRULE part %q", EInvalid, part)
+			}
+			ret.WeekStart = day
+		default:
+			err = fmt.Errorf("unsupported RRULE part %q", part)
+		}
+		if err != nil {
+			return RRule{}, err
+		}
+	}
+	if !seen["FREQ"] {
+		return RRule{}, fmt.Errorf("%s RRULE %q: no FREQ", EInvalid, rule)
+	}
+	if until != "" {
+		loc := time.UTC
+		if !ret.Start.IsZero() {
+			loc = ret.Start.Location()
+		}
+		t, err := rruleTimeOf(until, loc)
+		if err != nil {
+			return RRule{}, err
+		}
+		ret.Until = t
+	}
+	if err := ret.validate(); err != nil {
+		return RRule{}, err
+	}
+	return ret, nil
+} //                                                                  ParseRRule
+
+// -----------------------------------------------------------------------------
+// # Methods (ob RRule)
+
+// Occurrences returns the dates of the series that fall within the
+// date range, in chronological order. Count is applied from the
+// start of the series, even if the range begins later.
+//
+// If the rule is not valid, logs an error and returns nil.
+func (ob RRule) Occurrences(rng DateRange) []time.Time {
+	if err := ob.validate(); err != nil {
+		mod.Error(err)
+		return nil
+	}
+	if rng.isEmpty() {
+		return nil
+	}
+	var (
+		start    = ob.Start
+		interval = ob.Interval
+		limit    = rng.To
+	)
+	if start.IsZero() {
+		start = rng.From
+	}
+	if interval < 1 {
+		interval = 1
+	}
+	if until := ob.Until; !until.IsZero() {
+		if h, m, sec := until.Clock(); h+m+sec+until.Nanosecond() == 0 {
+			y, m, d := until.Date()
+			until = time.Date(y, m, d+1, 0, 0, 0, 0, start.Location()).
+				Add(-1)
+		}
+		if until.Before(limit) {
+			limit = until
+		}
+	}
+	var (
+		ret   []time.Time
+		count = 0
+		first = ob.periodStart(start)
+	)
+	for i := 0; ; i += interval {
+		from := dateUnitAdd(ob.Freq, first, i)
+		if from.After(limit) || from.Year() > 9999 {
+			break
+		}
+		to := dateUnitAdd(ob.Freq, from, 1).AddDate(0, 0, -1)
+		for _, day := range ob.expand(from, to, start) {
+			date := time.Date(day.Year(), day.Month(), day.Day(),
+				start.Hour(), start.Minute(), start.Second(),
+				start.Nanosecond(), start.Location())
+			if date.Before(start) {
+				continue
+			}
+			if date.After(limit) {
+				return ret
+			}
+			if rng.Contains(date) {
+				ret = append(ret, date)
+			}
+			count++
+			if ob.Count > 0 && count >= ob.Count {
+				return ret
+			}
+		}
+	}
+	return ret
+} //                                                                 Occurrences
+
+// String returns the rule as specified by RFC 5545, e.g.
+// "FREQ=MONTHLY;BYMONTHDAY=15". If Start is specified, the
+// rule is preceded by a DTSTART line and "RRULE:".
+func (ob RRule) String() string {
+	parts := []string{"FREQ=" + rruleFreqs[ob.Freq]}
+	add := func(name string, values []int) {
+		if len(values) == 0 {
+			return
+		}
+		a := make([]string, len(values))
+		for i, n := range values {
+			a[i] = strconv.Itoa(n)
+		}
+		parts = append(parts, name+"="+strings.Join(a, ","))
+	}
+	if ob.Interval > 1 {
+		add("INTERVAL", []int{ob.Interval})
+	}
+	if ob.Count > 0 {
+		add("COUNT", []int{ob.Count})
+	}
+	if !ob.Until.IsZero() {
+		parts = append(parts, rruleFormat("UNTIL=", ob.Until))
+	}
+	months := make([]int, len(ob.ByMonth))
+	for i, m := range ob.ByMonth {
+		months[i] = int(m)
+	}
+	add("BYMONTH", months)
+	add("BYMONTHDAY", ob.ByMonthDay)
+	if len(ob.ByDay) > 0 {
+		a := make([]string, len(ob.ByDay))
+		for i, day := range ob.ByDay {
+			a[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(a, ","))
+	}
+	add("BYSETPOS", ob.BySetPos)
+	if ob.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleWeekdays[ob.WeekStart%7])
+	}
+	ret := strings.Join(parts, ";")
+	if ob.Start.IsZero() {
+		return ret
+	}
+	return rruleFormat("DTSTART:", ob.Start) + "\nRRULE:" + ret
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Methods (ob RRuleDay)
+
+// String returns the day as written in BYDAY, e.g. "MO" or "-1FR".
+func (ob RRuleDay) String() string {
+	ret := rruleWeekdays[ob.Weekday%7]
+	if ob.N != 0 {
+		ret = strconv.Itoa(ob.N) + ret
+	}
+	return ret
+} //                                                                      String
+
+// -----------------------------------------------------------------------------
+// # Private Methods/Functions
+
+// expand returns the days from 'from' to 'to' (a single period of
+// the series) that match the rule, after applying BySetPos.
+func (ob RRule) expand(from, to, start time.Time) []time.Time {
+	var days []time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if ob.matches(day, from, to, start) {
+			days = append(days, day)
+		}
+	}
+	if len(ob.BySetPos) == 0 || len(days) == 0 {
+		return days
+	}
+	selected := make([]bool, len(days))
+	for _, pos := range ob.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(days) + pos
+		}
+		if i >= 0 && i < len(days) {
+			selected[i] = true
+		}
+	}
+	var ret []time.Time
+	for i, day := range days {
+		if selected[i] {
+			ret = append(ret, day)
+		}
+	}
+	return ret
+} //                                                                      expand
+
+// matches returns true if 'day', which is in the period from 'from'
+// to 'to', matches the BYxxx parts of the rule. Without BYDAY or
+// BYMONTHDAY, the day of the month or week of 'start' is used.
+func (ob RRule) matches(day, from, to, start time.Time) bool {
+	if len(ob.ByMonth) > 0 {
+		found := false
+		for _, m := range ob.ByMonth {
+			found = found || m == day.Month()
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(ob.ByMonthDay) > 0 {
+		var (
+			last  = DaysInMonth(day.Year(), day.Month())
+			found = false
+		)
+		for _, n := range ob.ByMonthDay {
+			found = found || n == day.Day() || (n < 0 && last+1+n == day.Day())
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(ob.ByDay) > 0 {
+		// in yearly rules with BYMONTH, ordinals count within the month
+		if ob.Freq == DateUnitYear && len(ob.ByMonth) > 0 {
+			y, m, _ := day.Date()
+			from = time.Date(y, m, 1, 0, 0, 0, 0, day.Location())
+			to = time.Date(y, m, DaysInMonth(y, m), 0, 0, 0, 0, day.Location())
+		}
+		found := false
+		for _, it := range ob.ByDay {
+			found = found || it.Weekday == day.Weekday() && (it.N == 0 ||
+				it.N == rruleDays(from, day)/7+1 ||
+				-it.N == rruleDays(day, to)/7+1)
+		}
+		return found
+	}
+	if len(ob.ByMonthDay) > 0 {
+		return true
+	}
+	switch ob.Freq {
+	case DateUnitWeek:
+		return day.Weekday() == start.Weekday()
+	case DateUnitMonth:
+		return day.Day() == start.Day()
+	case DateUnitYear:
+		return day.Day() == start.Day() &&
+			(len(ob.ByMonth) > 0 || day.Month() == start.Month())
+	}
+	return true
+} //                                                                     matches
+
+// periodStart returns the first day of the period
+// (day, week, month or year) that contains 'start'.
+func (ob RRule) periodStart(start time.Time) time.Time {
+	y, m, d := start.Date()
+	switch ob.Freq {
+	case DateUnitWeek:
+		d -= (int(start.Weekday()) - int(ob.WeekStart) + 7) % 7
+	case DateUnitMonth:
+		d = 1
+	case DateUnitYear:
+		m, d = time.January, 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, start.Location())
+} //                                                                 periodStart
+
+// validate returns an error if the rule is not valid.
+func (ob RRule) validate() error {
+	invalid := func(what string, value interface{}) error {
+		return fmt.Errorf("%s RRule %s: %v", EInvalid, what, value)
+	}
+	if _, found := rruleFreqs[ob.Freq]; !found {
+		return invalid("frequency", ob.Freq)
+	}
+	if ob.Interval < 0 {
+		return invalid("interval", ob.Interval)
+	}
+	if ob.Count < 0 {
+		return invalid("count", ob.Count)
+	}
+	if ob.Count > 0 && !ob.Until.IsZero() {
+		return invalid("count", "can't be used with Until")
+	}
+	for _, it := range ob.ByDay {
+		if it.Weekday < time.Sunday || it.Weekday > time.Saturday {
+			return invalid("weekday", int(it.Weekday))
+		}
+		max := 0
+		switch ob.Freq {
+		case DateUnitMonth:
+			max = 5
+		case DateUnitYear:
+			max = 53
+		}
+		if it.N < -max || it.N > max {
+			return invalid("day", it)
+		}
+	}
+	for _, n := range ob.ByMonthDay {
+		if n == 0 || n < -31 || n > 31 {
+			return invalid("day of the month", n)
+		}
+	}
+	for _, m := range ob.ByMonth {
+		if m < time.January || m > time.December {
+			return invalid("month", int(m))
+		}
+	}
+	for _, n := range ob.BySetPos {
+		if n == 0 || n < -366 || n > 366 {
+			return invalid("position", n)
+		}
+	}
+	if ob.WeekStart < time.Sunday || ob.WeekStart > time.Saturday {
+		return invalid("week start", int(ob.WeekStart))
+	}
+	return nil
+} //                                                                    validate
+
+// rruleDayOf reads a day of the week in BYDAY, e.g. "MO", "2TU" or "-1FR".
+func rruleDayOf(s string) (RRuleDay, error) {
+	if len(s) < 2 {
+		return RRuleDay{}, fmt.Errorf("%s RRULE day %q", EInvalid, s)
+	}
+	day, found := rruleWeekdayOf(s[len(s)-2:])
+	if !found {
+		return RRuleDay{}, fmt.Errorf("%s RRULE day %q", EInvalid, s)
+	}
+	ret := RRuleDay{Weekday: day}
+	if n := s[:len(s)-2]; n != "" {
+		var err error
+		ret.N, err = strconv.Atoi(n)
+		if err != nil || ret.N == 0 || ret.N < -53 || ret.N > 53 {
+			return RRuleDay{}, fmt.Errorf("%s RRULE day %q", EInvalid, s)
+		}
+	}
+	return ret, nil
+} //                                                                  rruleDayOf
+
+// rruleDays returns the number of calendar days from 'from' to 'to'.
+func rruleDays(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+} //                                                                   rruleDays
+
+// rruleFormat formats a DTSTART or UNTIL time, prefixed by 'name':
+// a DTSTART in a named time zone as a local time with TZID, other
+// times at midnight as a date, and any other time in UTC.
+func rruleFormat(name string, t time.Time) string {
+	loc := t.Location().String()
+	if strings.HasPrefix(name, "DTSTART") &&
+		loc != "UTC" && loc != "Local" && loc != "" {
+		return "DTSTART;TZID=" + loc + ":" + t.Format("20060102T150405")
+	}
+	if h, m, s := t.Clock(); h+m+s+t.Nanosecond() == 0 {
+		return name + t.Format("20060102")
+	}
+	return name + t.UTC().Format("20060102T150405Z")
+} //                                                                 rruleFormat
+
+// rruleInts reads a comma-separated list of numbers from 'min' to 'max',
+// excluding zero, e.g. "1,15,-1" in BYMONTHDAY.
+func rruleInts(s string, min, max int) ([]int, error) {
+	var ret []int
+	for _, it := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(it)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("%s RRULE number %q", EInvalid, it)
+		}
+		ret = append(ret, n)
+	}
+	return ret, nil
+} //                                                                   rruleInts
+
+// rruleTimeOf reads a DTSTART or UNTIL value like "20240115",
+// "20240115T090000" (a local time in 'loc') or "20240115T090000Z".
+func rruleTimeOf(s string, loc *time.Location) (time.Time, error) {
+	layout := "20060102"
+	switch {
+	case strings.HasSuffix(s, "Z"):
+		layout, loc = "20060102T150405Z", time.UTC
+	case strings.Contains(s, "T"):
+		layout = "20060102T150405"
+	}
+	ret, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s RRULE time %q", EInvalid, s)
+	}
+	return ret, nil
+} //                                                                 rruleTimeOf
+
+// rruleWeekdayOf returns the day of the week given
+// its code used in BYDAY and WKST, e.g. "MO".
+func rruleWeekdayOf(s string) (time.Weekday, bool) {
+	for i, code := range rruleWeekdays {
+		if strings.ToUpper(s) == code {
+			return time.Weekday(i), true
+		}
+	}
+	return time.Sunday, false
+} //                                                              rruleWeekdayOf
+
+// end
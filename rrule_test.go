@@ -0,0 +1,286 @@
+// -----------------------------------------------------------------------------
+// ZR Library                                                 zr/[rrule_test.go]
+// (c) balarabe@protonmail.com                                      License: MIT
+// -----------------------------------------------------------------------------
+
+package zr
+
+// # Functions
+//   Test_rrul_ParseRRule_
+//
+// # Methods (ob RRule)
+//   Test_rrul_RRule_Occurrences_
+//   Test_rrul_RRule_Occurrences_rfc5545_
+//   Test_rrul_RRule_String_
+
+//  to test all items in rrule.go use:
+//      go test --run Test_rrul_
+//
+//  to generate a test coverage report for the whole module use:
+//      go test -coverprofile cover.out
+//      go tool cover -html=cover.out
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rrulDates returns the dates generated by a rule
+// within a date range, as a space-separated string.
+func rrulDates(t *testing.T, rule, rng string) string {
+	rr, err := ParseRRule(rule)
+	if err != nil {
+		t.Fatalf("ParseRRule(%q) failed: %v", rule, err)
+	}
+	var a []string
+	for _, date := range rr.Occurrences(DateRangeOf(rng)) {
+		a = append(a, date.Format("2006-01-02"))
+	}
+	return strings.Join(a, " ")
+} //                                                                   rrulDates
+
+// -----------------------------------------------------------------------------
+// # Functions
+
+// go test --run Test_rrul_ParseRRule_
+func Test_rrul_ParseRRule_(t *testing.T) {
+	TBegin(t)
+	//
+	// ParseRRule(s string) (RRule, error)
+	//
+	got, err := ParseRRule("RRULE:FREQ=MONTHLY;INTERVAL=3;COUNT=4;" +
+		"BYMONTH=1,4;BYMONTHDAY=1,-1;BYDAY=MO,-1FR,+2TU;BYSETPOS=1,-1;WKST=SU")
+	TEqual(t, err, nil)
+	TEqual(t, got.Freq, DateUnitMonth)
+	TEqual(t, got.Interval, 3)
+	TEqual(t, got.Count, 4)
+	TEqual(t, got.ByMonth, []time.Month{time.January, time.April})
+	TEqual(t, got.ByMonthDay, []int{1, -1})
+	TEqual(t, got.ByDay, []RRuleDay{
+		{time.Monday, 0}, {time.Friday, -1}, {time.Tuesday, 2},
+	})
+	TEqual(t, got.BySetPos, []int{1, -1})
+	TEqual(t, got.WeekStart, time.Sunday)
+	TTrue(t, got.Start.IsZero())
+	//
+	// DTSTART and UNTIL, which is in the time zone of DTSTART
+	got, err = ParseRRule(`
+        DTSTART;TZID=Europe/Paris:20240115T090000
+        rrule:freq=weekly;until=20240331`)
+	TEqual(t, err, nil)
+	TEqual(t, got.Start.Format(time.RFC3339), "2024-01-15T09:00:00+01:00")
+	TEqual(t, got.Until.Format(time.RFC3339), "2024-03-31T00:00:00+01:00")
+	TEqual(t, got.WeekStart, time.Monday)
+	//
+	got, err = ParseRRule("DTSTART:20240115T090000Z\nFREQ=DAILY;" +
+		"UNTIL=20240120T170000Z")
+	TEqual(t, err, nil)
+	TEqual(t, got.Start, time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC))
+	TEqual(t, got.Until, time.Date(2024, 1, 20, 17, 0, 0, 0, time.UTC))
+	//
+	// errors
+	for _, s := range []string{
+		"",
+		"FREQ=HOURLY",
+		"FREQ=QUARTERLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=",
+		"FREQ=DAILY;COUNT=x",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=YEARLY;BYDAY=54MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=MONTHLY;BYSETPOS=367",
+		"FREQ=WEEKLY;WKST=XY",
+		"FREQ=YEARLY;BYWEEKNO=20",
+		"FREQ=DAILY;;COUNT=2",
+		"DTSTART:2024\nFREQ=DAILY",
+		"DTSTART;TZID=Mars/Olympus:20240101T090000\nFREQ=DAILY",
+		"DTSTART;FOO=BAR:20240101\nFREQ=DAILY",
+		"FREQ=DAILY\nFREQ=WEEKLY",
+	} {
+		got, err := ParseRRule(s)
+		if err == nil || got.Freq != 0 || got.WeekStart != 0 {
+			TFailf(t, `ParseRRule(%q) returned %+v, %v`, s, got, err)
+		}
+	}
+} //                                                       Test_rrul_ParseRRule_
+
+// -----------------------------------------------------------------------------
+// # Methods (ob RRule)
+
+// go test --run Test_rrul_RRule_Occurrences_
+func Test_rrul_RRule_Occurrences_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob RRule) Occurrences(rng DateRange) []time.Time
+	//
+	test := func(rule, rng, expect string) {
+		got := rrulDates(t, rule, rng)
+		if got != expect {
+			TFailf(t, "%q in %q returned\n%q instead of\n%q",
+				rule, rng, got, expect)
+		}
+	}
+	// last business day of every month
+	test("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "2024",
+		"2024-01-31 2024-02-29 2024-03-29 2024-04-30 2024-05-31 "+
+			"2024-06-28 2024-07-31 2024-08-30 2024-09-30 2024-10-31 "+
+			"2024-11-29 2024-12-31")
+	//
+	// the 15th of every quarter
+	test("DTSTART:20240115\nRRULE:FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15",
+		"2024", "2024-01-15 2024-04-15 2024-07-15 2024-10-15")
+	test("DTSTART:20240115\nRRULE:FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15",
+		"2024-05 to 2025-01", "2024-07-15 2024-10-15 2025-01-15")
+	//
+	// last day of the month, and months without the 31st
+	test("DTSTART:20240115\nFREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+		"2024", "2024-01-31 2024-02-29 2024-03-31")
+	test("DTSTART:20240131\nFREQ=MONTHLY;COUNT=4",
+		"2024", "2024-01-31 2024-03-31 2024-05-31 2024-07-31")
+	//
+	// Thanksgiving, Friday 13th and February 29
+	test("FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2022 to 2025",
+		"2022-11-24 2023-11-23 2024-11-28 2025-11-27")
+	test("FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "2024 to 2026",
+		"2024-09-13 2024-12-13 2025-06-13 2026-02-13 2026-03-13 2026-11-13")
+	test("DTSTART:20240229\nFREQ=YEARLY", "2024 to 2032",
+		"2024-02-29 2028-02-29 2032-02-29")
+	//
+	// daily, weekly and UNTIL, which includes the whole day
+	test("DTSTART:20240228\nFREQ=DAILY;UNTIL=20240302", "2024",
+		"2024-02-28 2024-02-29 2024-03-01 2024-03-02")
+	test("DTSTART:20240101\nFREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=6",
+		"2024", "2024-01-02 2024-01-04 2024-01-16 2024-01-18 "+
+			"2024-01-30 2024-02-01")
+	test("FREQ=DAILY;BYMONTH=2;BYDAY=SA,SU", "2024",
+		"2024-02-03 2024-02-04 2024-02-10 2024-02-11 2024-02-17 "+
+			"2024-02-18 2024-02-24 2024-02-25")
+	//
+	// without DTSTART, the series begins with the range
+	test("FREQ=WEEKLY", "2024-03",
+		"2024-03-01 2024-03-08 2024-03-15 2024-03-22 2024-03-29")
+	//
+	// COUNT is applied from the start of the series
+	test("DTSTART:20240101\nFREQ=DAILY;COUNT=10", "2024-01-05 to 2024-12-31",
+		"2024-01-05 2024-01-06 2024-01-07 2024-01-08 2024-01-09 2024-01-10")
+	test("DTSTART:20240101\nFREQ=DAILY;COUNT=10", "2025", "")
+	test("DTSTART:20250101\nFREQ=DAILY", "2024", "")
+	//
+	// the time of day and time zone of DTSTART are kept
+	rr, _ := ParseRRule("DTSTART;TZID=Europe/Paris:20240325T090000\n" +
+		"FREQ=WEEKLY;COUNT=2")
+	var a []string
+	for _, date := range rr.Occurrences(DateRangeOf("2024")) {
+		a = append(a, date.Format("2006-01-02 15:04 MST"))
+	}
+	TEqual(t, strings.Join(a, ", "),
+		"2024-03-25 09:00 CET, 2024-04-01 09:00 CEST")
+	//
+	// invalid rules
+	DisableErrors()
+	ec1 := GetErrorCount()
+	TEqual(t, len(RRule{Freq: DateUnitQuarter}.Occurrences(
+		DateRangeOf("2024"))), 0)
+	TEqual(t, len(RRule{Freq: DateUnitDay, ByMonthDay: []int{0}}.Occurrences(
+		DateRangeOf("2024"))), 0)
+	ec2 := GetErrorCount()
+	EnableErrors()
+	TEqual(t, ec2-ec1, 2)
+	TEqual(t, len(RRule{Freq: DateUnitDay}.Occurrences(DateRange{})), 0)
+} //                                                Test_rrul_RRule_Occurrences_
+
+// go test --run Test_rrul_RRule_Occurrences_rfc5545_
+func Test_rrul_RRule_Occurrences_rfc5545_(t *testing.T) {
+	TBegin(t)
+	//
+	// examples from RFC 5545, section 3.8.5.3
+	//
+	test := func(rule, expect string) {
+		got := rrulDates(t, rule, "1997 to 2000")
+		if got != expect {
+			TFailf(t, "%q returned\n%q instead of\n%q", rule, got, expect)
+		}
+	}
+	test("DTSTART:19970902\nFREQ=DAILY;COUNT=5",
+		"1997-09-02 1997-09-03 1997-09-04 1997-09-05 1997-09-06")
+	test("DTSTART:19970902\nFREQ=DAILY;INTERVAL=10;COUNT=5",
+		"1997-09-02 1997-09-12 1997-09-22 1997-10-02 1997-10-12")
+	test("DTSTART:19970902\nFREQ=WEEKLY;INTERVAL=2;WKST=SU;COUNT=4",
+		"1997-09-02 1997-09-16 1997-09-30 1997-10-14")
+	test("DTSTART:19970805\nFREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+		"1997-08-05 1997-08-10 1997-08-19 1997-08-24")
+	test("DTSTART:19970805\nFREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+		"1997-08-05 1997-08-17 1997-08-19 1997-08-31")
+	test("DTSTART:19970905\nFREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+		"1997-09-05 1997-10-03 1997-11-07 1997-12-05 1998-01-02 "+
+			"1998-02-06 1998-03-06 1998-04-03 1998-05-01 1998-06-05")
+	test("DTSTART:19970922\nFREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+		"1997-09-22 1997-10-20 1997-11-17 1997-12-22 1998-01-19 1998-02-16")
+	test("DTSTART:19970928\nFREQ=MONTHLY;BYMONTHDAY=-3;COUNT=6",
+		"1997-09-28 1997-10-29 1997-11-28 1997-12-29 1998-01-29 1998-02-26")
+	test("DTSTART:19970610\nFREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+		"1997-06-10 1997-07-10 1998-06-10 1998-07-10 1999-06-10 "+
+			"1999-07-10 2000-06-10 2000-07-10")
+	test("DTSTART:19970519\nFREQ=YEARLY;BYDAY=20MO;COUNT=3",
+		"1997-05-19 1998-05-18 1999-05-17")
+	test("DTSTART:19970904\nFREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3",
+		"1997-09-04 1997-10-07 1997-11-06")
+	test("DTSTART:19970929\nFREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2;"+
+		"COUNT=7", "1997-09-29 1997-10-30 1997-11-27 1997-12-30 "+
+		"1998-01-29 1998-02-26 1998-03-30")
+	test("DTSTART:19961105\nFREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;"+
+		"BYMONTHDAY=2,3,4,5,6,7,8;COUNT=2", "2000-11-07")
+} //                                        Test_rrul_RRule_Occurrences_rfc5545_
+
+// go test --run Test_rrul_RRule_String_
+func Test_rrul_RRule_String_(t *testing.T) {
+	TBegin(t)
+	//
+	// (ob RRule) String() string
+	// (ob RRuleDay) String() string
+	//
+	test := func(rule, expect string) {
+		rr, err := ParseRRule(rule)
+		if err != nil {
+			TFailf(t, `ParseRRule(%q) failed: %v`, rule, err)
+			return
+		}
+		TEqual(t, rr.String(), expect)
+		//
+		// the output can be parsed again
+		again, err := ParseRRule(rr.String())
+		TEqual(t, err, nil)
+		TEqual(t, again, rr)
+	}
+	test("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1")
+	test("bysetpos=1;byday=+1su,-2sa;bymonth=3;interval=2;freq=yearly",
+		"FREQ=YEARLY;INTERVAL=2;BYMONTH=3;BYDAY=1SU,-2SA;BYSETPOS=1")
+	test("FREQ=WEEKLY;WKST=MO;INTERVAL=1", "FREQ=WEEKLY")
+	test("FREQ=WEEKLY;WKST=SU", "FREQ=WEEKLY;WKST=SU")
+	test("DTSTART:20240115\nFREQ=DAILY;UNTIL=20240131;BYMONTHDAY=-1,1",
+		"DTSTART:20240115\nRRULE:FREQ=DAILY;UNTIL=20240131;BYMONTHDAY=-1,1")
+	test("DTSTART:20240115T090000Z\nFREQ=DAILY;COUNT=3",
+		"DTSTART:20240115T090000Z\nRRULE:FREQ=DAILY;COUNT=3")
+	test("DTSTART;TZID=Europe/Paris:20240115T090000\n"+
+		"FREQ=DAILY;UNTIL=20240120T170000Z",
+		"DTSTART;TZID=Europe/Paris:20240115T090000\n"+
+			"RRULE:FREQ=DAILY;UNTIL=20240120T170000Z")
+	//
+	TEqual(t, RRule{Freq: DateUnitDay}.String(), "FREQ=DAILY;WKST=SU")
+	TEqual(t, RRuleDay{time.Friday, -1}.String(), "-1FR")
+	TEqual(t, RRuleDay{Weekday: time.Sunday}.String(), "SU")
+} //                                                     Test_rrul_RRule_String_
+
+// end